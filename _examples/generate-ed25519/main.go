@@ -1,7 +1,6 @@
 package main
 
 import (
-	"crypto/ed25519"
 	"io/ioutil"
 	"log"
 
@@ -9,22 +8,28 @@ import (
 )
 
 func main() {
-	pub, priv, err := generateEdDSA()
+	privateKey, publicKey, err := jwt.GenerateEdDSA()
 	if err != nil {
 		log.Fatal(err)
 	}
 
+	priv, err := jwt.MarshalPrivateKeyEdDSA(privateKey)
+	if err != nil {
+		log.Fatalf("ed25519: private: marshal: %v", err)
+	}
+
+	pub, err := jwt.MarshalPublicKeyEdDSA(publicKey)
+	if err != nil {
+		log.Fatalf("ed25519: public: marshal: %v", err)
+	}
+
 	err = ioutil.WriteFile("ed25519_private.pem", priv, 0600)
 	if err != nil {
-		log.Fatalf("ed25519: private: write file: %w", err)
+		log.Fatalf("ed25519: private: write file: %v", err)
 	}
 
 	err = ioutil.WriteFile("ed25519_public.pem", pub, 0600)
 	if err != nil {
-		log.Fatalf("ed25519: public: write file: %w", err)
+		log.Fatalf("ed25519: public: write file: %v", err)
 	}
 }
-
-func generateEdDSA() (ed25519.PublicKey, ed25519.PrivateKey, error) {
-	return jwt.GenerateEdDSA()
-}
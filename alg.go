@@ -6,6 +6,7 @@ import (
 	_ "crypto/sha256" // ignore:lint
 	_ "crypto/sha512"
 	"errors"
+	"fmt"
 )
 
 var (
@@ -13,6 +14,10 @@ var (
 	ErrTokenSignature = errors.New("jwt: invalid token signature")
 	// ErrInvalidKey indicates that an algorithm required secret key is not a valid type.
 	ErrInvalidKey = errors.New("jwt: invalid key")
+	// ErrInvalidSignatureLength indicates that the token's signature (base64-decoded)
+	// is not of the length the algorithm produces, e.g. a truncated signature.
+	// It is an ErrTokenSignature too, so errors.Is(err, ErrTokenSignature) still reports true.
+	ErrInvalidSignatureLength = fmt.Errorf("%w: invalid length", ErrTokenSignature)
 )
 
 // Alg represents a signing and verifying algorithm.
@@ -22,6 +27,7 @@ type Alg interface {
 	// Sign should accept the private key given on jwt.Sign and
 	// the base64-encoded header and payload data.
 	// Should return the signature.
+	// The "headerAndPayload" is reused after Sign returns, it must not be retained.
 	Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error)
 	// Verify should verify the JWT "signature" (base64-decoded) against
 	// the header and payload (base64-encoded).
@@ -39,6 +45,32 @@ type AlgParser interface {
 	Parse(private, public []byte) (PrivateKey, PublicKey, error)
 }
 
+// AlgSignatureSizer is an optional interface that an "Alg" can complete
+// so the verify functions reject a signature of a different length
+// with ErrInvalidSignatureLength, before its Verify method is called.
+// All the builtin algorithms, except NONE, complete it.
+type AlgSignatureSizer interface {
+	// ExpectedSignatureSize should return the length of the signature (base64-decoded)
+	// produced by the key pair of the given verification key,
+	// or zero if it is not known, e.g. on an invalid key, so Verify reports the error instead.
+	ExpectedSignatureSize(key PublicKey) int
+}
+
+// checkSignatureSize reports ErrInvalidSignatureLength if the "alg" completes
+// the AlgSignatureSizer and the "signature" is not of the expected length.
+func checkSignatureSize(alg Alg, key PublicKey, signature []byte) error {
+	sizer, ok := alg.(AlgSignatureSizer)
+	if !ok {
+		return nil
+	}
+
+	if size := sizer.ExpectedSignatureSize(key); size > 0 && len(signature) != size {
+		return fmt.Errorf("%w: expected %d bytes but got %d", ErrInvalidSignatureLength, size, len(signature))
+	}
+
+	return nil
+}
+
 // The builtin signing available algorithms.
 // Author's recommendation of choosing the best algorithm for your application:
 // Already work with RSA public and private keys?
@@ -69,6 +101,8 @@ var (
 	//    "name": "Pretty Name",
 	//    "lastpage": "/views/settings"
 	//  }
+	// The unsecured tokens are rejected with ErrNoneAlgorithm,
+	// unless they are verified using the NONE algorithm itself.
 	NONE Alg = &algNONE{}
 	// HMAC-SHA signing algorithms.
 	// Keys should be type of []byte.
@@ -117,11 +151,14 @@ var (
 	// provide a level of security comparable to deterministic approaches.
 	// This way RSASSA-PSS results in a net improvement over PKCS v1.5 signatures
 	//
+	// As the JSON Web Algorithms (RFC 7518, section 3.5) requires,
+	// the salt has the same length as the hash function's output.
+	//
 	// Note that the OpenSSL generates different OIDs to protect
 	// reusing the same key material for different cryptosystems.
-	PS256 Alg = &algRSAPSS{"PS256", &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto, Hash: crypto.SHA256}}
-	PS384 Alg = &algRSAPSS{"PS384", &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto, Hash: crypto.SHA384}}
-	PS512 Alg = &algRSAPSS{"PS512", &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthAuto, Hash: crypto.SHA512}}
+	PS256 Alg = &algRSAPSS{"PS256", &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA256}}
+	PS384 Alg = &algRSAPSS{"PS384", &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA384}}
+	PS512 Alg = &algRSAPSS{"PS512", &rsa.PSSOptions{SaltLength: rsa.PSSSaltLengthEqualsHash, Hash: crypto.SHA512}}
 	// ECDSA signing algorithms.
 	// Sign   key: *ecdsa.PrivateKey
 	// Verify key: *ecdsa.PublicKey (or *ecdsa.PrivateKey with its PublicKey filled)
@@ -157,6 +194,9 @@ var (
 
 	allAlgs = []Alg{
 		NONE,
+		HS256,
+		HS384,
+		HS512,
 		RS256,
 		RS384,
 		RS512,
@@ -0,0 +1,87 @@
+package jwt
+
+import "fmt"
+
+// ErrUnexpectedAlgorithm indicates that none of the `AlgKeys` pairs
+// matches the token's "alg" (and "kid") header field. It is an ErrTokenAlg too.
+var ErrUnexpectedAlgorithm = fmt.Errorf("%w: no matching algorithm and key pair", ErrTokenAlg)
+
+// AlgKey holds an algorithm and the public key which
+// verifies the tokens signed with that algorithm.
+// The optional ID restricts the key to the tokens
+// with the same "kid" header field.
+// See `AlgKeys`.
+type AlgKey struct {
+	Alg Alg
+	Key PublicKey
+	ID  string // optional.
+}
+
+// AlgKeys is a list of acceptable algorithm and public key pairs.
+// Each token is verified by the first pair which matches its "alg"
+// (and its "kid", when the pair has an ID) header field.
+// Tokens that none of the pairs matches fail with ErrUnexpectedAlgorithm.
+//
+// Useful for a zero-downtime algorithm rotation,
+// e.g. during a migration from RS256 to EdDSA
+// where tokens of both algorithms are valid for a period of time.
+// See its `ValidateHeader`, `Verify` and `VerifyToken` methods.
+//
+// Usage:
+//  keys := jwt.AlgKeys{
+//    {Alg: jwt.EdDSA, Key: edPublicKey},
+//    {Alg: jwt.RS256, Key: rsaPublicKey},
+//  }
+//  verifiedToken, err := keys.Verify(token)
+type AlgKeys []AlgKey
+
+// ValidateHeader validates the given json header value (base64 decoded) based on the "keys".
+// AlgKeys structure completes the `HeaderValidator` interface.
+func (keys AlgKeys) ValidateHeader(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+	var h HeaderWithKid
+
+	err := Unmarshal(headerDecoded, &h)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	// If for some reason a specific alg was given by the caller then check that as well.
+	if alg != "" && alg != h.Alg {
+		return nil, nil, nil, ErrTokenAlg
+	}
+
+	for _, key := range keys {
+		if key.Alg == nil || key.Alg.Name() != h.Alg {
+			continue
+		}
+
+		if key.ID != "" && key.ID != h.Kid {
+			continue
+		}
+
+		return key.Alg, key.Key, nil, nil
+	}
+
+	return nil, nil, nil, ErrUnexpectedAlgorithm
+}
+
+// Verify verifies the "token" using the algorithm and the public key
+// of the matching pair. See `Verify` package-level function too.
+func (keys AlgKeys) Verify(token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	return VerifyWithHeaderValidator(nil, nil, token, keys.ValidateHeader, validators...)
+}
+
+// VerifyToken same as `Verify` but it decodes the token's payload (claims)
+// to the "dest" pointer of a struct or map value, like the `VerifyToken` package-level function does.
+func (keys AlgKeys) VerifyToken(token []byte, dest interface{}, validators ...TokenValidator) error {
+	verifiedToken, err := keys.Verify(token, validators...)
+	if err != nil {
+		return withTimeValidationError(verificationTime(validators), token, err)
+	}
+
+	if dest == nil {
+		return nil
+	}
+
+	return verifiedToken.Claims(dest)
+}
@@ -0,0 +1,77 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestAlgKeys(t *testing.T) {
+	edPrivateKey, edPublicKey := MustLoadEdDSA("./_testfiles/ed25519_private_key.pem", "./_testfiles/ed25519_public_key.pem")
+	rsaPrivateKey, rsaPublicKey := MustLoadRSA("./_testfiles/rsa_private_key.pem", "./_testfiles/rsa_public_key.pem")
+
+	keys := AlgKeys{
+		{Alg: EdDSA, Key: edPublicKey},
+		{Alg: HS256, Key: testSecret, ID: "shared"},
+	}
+
+	edToken, err := Sign(EdDSA, edPrivateKey, Claims{Subject: "eddsa"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	hsToken, err := Sign(HS256, testSecret, Claims{Subject: "hs256"}, WithKid("shared"), MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for expected, token := range map[string][]byte{"eddsa": edToken, "hs256": hsToken} {
+		var claims Claims
+		if err = keys.VerifyToken(token, &claims); err != nil {
+			t.Fatalf("[%s] %v", expected, err)
+		}
+
+		if claims.Subject != expected {
+			t.Fatalf("expected subject: %q but got: %q", expected, claims.Subject)
+		}
+	}
+
+	// The pair's ID must match the "kid".
+	otherKidToken, err := Sign(HS256, testSecret, Claims{Subject: "hs256"}, WithKid("other"), MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rsaToken, err := Sign(RS256, rsaPrivateKey, Claims{Subject: "rs256"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, token := range map[string][]byte{"kid": otherKidToken, "alg": rsaToken} {
+		if _, err = keys.Verify(token); !errors.Is(err, ErrUnexpectedAlgorithm) || !errors.Is(err, ErrTokenAlg) {
+			t.Fatalf("[%s] expected error: %v but got: %v", name, ErrUnexpectedAlgorithm, err)
+		}
+	}
+
+	keys = append(AlgKeys{{Alg: RS256, Key: rsaPublicKey}}, keys...)
+	if _, err = keys.Verify(rsaToken); err != nil {
+		t.Fatal(err)
+	}
+
+	// A token of another key fails on the signature of its matching pair.
+	_, otherPrivateKey, err := ed25519.GenerateKey(rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherToken, err := Sign(EdDSA, otherPrivateKey, Claims{Subject: "eddsa"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = keys.Verify(otherToken); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+}
@@ -0,0 +1,58 @@
+package jwt
+
+import "sync"
+
+var (
+	algsMu sync.RWMutex // protects the algs.
+	// algs holds the registered algorithms by their name,
+	// see the `AlgByName` and `RegisterAlg` functions.
+	algs = make(map[string]Alg, len(allAlgs))
+)
+
+func init() {
+	for _, alg := range allAlgs {
+		if alg == NONE { // it must be selected explicitly, never through a configuration.
+			continue
+		}
+
+		algs[alg.Name()] = alg
+	}
+}
+
+// AlgByName returns the registered algorithm of the given "name",
+// the "alg" JWT header field, e.g. "EdDSA", "HS256", "RS256" or "ES256".
+// The name is case-sensitive (RFC 7515, section 4.1.1).
+// It reports false for unknown names.
+// The builtin algorithms are registered by default, except the `NONE`.
+// See `RegisterAlg` to add custom ones.
+//
+// Usage:
+//  alg, ok := jwt.AlgByName(config.Alg)
+//  if !ok {
+//    [handle error...]
+//  }
+//  token, err := jwt.Sign(alg, privateKey, claims)
+func AlgByName(name string) (Alg, bool) {
+	algsMu.RLock()
+	alg, ok := algs[name]
+	algsMu.RUnlock()
+
+	return alg, ok
+}
+
+// RegisterAlg registers a custom algorithm, so `AlgByName` can return it,
+// e.g. the ES256K of the "github.com/kataras/jwt/es256k" package.
+// An algorithm of the same name is replaced.
+// It's safe for concurrent use, although it's usually called on initialization.
+//
+// Usage:
+//  jwt.RegisterAlg(es256k.ES256K)
+func RegisterAlg(alg Alg) {
+	if alg == nil {
+		return
+	}
+
+	algsMu.Lock()
+	algs[alg.Name()] = alg
+	algsMu.Unlock()
+}
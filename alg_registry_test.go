@@ -0,0 +1,67 @@
+package jwt
+
+import (
+	"sync"
+	"testing"
+)
+
+type testCustomAlg struct {
+	Alg
+}
+
+func (testCustomAlg) Name() string { return "HS256-CUSTOM" }
+
+func TestAlgByName(t *testing.T) {
+	for _, alg := range allAlgs {
+		got, ok := AlgByName(alg.Name())
+		if alg == NONE {
+			if ok || got != nil {
+				t.Fatalf("expected the NONE algorithm not to be registered")
+			}
+			continue
+		}
+
+		if !ok || got != alg {
+			t.Fatalf("expected algorithm: %s but got: %v (%v)", alg.Name(), got, ok)
+		}
+	}
+
+	for _, name := range []string{"", "none", "hs256", "eddsa", "HS1024"} {
+		if got, ok := AlgByName(name); ok || got != nil {
+			t.Fatalf("expected unknown algorithm: %q but got: %v", name, got)
+		}
+	}
+
+	custom := testCustomAlg{HS256}
+	RegisterAlg(custom)
+	t.Cleanup(func() {
+		algsMu.Lock()
+		delete(algs, custom.Name())
+		algsMu.Unlock()
+	})
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			if alg, ok := AlgByName("HS256-CUSTOM"); !ok || alg != Alg(custom) {
+				t.Errorf("expected the custom algorithm but got: %v (%v)", alg, ok)
+			}
+		}()
+	}
+	wg.Wait()
+
+	alg, _ := AlgByName("HS256-CUSTOM")
+	token, err := Sign(alg, testSecret, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(alg, testSecret, token); err != nil {
+		t.Fatal(err)
+	}
+
+	RegisterAlg(nil) // no-op.
+}
@@ -0,0 +1,153 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// ErrUnknownIssuer indicates that the "iss" claim of a token
+// does not match any of the registered `Authorities`.
+var ErrUnknownIssuer = errors.New("jwt: unknown issuer")
+
+// Authority holds the verification constraints of a trusted token issuer,
+// e.g. an OpenID Connect provider. See `Authorities`.
+type Authority struct {
+	// Issuer is the expected "iss" claim of the tokens.
+	Issuer string
+	// JWKS fetches the verification keys of the issuer,
+	// the key is selected by the "kid" header field of the token.
+	JWKS *JWKSClient
+	// Algs restricts the accepted algorithms, e.g. jwt.RS256. Optional.
+	// If empty, the algorithm of the matching JSON Web Key is accepted.
+	Algs []Alg
+	// Audience, if not empty, is the expected "aud" claim of the tokens,
+	// e.g. the audience this API is registered with at the issuer. Optional.
+	Audience string
+}
+
+// Authorities is a map which holds the trusted authorities by their issuer.
+// It's useful for multi-tenant APIs and gateways which accept tokens of several issuers.
+// User should initialize the authorities once, not safe for concurrent writes.
+// See its `VerifyToken` method.
+//
+// Usage:
+//  authorities := jwt.NewAuthorities(
+//    &jwt.Authority{Issuer: "https://a.example.com", JWKS: jwt.NewJWKSClient("https://a.example.com/.well-known/jwks.json")},
+//    &jwt.Authority{Issuer: "https://b.example.com", JWKS: jwt.NewJWKSClient(...), Algs: []jwt.Alg{jwt.RS256}, Audience: "my-api"},
+//  )
+//  err := authorities.VerifyToken(token, &claims)
+type Authorities map[string]*Authority
+
+// NewAuthorities returns a new Authorities of the given authorities.
+func NewAuthorities(authorities ...*Authority) Authorities {
+	a := make(Authorities, len(authorities))
+	for _, authority := range authorities {
+		a.Register(authority)
+	}
+
+	return a
+}
+
+// Register registers an authority by its `Issuer`.
+func (a Authorities) Register(authority *Authority) {
+	a[authority.Issuer] = authority
+}
+
+// Get returns the authority based on its issuer.
+func (a Authorities) Get(issuer string) (*Authority, bool) {
+	authority, ok := a[issuer]
+	return authority, ok
+}
+
+// VerifyToken reads the (unverified) "iss" claim of the "token",
+// finds the matching authority and verifies the token with the key of the authority's JWKS
+// and its constraints: the allowed algorithms, the issuer and the audience.
+// Tokens of an unregistered issuer fail with ErrUnknownIssuer.
+// On success, it sets the custom claims to the destination "claimsPtr".
+func (a Authorities) VerifyToken(token []byte, claimsPtr interface{}, validators ...TokenValidator) error {
+	return a.VerifyTokenContext(context.Background(), token, claimsPtr, validators...)
+}
+
+// VerifyTokenContext same as `VerifyToken` but it accepts a context,
+// the keys are fetched with that context and it's passed to the validators too, see `VerifyContext`.
+func (a Authorities) VerifyTokenContext(ctx context.Context, token []byte, claimsPtr interface{}, validators ...TokenValidator) error {
+	authority, err := a.authority(token)
+	if err != nil {
+		return err
+	}
+
+	if authority.Audience != "" {
+		validators = prependValidator(ExpectAudience(authority.Audience), validators)
+	}
+	// The issuer was read before the signature verification, check it again on the verified claims.
+	validators = prependValidator(ExpectIssuer(authority.Issuer), validators)
+
+	verifiedToken, err := VerifyWithHeaderValidatorContext(ctx, nil, nil, token, authority.ValidateHeaderContext, validators...)
+	if err != nil {
+		return withTimeValidationError(verificationTime(validators), token, err)
+	}
+
+	if claimsPtr == nil {
+		return nil
+	}
+
+	return verifiedToken.Claims(claimsPtr)
+}
+
+// authority returns the authority of the unverified "iss" claim of the "token".
+// The payload is not trusted yet: compressed tokens are rejected
+// instead of being inflated before the signature verification.
+func (a Authorities) authority(token []byte) (*Authority, error) {
+	tok, err := Decode(token)
+	if err != nil {
+		return nil, err
+	}
+
+	var header struct {
+		Zip string `json:"zip"`
+	}
+	if err = Unmarshal(tok.Header, &header); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrTokenForm, err)
+	}
+
+	if header.Zip != "" {
+		return nil, fmt.Errorf("%w: unsupported zip: %q", ErrTokenForm, header.Zip)
+	}
+
+	var claims struct {
+		Issuer string `json:"iss"`
+	}
+	if err = Unmarshal(tok.Payload, &claims); err != nil {
+		return nil, fmt.Errorf("%w: payload: %v", ErrTokenForm, err)
+	}
+
+	authority, ok := a.Get(claims.Issuer)
+	if !ok || authority.JWKS == nil {
+		return nil, fmt.Errorf("%w: %q", ErrUnknownIssuer, claims.Issuer)
+	}
+
+	return authority, nil
+}
+
+// ValidateHeaderContext selects the verification key of the authority's JWKS
+// and makes sure its algorithm is one of the allowed `Algs`.
+// It completes the `ContextHeaderValidator` interface.
+func (a *Authority) ValidateHeaderContext(ctx context.Context, alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+	verifyAlg, publicKey, decrypt, err := a.JWKS.ValidateHeaderContext(ctx, alg, headerDecoded)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if len(a.Algs) == 0 {
+		return verifyAlg, publicKey, decrypt, nil
+	}
+
+	for _, allowed := range a.Algs {
+		if allowed.Name() == verifyAlg.Name() {
+			return verifyAlg, publicKey, decrypt, nil
+		}
+	}
+
+	return nil, nil, nil, ErrTokenAlg
+}
@@ -0,0 +1,109 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestAuthorities(t *testing.T) {
+	newIssuer := func(kid string) (ed25519.PrivateKey, *JWKSClient) {
+		privateKey, publicKey, err := GenerateEdDSA()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		jwk, err := MarshalPublicKeyEdDSAToJWK(publicKey, kid)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		set, err := MarshalJWKS(jwk)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.Write(set)
+		}))
+		t.Cleanup(srv.Close)
+
+		return privateKey, NewJWKSClient(srv.URL)
+	}
+
+	privateKeyA, jwksA := newIssuer("a")
+	privateKeyB, jwksB := newIssuer("b")
+	privateKeyC, _ := newIssuer("c")
+
+	authorities := NewAuthorities(
+		&Authority{Issuer: "https://a.example.com", JWKS: jwksA},
+		&Authority{Issuer: "https://b.example.com", JWKS: jwksB, Algs: []Alg{EdDSA}, Audience: "my-api"},
+	)
+
+	sign := func(kid string, key ed25519.PrivateKey, claims Claims) []byte {
+		claims.Expiry = Clock().Add(time.Minute).Unix()
+		token, err := Sign(EdDSA, key, claims, WithKid(kid))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	var tests = []struct {
+		name  string
+		token []byte
+		err   error
+	}{
+		{"a", sign("a", privateKeyA, Claims{Issuer: "https://a.example.com", Subject: "a-user"}), nil},
+		{"b", sign("b", privateKeyB, Claims{Issuer: "https://b.example.com", Subject: "b-user", Audience: []string{"my-api"}}), nil},
+		{"b audience", sign("b", privateKeyB, Claims{Issuer: "https://b.example.com", Audience: []string{"other-api"}}), ErrInvalidAudience},
+		// The key of "a" can not sign tokens of "b".
+		{"b by a", sign("a", privateKeyA, Claims{Issuer: "https://b.example.com", Audience: []string{"my-api"}}), ErrUnknownKid},
+		{"b by a kid", sign("b", privateKeyA, Claims{Issuer: "https://b.example.com", Audience: []string{"my-api"}}), ErrTokenSignature},
+		{"unregistered", sign("c", privateKeyC, Claims{Issuer: "https://c.example.com"}), ErrUnknownIssuer},
+		{"no issuer", sign("a", privateKeyA, Claims{}), ErrUnknownIssuer},
+	}
+
+	for _, tt := range tests {
+		var claims Claims
+		err := authorities.VerifyToken(tt.token, &claims)
+		if !errors.Is(err, tt.err) {
+			t.Fatalf("[%s] expected error: %v but got: %v", tt.name, tt.err, err)
+		}
+
+		if tt.err == nil && claims.Subject != tt.name+"-user" {
+			t.Fatalf("[%s] unexpected subject: %q", tt.name, claims.Subject)
+		}
+	}
+
+	// The algorithm of the key must be allowed.
+	authorities.Register(&Authority{Issuer: "https://a.example.com", JWKS: jwksA, Algs: []Alg{RS256}})
+	token := sign("a", privateKeyA, Claims{Issuer: "https://a.example.com"})
+	if err := authorities.VerifyToken(token, nil); !errors.Is(err, ErrTokenAlg) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenAlg, err)
+	}
+
+	// Compressed tokens are not inflated before their signature is verified.
+	token, err := Sign(EdDSA, privateKeyA, Claims{Issuer: "https://a.example.com"}, WithKid("a"), WithCompression())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err = authorities.VerifyToken(token, nil); !errors.Is(err, ErrTokenForm) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenForm, err)
+	}
+
+	// The time claims errors are reported as ValidationError values, like the VerifyToken does.
+	authorities.Register(&Authority{Issuer: "https://a.example.com", JWKS: jwksA})
+	token, err = Sign(EdDSA, privateKeyA, Claims{Issuer: "https://a.example.com", Expiry: Clock().Add(-time.Minute).Unix()}, WithKid("a"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = authorities.VerifyToken(token, nil)
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected a ValidationError of: %v but got: %v", ErrExpired, err)
+	}
+}
@@ -31,7 +31,7 @@ type Blocklist struct {
 	mu sync.RWMutex
 }
 
-var _ TokenValidator = (*Blocklist)(nil)
+var _ ContextTokenValidator = (*Blocklist)(nil)
 
 // NewBlocklist returns a new up and running in-memory Token Blocklist.
 // It accepts the clear every "x" duration. Indeed, this duration
@@ -66,12 +66,23 @@ func defaultGetKey(token []byte, c Claims) string {
 	return BytesToString(token)
 }
 
+// ValidateTokenContext completes the `ContextTokenValidator` interface.
+// It returns the ctx.Err() if the context is canceled,
+// otherwise it calls the `ValidateToken` method.
+func (b *Blocklist) ValidateTokenContext(ctx context.Context, token []byte, c Claims, err error) error {
+	if ctxErr := ctx.Err(); ctxErr != nil {
+		return ctxErr
+	}
+
+	return b.ValidateToken(token, c, err)
+}
+
 // ValidateToken completes the `TokenValidator` interface.
 // Returns ErrBlocked if the "token" was blocked by this Blocklist.
 func (b *Blocklist) ValidateToken(token []byte, c Claims, err error) error {
 	key := b.GetKey(token, c)
 	if err != nil {
-		if err == ErrExpired {
+		if errors.Is(err, ErrExpired) {
 			b.Del(key)
 		}
 
@@ -104,6 +115,24 @@ func (b *Blocklist) InvalidateToken(token []byte, c Claims) error {
 	return nil
 }
 
+// InvalidateID invalidates a token based on its "jti" claim,
+// without the need of the token itself, e.g. to revoke the tokens of compromised credentials.
+// The "expiry" should be the token's expiration time, after that time
+// the entry is removed by the GC. A zero "expiry" blocks the "id" until it's removed manually.
+//
+// Note that the `GetKey` should resolve the tokens by their "jti" (the default behavior).
+func (b *Blocklist) InvalidateID(id string, expiry time.Time) error {
+	if id == "" {
+		return ErrMissing
+	}
+
+	b.mu.Lock()
+	b.entries[id] = NumericDate(expiry)
+	b.mu.Unlock()
+
+	return nil
+}
+
 // Del removes a token based on its "key" from the blocklist.
 func (b *Blocklist) Del(key string) error {
 	b.mu.Lock()
@@ -138,6 +167,7 @@ func (b *Blocklist) Has(key string) (bool, error) {
 }
 
 // GC iterates over all entries and removes expired tokens.
+// Entries without an expiration time are kept.
 // This method is helpful to keep the list size small.
 // Depending on the application, the GC method can be scheduled
 // to called every half or a whole hour.
@@ -148,7 +178,7 @@ func (b *Blocklist) GC() int {
 
 	b.mu.RLock()
 	for token, expiry := range b.entries {
-		if now > expiry {
+		if expiry > 0 && now > expiry { // tokens without expiration are never expired.
 			markedForDeletion = append(markedForDeletion, token)
 		}
 	}
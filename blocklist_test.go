@@ -85,3 +85,54 @@ func TestBlocklist(t *testing.T) {
 		t.Fatalf("expected all entries to be removed but: %d", got)
 	}
 }
+
+func TestBlocklistInvalidateID(t *testing.T) {
+	b := NewBlocklist(0)
+
+	now := time.Now()
+	b.Clock = func() time.Time {
+		return now
+	}
+
+	if err := b.InvalidateID("", time.Time{}); err != ErrMissing {
+		t.Fatalf("expected error: %v but got: %v", ErrMissing, err)
+	}
+
+	b.InvalidateID("expired", now.Add(-time.Minute))
+	b.InvalidateID("valid", now.Add(time.Minute))
+	b.InvalidateID("forever", time.Time{})
+
+	for _, id := range []string{"expired", "valid", "forever"} {
+		token, err := Sign(testAlg, testSecret, Claims{ID: id})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = Verify(testAlg, testSecret, token, b); err != ErrBlocked {
+			t.Fatalf("[%s] expected error: %v but got: %v", id, ErrBlocked, err)
+		}
+	}
+
+	if removed := b.GC(); removed != 1 {
+		t.Fatalf("expected only the expired entry to be removed but got: %d", removed)
+	}
+
+	if has, _ := b.Has("expired"); has {
+		t.Fatalf("expected the expired entry to be removed")
+	}
+
+	for _, id := range []string{"valid", "forever"} {
+		if has, _ := b.Has(id); !has {
+			t.Fatalf("[%s] expected entry to be kept", id)
+		}
+	}
+
+	now = now.Add(2 * time.Minute)
+	if removed := b.GC(); removed != 1 {
+		t.Fatalf("expected the now expired entry to be removed but got: %d", removed)
+	}
+
+	if has, _ := b.Has("forever"); !has {
+		t.Fatalf("expected the entry without expiration to be kept")
+	}
+}
@@ -1,10 +1,15 @@
 package jwt
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 	"time"
+	"unicode"
 )
 
 var (
@@ -14,8 +19,126 @@ var (
 	ErrNotValidYet = errors.New("jwt: token not valid yet")
 	// ErrIssuedInTheFuture indicates that the "iat" claim is in the future.
 	ErrIssuedInTheFuture = errors.New("jwt: token issued in the future")
+	// ErrInconsistentClaims indicates that the "nbf" or the "iat" claim is after the "exp" one,
+	// such a token is never valid, it is probably produced by a broken issuer.
+	ErrInconsistentClaims = errors.New("jwt: inconsistent time claims")
+	// ErrInvalidNumericDate indicates that a time claim ("exp", "nbf" or "iat")
+	// is not a number of seconds nor a string holding one, e.g. "tomorrow".
+	ErrInvalidNumericDate = errors.New("jwt: invalid numeric date")
+	// ErrInvalidClaimType indicates that a registered string claim ("iss", "sub" or "jti")
+	// is a JSON object, an array or a boolean. Numbers are accepted in their string form.
+	ErrInvalidClaimType = errors.New("jwt: invalid claim type")
 )
 
+// ValidationError describes why the claims of a token failed to validate,
+// e.g. by how much a token was expired or which audience was expected.
+// It wraps the sentinel error (e.g. ErrExpired, ErrInvalidAudience),
+// so errors.Is keeps working, use errors.As to read the details.
+//
+// It is returned by the `VerifyToken` function for the time claims
+// and by the `ClockSkew`, `ExpectAudience`, `ExpectIssuer` and `ExpectSubject` validators.
+// Note that the `Verify` function returns the sentinel errors of
+// the builtin time claims validation as they are.
+//
+// Usage:
+//  var vErr *jwt.ValidationError
+//  if errors.As(err, &vErr) {
+//    [vErr.Claim, vErr.Expiry, vErr.Now...]
+//  }
+type ValidationError struct {
+	// Err is the sentinel error, e.g. ErrExpired.
+	Err error
+	// Claim is the name of the claim which failed, e.g. "exp".
+	Claim string
+
+	// The time claims of the token, zero when absent.
+	Expiry    time.Time
+	NotBefore time.Time
+	IssuedAt  time.Time
+	// Now is the current time of the validation, see `Clock`.
+	Now time.Time
+	// Skew is the tolerated clock skew, see `ClockSkew`.
+	Skew time.Duration
+
+	// The expected and the actual "aud" claim, see `ExpectAudience`.
+	ExpectedAudience string
+	ActualAudience   []string
+	// The expected audiences, see `ExpectAnyAudience` and `ExpectAllAudiences`.
+	ExpectedAudiences []string
+	// The expected and the actual "iss" or "sub" claim,
+	// see `ExpectIssuer` and `ExpectSubject`.
+	Expected string
+	Actual   string
+}
+
+// Error implements the error interface.
+func (e *ValidationError) Error() string {
+	switch e.Claim {
+	case "exp":
+		return fmt.Sprintf("%v: exp: %s, now: %s, skew: %s", e.Err, formatTime(e.Expiry), formatTime(e.Now), e.Skew)
+	case "nbf":
+		return fmt.Sprintf("%v: nbf: %s, now: %s, skew: %s", e.Err, formatTime(e.NotBefore), formatTime(e.Now), e.Skew)
+	case "iat":
+		return fmt.Sprintf("%v: iat: %s, now: %s, skew: %s", e.Err, formatTime(e.IssuedAt), formatTime(e.Now), e.Skew)
+	case "aud":
+		if e.ExpectedAudiences != nil {
+			return fmt.Sprintf("%v: expected: %q, got: %q", e.Err, e.ExpectedAudiences, e.ActualAudience)
+		}
+
+		return fmt.Sprintf("%v: expected: %q, got: %q", e.Err, e.ExpectedAudience, e.ActualAudience)
+	default:
+		return fmt.Sprintf("%v: expected: %q, got: %q", e.Err, e.Expected, e.Actual)
+	}
+}
+
+// Unwrap returns the sentinel error.
+func (e *ValidationError) Unwrap() error {
+	return e.Err
+}
+
+// unixTime returns the time of a claim, zero when the claim is absent.
+func unixTime(v int64) time.Time {
+	if v == 0 {
+		return time.Time{}
+	}
+
+	return time.Unix(v, 0)
+}
+
+func formatTime(t time.Time) string {
+	if t.IsZero() {
+		return "-"
+	}
+
+	return t.UTC().Format(time.RFC3339)
+}
+
+// newTimeValidationError returns a ValidationError of a time claims validation "err"
+// (ErrExpired, ErrNotValidYet or ErrIssuedInTheFuture), otherwise it returns the "err" as it's.
+func newTimeValidationError(err error, now time.Time, claims Claims, skew time.Duration) error {
+	var claim string
+	switch err {
+	case ErrExpired:
+		claim = "exp"
+	case ErrNotValidYet:
+		claim = "nbf"
+	case ErrIssuedInTheFuture:
+		claim = "iat"
+	default:
+		return err
+	}
+
+	return &ValidationError{
+		Err:       err,
+		Claim:     claim,
+		Expiry:    unixTime(claims.Expiry),
+		NotBefore: unixTime(claims.NotBefore),
+		IssuedAt:  unixTime(claims.IssuedAt),
+		Now:       now,
+		Skew:      skew,
+	}
+}
+
 // Claims holds the standard JWT claims (payload fields).
 // It can be used to validate the JWT and to sign it.
 // It completes the `SignOption` interface.
@@ -59,54 +182,176 @@ type Claims struct {
 	// disregard the data contained in the JWT. As in the case of the iss and sub claims, this claim
 	// is application specific.
 	Audience Audience `json:"aud,omitempty"`
+	// The OAuth 2.0 scopes, a space-delimited string (or an array of strings) of the "scope"
+	// claim (RFC 8693) or the "scp" one, which some issuers produce instead.
+	// See the `Scopes`, `HasScope`, `HasAllScopes` and `HasAnyScope` methods.
+	Scope Scope `json:"scope,omitempty"`
+	Scp   Scope `json:"scp,omitempty"`
+	// An array of strings (or a single string) of roles, see the `HasRole` method.
+	Roles Roles `json:"roles,omitempty"`
+	// The "cnf" (confirmation) claim of proof-of-possession tokens (RFC 7800),
+	// it binds the token to a key of the client, see `ExpectConfirmationThumbprint`.
+	Confirmation *Confirmation `json:"cnf,omitempty"`
+
+	// the current time of the verification, set only on the claims passed to the token validators.
+	validatedAt time.Time
 }
 
+// currentTime returns the current time of the verification the claims are validated by
+// (see `WithClock`), or the `Clock` package-level variable's one outside of a verification.
+func (c Claims) currentTime() time.Time {
+	if c.validatedAt.IsZero() {
+		return Clock()
+	}
+
+	return c.validatedAt
+}
+
+// claimsSecondChance decodes the claims of non-conformant issuers,
+// e.g. time claims expressed as floats or as numeric strings
+// and string claims expressed as numbers.
 type claimsSecondChance struct {
-	NotBefore json.Number `json:"nbf,omitempty"`
-	IssuedAt  json.Number `json:"iat,omitempty"`
-	Expiry    json.Number `json:"exp,omitempty"`
-	ID        string      `json:"jti,omitempty"`
-	OriginID  string      `json:"origin_jti,omitempty"`
-	Issuer    interface{} `json:"iss,omitempty"`
-	Subject   interface{} `json:"sub,omitempty"`
-	Audience  Audience    `json:"aud,omitempty"`
-}
-
-func (c claimsSecondChance) toClaims() Claims {
-	nbf, _ := c.NotBefore.Float64() // some authorities generates floats for unix timestamp (1-35 seconds), with the leeway of 1 minute we really don't care.
-	iat, _ := c.IssuedAt.Float64()
-	exp, _ := c.Expiry.Float64()
-
-	return Claims{
-		NotBefore: int64(nbf),
-		IssuedAt:  int64(iat),
-		Expiry:    int64(exp),
-		ID:        c.ID,
+	NotBefore numericDate     `json:"nbf,omitempty"`
+	IssuedAt  numericDate     `json:"iat,omitempty"`
+	Expiry    numericDate     `json:"exp,omitempty"`
+	ID        json.RawMessage `json:"jti,omitempty"`
+	OriginID  string          `json:"origin_jti,omitempty"`
+	Issuer    json.RawMessage `json:"iss,omitempty"`
+	Subject   json.RawMessage `json:"sub,omitempty"`
+	Audience  Audience        `json:"aud,omitempty"`
+	Scope     Scope           `json:"scope,omitempty"`
+	Scp       Scope           `json:"scp,omitempty"`
+	Roles     Roles           `json:"roles,omitempty"`
+	// Confirmation holds the "cnf" claim.
+	Confirmation *Confirmation `json:"cnf,omitempty"`
+}
+
+// toClaims converts the claims to the standard ones.
+// On an ErrInvalidClaimType error the rest of the claims are still converted.
+func (c claimsSecondChance) toClaims() (Claims, error) {
+	claims := Claims{
+		NotBefore: int64(c.NotBefore),
+		IssuedAt:  int64(c.IssuedAt),
+		Expiry:    int64(c.Expiry),
 		OriginID:  c.OriginID,
-		Issuer:    getStr(c.Issuer),
-		Subject:   getStr(c.Subject),
 		Audience:  c.Audience,
+		Scope:     c.Scope,
+		Scp:       c.Scp,
+		Roles:     c.Roles,
+
+		Confirmation: c.Confirmation,
+	}
+
+	var err error
+	for _, field := range []struct {
+		name  string
+		value json.RawMessage
+		dest  *string
+	}{
+		{"jti", c.ID, &claims.ID},
+		{"iss", c.Issuer, &claims.Issuer},
+		{"sub", c.Subject, &claims.Subject},
+	} {
+		var fieldErr error
+		if *field.dest, fieldErr = stringClaim(field.name, field.value); fieldErr != nil && err == nil {
+			err = fieldErr
+		}
 	}
+
+	return claims, err
 }
 
-func getStr(v interface{}) string {
-	if v == nil {
-		return ""
+// stringClaim returns the string form of the registered string claim's raw JSON "value",
+// e.g. "123" of a numeric "sub" claim. A null or absent value results to an empty string.
+// Objects, arrays and booleans fail with an ErrInvalidClaimType error which names the claim.
+func stringClaim(name string, value json.RawMessage) (string, error) {
+	value = bytes.TrimSpace(value)
+	if len(value) == 0 || string(value) == "null" {
+		return "", nil
+	}
+
+	switch value[0] {
+	case '"':
+		var s string
+		err := json.Unmarshal(value, &s)
+		return s, err
+	case '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return string(value), nil // as written by the issuer, e.g. no float64 rounding of large ids.
+	case '{':
+		return "", fmt.Errorf("%w: %s: expected a string but got an object", ErrInvalidClaimType, name)
+	case '[':
+		return "", fmt.Errorf("%w: %s: expected a string but got an array", ErrInvalidClaimType, name)
+	default:
+		return "", fmt.Errorf("%w: %s: expected a string but got: %s", ErrInvalidClaimType, name, value)
+	}
+}
+
+// numericDate is a time claim of the `claimsSecondChance`, seconds since epoch.
+// Some authorities generate floats for unix timestamps, they are truncated to seconds,
+// others generate numeric strings, e.g. "1700000000".
+type numericDate int64
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It accepts integers, floats and strings holding one of them in the JSON number form,
+// any other value (e.g. "0x1p30", "Inf" or a number out of the int64 range)
+// fails with ErrInvalidNumericDate.
+func (d *numericDate) UnmarshalJSON(data []byte) error {
+	if string(data) == "null" {
+		return nil
+	}
+
+	if len(data) > 1 && data[0] == '"' && data[len(data)-1] == '"' {
+		data = data[1 : len(data)-1]
+	}
+
+	// A JSON number starts with a minus or a digit, unlike the rest of the JSON values,
+	// and the strconv.ParseFloat accepts more forms than the JSON number one, e.g. hex floats.
+	if len(data) == 0 || !(data[0] == '-' || (data[0] >= '0' && data[0] <= '9')) || !json.Valid(data) {
+		return fmt.Errorf("%w: %s", ErrInvalidNumericDate, data)
 	}
 
-	if s, ok := v.(string); ok {
-		return s
-	} else {
-		return fmt.Sprintf("%v", v)
+	f, err := strconv.ParseFloat(string(data), 64)
+	if err != nil || f >= maxNumericDate || f < -maxNumericDate {
+		return fmt.Errorf("%w: %s", ErrInvalidNumericDate, data)
 	}
+
+	*d = numericDate(f)
+	return nil
 }
 
+// maxNumericDate is the first float which does not fit in an int64, 2^63.
+const maxNumericDate = 1 << 63
+
 // Audience represents the "aud" standard JWT claim.
 // See the `Claims` structure for details.
 type Audience []string
 
+// Contains reports whether the "v" is one of the audience values.
+func (aud Audience) Contains(v string) bool {
+	for _, a := range aud {
+		if a == v {
+			return true
+		}
+	}
+
+	return false
+}
+
+// SplitAudience, when set to true, splits a single string "aud" claim
+// on commas and spaces into multiple audience values, e.g. "a b c" and "a,b,c"
+// are decoded as Audience{"a", "b", "c"}, so `ExpectAudience("b")` passes.
+// It's not standard, it's an integration helper for legacy issuers
+// which join their audiences into one string instead of an array.
+// An array of strings is never split.
+// It's not safe to modify it while tokens are verified,
+// set it once, e.g. at the program's initialization.
+//
+// Defaults to false.
+var SplitAudience = false
+
 // UnmarshalJSON implements the json.Unmarshaler interface.
 // The audience is expected to be single string an array of strings.
+// See `SplitAudience` too.
 func (aud *Audience) UnmarshalJSON(data []byte) (err error) {
 	// Fixes #3.
 	if len(data) > 0 {
@@ -115,7 +360,11 @@ func (aud *Audience) UnmarshalJSON(data []byte) (err error) {
 			var audString string
 			err = json.Unmarshal(data, &audString)
 			if err == nil {
-				*aud = []string{audString}
+				if SplitAudience {
+					*aud = strings.FieldsFunc(audString, isAudienceSeparator)
+				} else {
+					*aud = []string{audString}
+				}
 			}
 		case '[': // it's an array of strings.
 			var audStrings []string
@@ -127,6 +376,10 @@ func (aud *Audience) UnmarshalJSON(data []byte) (err error) {
 	return
 }
 
+func isAudienceSeparator(r rune) bool {
+	return r == ',' || unicode.IsSpace(r)
+}
+
 // Age returns the total age of the claims,
 // the result of issued at - expired time.
 func (c Claims) Age() time.Duration {
@@ -140,31 +393,115 @@ func (c Claims) ExpiresAt() time.Time {
 	return time.Unix(c.Expiry, 0)
 }
 
+// NotBeforeTime returns the time this token starts to be valid (round in second).
+// It's a shortcut of time.Unix(c.NotBefore).
+func (c Claims) NotBeforeTime() time.Time {
+	return time.Unix(c.NotBefore, 0)
+}
+
+// IssuedAtTime returns the time this token was issued (round in second).
+// It's a shortcut of time.Unix(c.IssuedAt).
+func (c Claims) IssuedAtTime() time.Time {
+	return time.Unix(c.IssuedAt, 0)
+}
+
+// NumericDate converts a time to the "seconds since epoch" format
+// of the "exp", "nbf" and "iat" claims.
+// A zero time results to zero, so the claim is omitted.
+//
+// Usage:
+//  jwt.Claims{NotBefore: jwt.NumericDate(startsAt)}
+func NumericDate(t time.Time) int64 {
+	if t.IsZero() {
+		return 0
+	}
+
+	return t.Unix()
+}
+
 // Timeleft returns the remaining time to be expired (round in second).
 func (c Claims) Timeleft() time.Duration {
 	return time.Duration(c.Expiry-Clock().Unix()) * time.Second
 	// return c.ExpiresAt().Sub(Clock())
 }
 
+// Valid validates already parsed claims, without a token, a signature or a key,
+// e.g. claims of a token an upstream (e.g. an API gateway) verified already.
+// It runs the exact validation of the `VerifyToken` function:
+// the "exp", "nbf" and "iat" checks and the given validators
+// (e.g. `Leeway`, `ClockSkew`, `ExpectIssuer`, `ExpectAudience`, `WithClaimPredicate`).
+// The time claims validation errors are ValidationError values.
+//
+// The validators which read the token itself, e.g. `ExpectType`,
+// get a nil token and fail, they are not applicable here.
+//
+// Usage:
+//  err := claims.Valid(jwt.ClockSkew(time.Minute), jwt.ExpectIssuer("my-idp"))
+func (c Claims) Valid(validators ...TokenValidator) error {
+	now := verificationTime(validators)
+
+	var payload []byte // the claim predicates and the required claims need the claims as JSON.
+	for _, v := range validators {
+		switch v.(type) {
+		case claimPredicate, requiredClaims:
+		default:
+			continue
+		}
+
+		b, err := Marshal(c)
+		if err != nil {
+			return err
+		}
+
+		payload = b
+		break
+	}
+
+	err := runValidators(context.Background(), now, nil, payload, c, validateClaims(now, c), validators)
+	switch err {
+	case ErrExpired, ErrNotValidYet, ErrIssuedInTheFuture:
+		return newTimeValidationError(err, now, c, 0)
+	default:
+		return err
+	}
+}
+
 // See TokenValidator and its implementations
 // for further validation options.
 func validateClaims(t time.Time, claims Claims) error {
+	return validateClaimsWithSkew(t, claims, 0)
+}
+
+// validateClaimsWithSkew same as validateClaims but it tolerates
+// a "skew" difference between the clocks of the issuer and the current machine.
+// Absent (zero) claims are not validated.
+func validateClaimsWithSkew(t time.Time, claims Claims, skew time.Duration) error {
+	return validateClaimsWithSkews(t, claims, skew, skew, skew)
+}
+
+// validateClaimsWithSkews same as validateClaimsWithSkew
+// but it tolerates a different skew for each one of the "exp", "nbf" and "iat" claims.
+func validateClaimsWithSkews(t time.Time, claims Claims, expSkew, nbfSkew, iatSkew time.Duration) error {
+	if claims.Expiry > 0 && (claims.NotBefore > claims.Expiry || claims.IssuedAt > claims.Expiry) {
+		return ErrInconsistentClaims
+	}
+
 	now := t.Round(time.Second).Unix()
 
 	if claims.NotBefore > 0 {
-		if now < claims.NotBefore {
+		if now+int64(nbfSkew/time.Second) < claims.NotBefore {
 			return ErrNotValidYet
 		}
 	}
 
 	if claims.IssuedAt > 0 {
-		if now < claims.IssuedAt {
+		if now+int64(iatSkew/time.Second) < claims.IssuedAt {
 			return ErrIssuedInTheFuture
 		}
 	}
 
 	if claims.Expiry > 0 {
-		if now > claims.Expiry {
+		if now-int64(expSkew/time.Second) > claims.Expiry {
 			return ErrExpired
 		}
 	}
@@ -206,6 +543,22 @@ func (c Claims) ApplyClaims(dest *Claims) {
 		dest.Audience = v
 		// dest.RawAudience, _ = json.Marshal(v) // lint: ignore
 	}
+
+	if v := c.Scope; len(v) > 0 {
+		dest.Scope = v
+	}
+
+	if v := c.Scp; len(v) > 0 {
+		dest.Scp = v
+	}
+
+	if v := c.Roles; len(v) > 0 {
+		dest.Roles = v
+	}
+
+	if v := c.Confirmation; v != nil {
+		dest.Confirmation = v
+	}
 }
 
 // MaxAge is a SignOption to set the expiration "exp", "iat" JWT standard claims.
@@ -228,6 +581,58 @@ func MaxAge(maxAge time.Duration) SignOptionFunc {
 	}
 }
 
+// SetIssuedAt is a SignOption which sets the "iat" standard claim
+// to the current time of the `Clock` package-level variable.
+//
+// Like the `MaxAge` one, the time sign options override the "iat", "nbf" and "exp"
+// claims the caller may have already set, on the claims value or a previous sign option:
+// the value's fields are replaced, not duplicated.
+//
+// Usage:
+//  token, err := jwt.Sign(jwt.EdDSA, privateKey, claims,
+//    jwt.SetIssuedAt(), jwt.NotBeforeFromNow(5*time.Second), jwt.ExpiresIn(15*time.Minute))
+func SetIssuedAt() SignOptionFunc {
+	return func(c *Claims) {
+		c.IssuedAt = Clock().Unix()
+	}
+}
+
+// NotBeforeFromNow is a SignOption which sets the "nbf" standard claim
+// to the current time plus "d", e.g. to allow propagation before the token can be used.
+// See `SetIssuedAt` too.
+func NotBeforeFromNow(d time.Duration) SignOptionFunc {
+	return func(c *Claims) {
+		c.NotBefore = Clock().Add(d).Unix()
+	}
+}
+
+// ExpiresIn is a SignOption which sets the "exp" standard claim
+// to the current time plus "d". Unlike `MaxAge`, it does not set the "iat" claim.
+// See `SetIssuedAt` too.
+func ExpiresIn(d time.Duration) SignOptionFunc {
+	return func(c *Claims) {
+		c.Expiry = Clock().Add(d).Unix()
+	}
+}
+
+// WithRandomJTI is a SignOption which sets the "jti" (token id) standard claim
+// to a cryptographically random value: 16 bytes of crypto/rand, base64url-encoded.
+// Every token needs a unique "jti" to be blocked by its id, see `Blocklist.InvalidateID`.
+// The generated id is stored to "dest", if it's not nil,
+// so the caller can keep track of the issued tokens.
+//
+// Usage:
+//  var jti string
+//  token, err := jwt.Sign(jwt.EdDSA, privateKey, claims, jwt.WithRandomJTI(&jti), jwt.MaxAge(15*time.Minute))
+func WithRandomJTI(dest *string) SignOptionFunc {
+	return func(c *Claims) {
+		c.ID = string(Base64Encode(MustGenerateRandom(16)))
+		if dest != nil {
+			*dest = c.ID
+		}
+	}
+}
+
 // MaxAgeMap is a helper to set "exp" and "iat" claims to a map claims.
 // Usage:
 // claims := map[string]interface{}{"foo": "bar"}
@@ -260,10 +665,12 @@ func MaxAgeMap(maxAge time.Duration, claims Map) {
 //  })
 //  Sign(alg, key, claims)
 //
-// Merge is automatically called when:
+// The `Sign` function does not call it for the claims of its sign options, e.g.
 //
 //  Sign(alg, key, claims, MaxAge(time.Duration))
 //  Sign(alg, key, claims, Claims{...})
+//
+// as they replace the "claims" fields of the same name instead of duplicating them.
 func Merge(claims interface{}, other interface{}) []byte {
 	claimsB, err := Marshal(claims)
 	if err != nil {
@@ -0,0 +1,95 @@
+package jwt
+
+import (
+	"context"
+	"time"
+)
+
+// TimeSource is the clock interface of the token verification,
+// it returns the current time the time claims are validated against.
+// See `WithClock`.
+type TimeSource interface {
+	Now() time.Time
+}
+
+// ClockFunc is the function shortcut of a TimeSource, e.g. ClockFunc(time.Now).
+// The `Clock` package-level variable is a ClockFunc.
+type ClockFunc func() time.Time
+
+// Now completes the TimeSource interface.
+// It calls itself.
+func (fn ClockFunc) Now() time.Time {
+	return fn()
+}
+
+// FixedClock is a TimeSource which always returns the same time.
+// Useful for deterministic tests.
+//
+// Usage:
+//  jwt.Verify(alg, key, token, jwt.WithClock(jwt.FixedClock(time.Date(2020, 10, 26, 1, 1, 1, 0, time.UTC))))
+type FixedClock time.Time
+
+// Now completes the TimeSource interface.
+func (c FixedClock) Now() time.Time {
+	return time.Time(c)
+}
+
+// WithClock is a TokenValidator which sets the clock of a single token verification,
+// instead of the `Clock` package-level variable,
+// e.g. a trusted time source or a `FixedClock` for testing.
+// It's used by the builtin "exp", "nbf" and "iat" claims validation
+// and the time validators (see `TimeValidatorFunc`), no matter its position.
+//
+// Usage:
+//  verifiedToken, err := jwt.Verify(jwt.HS256, sharedKey, token, jwt.WithClock(myClock), jwt.Leeway(time.Minute))
+func WithClock(c TimeSource) TokenValidator {
+	return clockOption{c}
+}
+
+type clockOption struct {
+	TimeSource
+}
+
+// ValidateToken completes the TokenValidator interface.
+// It respects the previous error.
+func (clockOption) ValidateToken(_ []byte, _ Claims, err error) error {
+	return err
+}
+
+// TimeValidatorFunc is a TokenValidator which accepts the current time of the verification too,
+// the `Clock` package-level variable or the one set by `WithClock`.
+// Look `ClockSkew` and `MaxTokenAge`.
+type TimeValidatorFunc func(now time.Time, token []byte, standardClaims Claims, err error) error
+
+// ValidateToken completes the TokenValidator interface.
+// It calls itself with the current time of the verification the "standardClaims" are validated by,
+// so a wrapped TimeValidatorFunc respects the `WithClock` too,
+// or the `Clock` package-level variable's one outside of a verification.
+func (fn TimeValidatorFunc) ValidateToken(token []byte, standardClaims Claims, err error) error {
+	return fn(standardClaims.currentTime(), token, standardClaims, err)
+}
+
+// verificationTime returns the current time of a verification,
+// the last `WithClock` of the "validators" or the `Clock` package-level variable.
+func verificationTime(validators []TokenValidator) time.Time {
+	for i := len(validators) - 1; i >= 0; i-- {
+		if c, ok := validators[i].(clockOption); ok && c.TimeSource != nil {
+			return c.Now()
+		}
+	}
+
+	return Clock()
+}
+
+// validateTokenContext calls the "validator" with the given context (see `ContextTokenValidator`)
+// or the given current time, when it accepts one of them.
+func validateTokenContext(ctx context.Context, now time.Time, validator TokenValidator, token []byte, standardClaims Claims, err error) error {
+	switch v := validator.(type) {
+	case TimeValidatorFunc:
+		return v(now, token, standardClaims, err)
+	case ContextTokenValidator:
+		return v.ValidateTokenContext(ctx, token, standardClaims, err)
+	default:
+		return validator.ValidateToken(token, standardClaims, err)
+	}
+}
@@ -0,0 +1,64 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithClock(t *testing.T) {
+	issuedAt := time.Date(2020, 10, 26, 1, 1, 1, 0, time.UTC)
+	token, err := Sign(testAlg, testSecret, Claims{
+		IssuedAt: issuedAt.Unix(),
+		Expiry:   issuedAt.Add(time.Hour).Unix(),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The real clock is years after the expiration.
+	if _, err = Verify(testAlg, testSecret, token); err != ErrExpired {
+		t.Fatalf("expected error: %v but got: %v", ErrExpired, err)
+	}
+
+	// A TimeValidatorFunc wrapped by a custom validator respects the WithClock too.
+	wrapped := func(v TokenValidator) TokenValidatorFunc {
+		return func(token []byte, standardClaims Claims, err error) error {
+			return v.ValidateToken(token, standardClaims, err)
+		}
+	}
+
+	var tests = []struct {
+		now        time.Time
+		validators []TokenValidator
+		wantErr    error
+	}{
+		{issuedAt.Add(time.Minute), nil, nil},
+		{issuedAt.Add(-time.Minute), nil, ErrIssuedInTheFuture},
+		{issuedAt.Add(2 * time.Hour), nil, ErrExpired},
+		{issuedAt.Add(time.Hour + 30*time.Second), []TokenValidator{ClockSkew(time.Minute)}, nil},
+		{issuedAt.Add(59 * time.Minute), []TokenValidator{Leeway(2 * time.Minute)}, ErrExpired},
+		{issuedAt.Add(50 * time.Minute), []TokenValidator{Leeway(2 * time.Minute)}, nil},
+		{issuedAt.Add(20 * time.Minute), []TokenValidator{wrapped(MaxTokenAge(15 * time.Minute))}, ErrTokenMaxAgeExceeded},
+		{issuedAt.Add(10 * time.Minute), []TokenValidator{wrapped(MaxTokenAge(15 * time.Minute))}, nil},
+		{issuedAt.Add(20 * time.Minute), []TokenValidator{MaxTokenAge(15 * time.Minute)}, ErrTokenMaxAgeExceeded},
+		{issuedAt.Add(10 * time.Minute), []TokenValidator{MaxTokenAge(15 * time.Minute)}, nil},
+	}
+
+	for i, tt := range tests {
+		// The position of WithClock does not matter.
+		validators := append(tt.validators, WithClock(FixedClock(tt.now)))
+
+		if _, err = Verify(testAlg, testSecret, token, validators...); !errors.Is(err, tt.wantErr) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.wantErr, err)
+		}
+	}
+
+	// Test the current time of the validation error.
+	now := issuedAt.Add(2 * time.Hour)
+	err = VerifyToken(testAlg, testSecret, token, nil, WithClock(ClockFunc(func() time.Time { return now })))
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) || !vErr.Now.Equal(now) {
+		t.Fatalf("expected a validation error of the given clock's time but got: %#+v", err)
+	}
+}
@@ -0,0 +1,95 @@
+package jwt
+
+import (
+	"encoding/json"
+	"fmt"
+)
+
+// ErrConfirmationMismatch indicates that the token's "cnf" claim is missing
+// or it does not bind the expected key, see `ExpectConfirmationThumbprint`.
+// It is an ErrExpected too.
+var ErrConfirmationMismatch = fmt.Errorf("%w: cnf", ErrExpected)
+
+// Confirmation represents the "cnf" (confirmation) claim of
+// proof-of-possession tokens (RFC 7800), e.g. DPoP-bound access tokens (RFC 9449).
+// The key is either embedded as a JSON Web Key ("jwk")
+// or referenced by its JWK Thumbprint ("jkt").
+type Confirmation struct {
+	// The JWK Thumbprint (RFC 7638) of the key, base64url-encoded.
+	JKT string `json:"jkt,omitempty"`
+	// The embedded public key.
+	JWK *JWK `json:"jwk,omitempty"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// The claim is decoded leniently, as it's not required by most verifications:
+// a value which is not an object (e.g. "x") or members of unexpected types
+// are ignored, the `ExpectConfirmationThumbprint` fails on them.
+func (c *Confirmation) UnmarshalJSON(data []byte) error {
+	var members struct {
+		JKT json.RawMessage `json:"jkt"`
+		JWK json.RawMessage `json:"jwk"`
+	}
+	if len(data) == 0 || data[0] != '{' || json.Unmarshal(data, &members) != nil {
+		return nil
+	}
+
+	var confirmation Confirmation
+	if len(members.JKT) > 0 && members.JKT[0] == '"' {
+		_ = json.Unmarshal(members.JKT, &confirmation.JKT)
+	}
+
+	if len(members.JWK) > 0 && members.JWK[0] == '{' {
+		var jwk JWK
+		if json.Unmarshal(members.JWK, &jwk) == nil {
+			confirmation.JWK = &jwk
+		}
+	}
+
+	*c = confirmation
+	return nil
+}
+
+// Thumbprint returns the JWK Thumbprint of the confirmation key:
+// the "jkt" value as it is or the thumbprint of the embedded "jwk".
+func (c *Confirmation) Thumbprint() (string, error) {
+	if c.JKT != "" {
+		return c.JKT, nil
+	}
+
+	if c.JWK == nil {
+		return "", fmt.Errorf("%w: missing jkt or jwk", ErrConfirmationMismatch)
+	}
+
+	return c.JWK.Thumbprint()
+}
+
+// ExpectConfirmationThumbprint is a TokenValidator which makes sure that
+// the token's "cnf" claim binds the key of the given JWK Thumbprint,
+// e.g. the key which signed the DPoP proof of the request.
+// Both the "jkt" and the embedded "jwk" forms of the claim are accepted.
+// A token without a "cnf" claim fails.
+//
+// It returns ErrConfirmationMismatch on validation failure.
+// See the `JWK.Thumbprint` method too.
+//
+// Usage:
+//  verifiedToken, err := Verify(..., ExpectConfirmationThumbprint(proofKeyThumbprint))
+func ExpectConfirmationThumbprint(expectedJKT string) TokenValidatorFunc {
+	return func(token []byte, c Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if expectedJKT == "" || c.Confirmation == nil {
+			return ErrConfirmationMismatch
+		}
+
+		jkt, thumbprintErr := c.Confirmation.Thumbprint()
+		if thumbprintErr != nil || jkt != expectedJKT {
+			return ErrConfirmationMismatch
+		}
+
+		return nil
+	}
+}
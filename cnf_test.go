@@ -0,0 +1,102 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExpectConfirmationThumbprint(t *testing.T) {
+	// RFC 8037, appendix A.3.
+	jkt := "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"
+
+	var tests = []struct {
+		payload string
+		wantErr error
+	}{
+		{`{"cnf":{"jkt":"` + jkt + `"}}`, nil},
+		{`{"cnf":{"jwk":` + testJWKPublicKeyEdDSA + `}}`, nil},
+		{`{"cnf":{"jkt":"0ZcOCORZNYy-DWpqq30jZyJGHTN0d2HglBV3uiguA4I"}}`, ErrConfirmationMismatch},
+		{`{"cnf":{"jwk":{"kty":"OKP","crv":"Ed25519"}}}`, ErrConfirmationMismatch},
+		{`{"cnf":{}}`, ErrConfirmationMismatch},
+		{`{"sub":"kataras"}`, ErrConfirmationMismatch},
+		// Unexpected shapes are ignored.
+		{`{"cnf":"x"}`, ErrConfirmationMismatch},
+		{`{"cnf":["` + jkt + `"]}`, ErrConfirmationMismatch},
+		{`{"cnf":{"jkt":1}}`, ErrConfirmationMismatch},
+		{`{"cnf":{"jkt":"` + jkt + `","jwk":"x"}}`, nil},
+		{`{"cnf":{"jwk":{"kty":1}}}`, ErrConfirmationMismatch},
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, []byte(tt.payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = Verify(testAlg, testSecret, token, ExpectConfirmationThumbprint(jkt)); !errors.Is(err, tt.wantErr) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.wantErr, err)
+		}
+	}
+
+	// The verification does not fail because of a "cnf" claim it does not require.
+	for _, payload := range []string{`{"cnf":"x"}`, `{"cnf":7}`, `{"cnf":{"jkt":[],"jwk":true}}`} {
+		token, err := Sign(testAlg, testSecret, []byte(payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = Verify(testAlg, testSecret, token); err != nil {
+			t.Fatalf("%s: %v", payload, err)
+		}
+	}
+
+	// test the claim on sign.
+	token, err := Sign(testAlg, testSecret, Claims{Subject: "kataras", Confirmation: &Confirmation{JKT: jkt}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token, ExpectConfirmationThumbprint(jkt))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := verifiedToken.StandardClaims.Confirmation; got == nil || got.JKT != jkt {
+		t.Fatalf("expected cnf claim with jkt: %s but got: %#+v", jkt, got)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, ExpectConfirmationThumbprint("")); !errors.Is(err, ErrConfirmationMismatch) {
+		t.Fatalf("expected error: %v but got: %v", ErrConfirmationMismatch, err)
+	}
+}
+
+func TestJWKThumbprint(t *testing.T) {
+	var jwk JWK
+	if err := Unmarshal([]byte(testJWKPublicKeyEdDSA), &jwk); err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := jwk.Thumbprint()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// RFC 8037, appendix A.3.
+	if expected := "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"; got != expected {
+		t.Fatalf("expected thumbprint: %s but got: %s", expected, got)
+	}
+
+	// the optional members do not modify the thumbprint.
+	jwk.Kid, jwk.Use = "my-key", "sig"
+	if other, err := jwk.Thumbprint(); err != nil || other != got {
+		t.Fatalf("expected thumbprint: %s but got: %s (%v)", got, other, err)
+	}
+
+	if _, err = (&JWK{Kty: "RSA", N: "n"}).Thumbprint(); err != ErrInvalidKey {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidKey, err)
+	}
+
+	if _, err = (&JWK{Kty: "oct"}).Thumbprint(); !errors.Is(err, ErrJWKType) {
+		t.Fatalf("expected error: %v but got: %v", ErrJWKType, err)
+	}
+}
@@ -0,0 +1,25 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+)
+
+// SecureCompare reports whether "a" and "b" are equal, in constant time,
+// e.g. to match a presented refresh token against a stored one.
+// Comparing tokens with == or bytes.Equal returns at the first different byte,
+// the response time tells an attacker how many leading bytes were right,
+// so a token can be guessed byte by byte (a timing side channel).
+//
+// Both values are hashed first, so the comparison time does not depend
+// on where the values differ nor on whether their lengths differ,
+// subtle.ConstantTimeCompare returns early on a length mismatch.
+//
+// Usage:
+//  if !jwt.SecureCompare(presentedToken, storedToken) {
+//    [handle error...]
+//  }
+func SecureCompare(a, b []byte) bool {
+	da, db := sha256.Sum256(a), sha256.Sum256(b)
+	return subtle.ConstantTimeCompare(da[:], db[:])&subtle.ConstantTimeEq(int32(len(a)), int32(len(b))) == 1
+}
@@ -0,0 +1,33 @@
+package jwt
+
+import "testing"
+
+func TestSecureCompare(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	other, err := Sign(testAlg, testSecret, Map{"username": "makis"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		a, b []byte
+		ok   bool
+	}{
+		{token, append([]byte(nil), token...), true},
+		{token, other, false},
+		{token, token[:len(token)-1], false},
+		{token, append(append([]byte(nil), token...), 'a'), false},
+		{token, nil, false},
+		{nil, []byte{}, true},
+	}
+
+	for i, tt := range tests {
+		if got := SecureCompare(tt.a, tt.b); got != tt.ok {
+			t.Fatalf("[%d] expected: %v but got: %v", i, tt.ok, got)
+		}
+	}
+}
@@ -0,0 +1,109 @@
+package jwt
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+)
+
+// ErrDecompressedTooLarge indicates that the inflated payload
+// of a compressed token exceeds the `MaxDecompressedSize` limit.
+var ErrDecompressedTooLarge = errors.New("jwt: decompressed payload exceeds the maximum size")
+
+// MaxDecompressedSize is the maximum number of bytes the payload of a compressed
+// ("zip":"DEF") token can be inflated to, protects against decompression bombs.
+// Defaults to 1 MiB.
+var MaxDecompressedSize int64 = 1 << 20
+
+// zipDeflate is the "zip" header value of the DEFLATE (RFC 1951) compression, RFC 7516 section 4.1.3.
+const zipDeflate = "DEF"
+
+type compressionOption struct{}
+
+func (compressionOption) ApplyClaims(*Claims) {}
+
+// WithCompression is a SignOption which DEFLATE-compresses the payload
+// and sets the "zip":"DEF" header field, useful for large claim sets.
+// The `Verify` function inflates the payload of such tokens automatically,
+// up to `MaxDecompressedSize` bytes.
+// When encryption is used, the payload is compressed before it's encrypted.
+//
+// Usage:
+//  token, err := jwt.Sign(jwt.EdDSA, privateKey, claims, jwt.WithCompression())
+func WithCompression() SignOption {
+	return compressionOption{}
+}
+
+// zipHeader is the HeaderSignOption of the `WithCompression`,
+// it sets the "zip" field which is protected from the `WithHeader` sign options.
+func zipHeader(h *Header) error {
+	if h.Extra == nil {
+		h.Extra = make(map[string]interface{})
+	}
+
+	h.Extra["zip"] = zipDeflate
+	return nil
+}
+
+func compressPayload(payload []byte) ([]byte, error) {
+	var buf bytes.Buffer
+
+	w, err := flate.NewWriter(&buf, flate.DefaultCompression)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err = w.Write(payload); err != nil {
+		return nil, err
+	}
+
+	if err = w.Close(); err != nil {
+		return nil, err
+	}
+
+	return buf.Bytes(), nil
+}
+
+// decompressPayload inflates the "payload" if the decoded "header"
+// holds a "zip" field, otherwise it returns the "payload" as it is.
+func decompressPayload(header, payload []byte) ([]byte, error) {
+	if !bytes.Contains(header, []byte(`"zip"`)) {
+		return payload, nil // fast path, the most common case.
+	}
+
+	var h struct {
+		Zip string `json:"zip"`
+	}
+	if err := Unmarshal(header, &h); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", ErrTokenForm, err)
+	}
+
+	return inflate(h.Zip, payload)
+}
+
+func inflate(zip string, payload []byte) ([]byte, error) {
+	switch zip {
+	case "":
+		return payload, nil
+	case zipDeflate:
+	default:
+		return nil, fmt.Errorf("%w: unsupported zip: %q", ErrTokenForm, zip)
+	}
+
+	r := flate.NewReader(bytes.NewReader(payload))
+	defer r.Close()
+
+	b, err := ioutil.ReadAll(io.LimitReader(r, MaxDecompressedSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("%w: zip: %v", ErrTokenForm, err)
+	}
+
+	if int64(len(b)) > MaxDecompressedSize {
+		return nil, ErrDecompressedTooLarge
+	}
+
+	return b, nil
+}
@@ -0,0 +1,142 @@
+package jwt
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignWithCompression(t *testing.T) {
+	scopes := make([]string, 0, 200)
+	for i := 0; i < cap(scopes); i++ {
+		scopes = append(scopes, "read:resource")
+	}
+	claims := Map{"username": "kataras", "scopes": scopes}
+
+	plain, err := Sign(testAlg, testSecret, claims, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := Sign(testAlg, testSecret, claims, WithCompression(), MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(token) >= len(plain) {
+		t.Fatalf("expected the compressed token (%d bytes) to be smaller than the plain one (%d bytes)", len(token), len(plain))
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got struct {
+		Username string   `json:"username"`
+		Scopes   []string `json:"scopes"`
+	}
+	if err = verifiedToken.Claims(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Username != "kataras" || len(got.Scopes) != len(scopes) {
+		t.Fatalf("unexpected claims after the compressed round trip: %#+v", got)
+	}
+
+	if verifiedToken.StandardClaims.Expiry == 0 {
+		t.Fatalf("expected the standard claims to be decoded from the inflated payload")
+	}
+
+	header, _, err := UnsafeDecode(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if zip := header.Get("zip"); zip != "DEF" {
+		t.Fatalf("expected zip header: DEF but got: %v", zip)
+	}
+
+	// The validators of the payload read the inflated one.
+	if _, err = Verify(testAlg, testSecret, token, RequireClaims("username", "scopes")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, RequireClaims("tenant_id")); !errors.Is(err, ErrMissingRequiredClaim) {
+		t.Fatalf("expected error: %v but got: %v", ErrMissingRequiredClaim, err)
+	}
+
+	expired, err := Sign(testAlg, testSecret, Map{"username": "kataras", "exp": time.Now().Add(-time.Minute).Unix()}, WithCompression())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var vErr *ValidationError
+	if err = VerifyToken(testAlg, testSecret, expired, nil); !errors.As(err, &vErr) || !errors.Is(err, ErrExpired) || vErr.Expiry.IsZero() {
+		t.Fatalf("expected a validation error of the expiry time but got: %#+v", err)
+	}
+
+	// Compressed before encryption, inflated after decryption.
+	encrypt, decrypt, err := GCM([]byte("sercrethatmaycontainch@r32chars!"), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err = SignEncrypted(testAlg, testSecret, encrypt, claims, WithCompression())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifiedToken, err = VerifyEncrypted(testAlg, testSecret, decrypt, token); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = verifiedToken.Claims(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Username != "kataras" {
+		t.Fatalf("unexpected claims after the compressed and encrypted round trip: %#+v", got)
+	}
+}
+
+func TestVerifyCompressionBomb(t *testing.T) {
+	// Compresses to a few KiB, inflates to 2 MiB.
+	bomb := []byte(`{"data":"` + strings.Repeat("0", 2<<20) + `"}`)
+
+	token, err := Sign(testAlg, testSecret, bomb, WithCompression())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(token) > 16<<10 {
+		t.Fatalf("expected a small compressed token but got %d bytes", len(token))
+	}
+
+	if _, err = Verify(testAlg, testSecret, token); !errors.Is(err, ErrDecompressedTooLarge) {
+		t.Fatalf("expected error: %v but got: %v", ErrDecompressedTooLarge, err)
+	}
+
+	if _, _, err = UnsafeDecode(token); !errors.Is(err, ErrDecompressedTooLarge) {
+		t.Fatalf("expected error: %v but got: %v", ErrDecompressedTooLarge, err)
+	}
+
+	defer func(max int64) { MaxDecompressedSize = max }(MaxDecompressedSize)
+	MaxDecompressedSize = 4 << 20
+
+	if _, err = Verify(testAlg, testSecret, token); err != nil {
+		t.Fatalf("expected no error with a greater limit but got: %v", err)
+	}
+}
+
+func TestVerifyUnsupportedZip(t *testing.T) {
+	token, err := SignWithHeader(testAlg, testSecret, Map{"username": "kataras"}, Map{"alg": testAlg.Name(), "typ": "JWT", "zip": "GZIP"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token); !errors.Is(err, ErrTokenForm) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenForm, err)
+	}
+}
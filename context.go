@@ -0,0 +1,59 @@
+package jwt
+
+import "context"
+
+type (
+	// ContextTokenValidator is an optional interface of a TokenValidator
+	// which accepts the context of the verification too,
+	// e.g. a Blocklist backed by a remote database.
+	// Look `VerifyContext` and the `Blocklist.ValidateTokenContext` method.
+	ContextTokenValidator interface {
+		TokenValidator
+		// ValidateTokenContext same as ValidateToken but it accepts the
+		// context of the verification, it should return promptly on its cancellation.
+		ValidateTokenContext(ctx context.Context, token []byte, standardClaims Claims, err error) error
+	}
+
+	// ContextHeaderValidator same as `HeaderValidator` but it accepts
+	// the context of the verification too, e.g. to fetch the keys over the network.
+	// Look the `JWKSClient.ValidateHeaderContext` method.
+	ContextHeaderValidator func(ctx context.Context, alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error)
+)
+
+// VerifyContext same as `Verify` but it accepts a context,
+// which is passed to the validators that implement the `ContextTokenValidator` interface.
+// It returns the ctx.Err() if the context is canceled before the verification completes.
+//
+// Example Code:
+//
+//  verifiedToken, err := jwt.VerifyContext(r.Context(), jwt.EdDSA, publicKey, token, blocklist)
+func VerifyContext(ctx context.Context, alg Alg, key PublicKey, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	return verifyToken(ctx, alg, key, nil, token, nil, validators...)
+}
+
+// VerifyTokenContext same as `VerifyToken` but it accepts a context, see `VerifyContext`.
+func VerifyTokenContext(ctx context.Context, alg Alg, key PublicKey, token []byte, dest interface{}, validators ...TokenValidator) error {
+	verifiedToken, err := VerifyContext(ctx, alg, key, token, validators...)
+	if err != nil {
+		return withTimeValidationError(verificationTime(validators), token, err)
+	}
+
+	if dest == nil {
+		return nil
+	}
+
+	return verifiedToken.Claims(dest)
+}
+
+// VerifyWithHeaderValidatorContext same as `VerifyWithHeaderValidator` but it accepts a context
+// which is passed to the "headerValidator" and the context-aware validators, see `VerifyContext`.
+func VerifyWithHeaderValidatorContext(ctx context.Context, alg Alg, key PublicKey, token []byte, headerValidator ContextHeaderValidator, validators ...TokenValidator) (*VerifiedToken, error) {
+	var compareHeaderFunc HeaderValidator
+	if headerValidator != nil {
+		compareHeaderFunc = func(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+			return headerValidator(ctx, alg, headerDecoded)
+		}
+	}
+
+	return verifyToken(ctx, alg, key, nil, token, compareHeaderFunc, validators...)
+}
@@ -0,0 +1,91 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+type testContextValidator struct {
+	got context.Context
+}
+
+func (v *testContextValidator) ValidateToken(token []byte, c Claims, err error) error {
+	return v.ValidateTokenContext(context.Background(), token, c, err)
+}
+
+func (v *testContextValidator) ValidateTokenContext(ctx context.Context, token []byte, c Claims, err error) error {
+	v.got = ctx
+	return err
+}
+
+func TestVerifyContext(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Claims{ID: "id"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type ctxKey struct{}
+	ctx := context.WithValue(context.Background(), ctxKey{}, "value")
+
+	v := new(testContextValidator)
+	if _, err = VerifyContext(ctx, testAlg, testSecret, token, v); err != nil {
+		t.Fatal(err)
+	}
+
+	if v.got == nil || v.got.Value(ctxKey{}) != "value" {
+		t.Fatalf("expected the validator to receive the verification's context")
+	}
+
+	canceledCtx, cancel := context.WithCancel(ctx)
+	cancel()
+
+	if _, err = VerifyContext(canceledCtx, testAlg, testSecret, token); err != context.Canceled {
+		t.Fatalf("expected error: %v but got: %v", context.Canceled, err)
+	}
+
+	b := NewBlocklist(0)
+	if err = b.ValidateTokenContext(canceledCtx, token, Claims{ID: "id"}, nil); err != context.Canceled {
+		t.Fatalf("expected error: %v but got: %v", context.Canceled, err)
+	}
+
+	if err = VerifyTokenContext(ctx, testAlg, testSecret, token, nil, b); err != nil {
+		t.Fatal(err)
+	}
+}
+
+func TestJWKSClientVerifyTokenContext(t *testing.T) {
+	done := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		defer close(done)
+		<-r.Context().Done() // a provider which never responds.
+	}))
+	defer srv.Close()
+
+	client := NewJWKSClient(srv.URL)
+	client.FetchTimeout = 200 * time.Millisecond
+
+	// The keys are fetched before the signature verification.
+	token := []byte("eyJhbGciOiJFZERTQSIsImtpZCI6ImZpcnN0In0.e30.c2lnbmF0dXJl") // {"alg":"EdDSA","kid":"first"}.{}.signature
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	if err := client.VerifyTokenContext(ctx, token, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected error: %v but got: %v", context.DeadlineExceeded, err)
+	}
+
+	if elapsed := time.Since(start); elapsed > 5*time.Second {
+		t.Fatalf("expected to return promptly on cancellation but took: %s", elapsed)
+	}
+
+	select {
+	case <-done: // the request was canceled on its FetchTimeout, nothing leaked.
+	case <-time.After(5 * time.Second):
+		t.Fatalf("expected the request to the provider to be canceled on its FetchTimeout")
+	}
+}
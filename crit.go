@@ -0,0 +1,122 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// ErrUnknownCriticalHeader indicates that the token's "crit" header field
+// lists an extension which is not understood by the verifier (RFC 7515, section 4.1.11).
+// Only the "b64" one (RFC 7797) is understood by default, register more through `UnderstoodCrit`.
+var ErrUnknownCriticalHeader = fmt.Errorf("%w: unknown critical header", ErrTokenForm)
+
+// registeredHeaders are the header parameters of the JWS and JWE specifications,
+// they must not be listed in the "crit" header field.
+var registeredHeaders = []string{
+	"alg", "jku", "jwk", "kid", "x5u", "x5c", "x5t", "x5t#S256",
+	"typ", "cty", "crit", "enc", "zip", "epk", "apu", "apv", "iv", "tag", "p2s", "p2c",
+}
+
+type understoodCritOption []string
+
+// ValidateToken completes the TokenValidator interface.
+// It respects the previous error.
+func (understoodCritOption) ValidateToken(_ []byte, _ Claims, err error) error {
+	return err
+}
+
+// UnderstoodCrit is a TokenValidator which registers the header fields (extensions)
+// that the caller handles, so tokens which list them in their "crit" header field are accepted.
+// A token which marks as critical an extension that is not understood is rejected
+// with an ErrUnknownCriticalHeader error, instead of being processed as if the extension was absent.
+// The "b64" one is always understood.
+//
+// Note that the caller is responsible to process the registered extensions,
+// e.g. through a `HeaderValidator`.
+//
+// Usage:
+//  verifiedToken, err := jwt.Verify(jwt.PS256, publicKey, token, jwt.UnderstoodCrit("http://openbanking.org.uk/iat"))
+func UnderstoodCrit(names ...string) TokenValidator {
+	return understoodCritOption(names)
+}
+
+// understoodCrit returns the extensions of all the `UnderstoodCrit` of the "validators".
+func understoodCrit(validators []TokenValidator) []string {
+	var names []string
+	for _, v := range validators {
+		if o, ok := v.(understoodCritOption); ok {
+			names = append(names, o...)
+		}
+	}
+
+	return names
+}
+
+// checkCritical validates the "crit" field of the (decoded) header:
+// it must be a non-empty array of unique, present and non registered header field names,
+// each one understood ("b64" or one of the "understood").
+// It reports whether the header holds the "b64":false field (RFC 7797) as critical.
+func checkCritical(headerDecoded []byte, understood []string) (bool, error) {
+	if !bytes.Contains(headerDecoded, []byte("crit")) && bytes.IndexByte(headerDecoded, '\\') < 0 {
+		return false, nil // fast path, the field names may be escaped.
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(headerDecoded, &fields); err != nil {
+		return false, fmt.Errorf("%w: %v", ErrMalformedHeader, err)
+	}
+
+	critValue, ok := fields["crit"]
+	if !ok {
+		return false, nil
+	}
+
+	var crit []string
+	if err := json.Unmarshal(critValue, &crit); err != nil || len(crit) == 0 {
+		return false, fmt.Errorf("%w: crit: expected a non-empty array of strings", ErrMalformedHeader)
+	}
+
+	unencoded := false
+	for i, name := range crit {
+		if containsString(registeredHeaders, name) || containsString(crit[:i], name) {
+			return false, fmt.Errorf("%w: crit: %s", ErrMalformedHeader, name)
+		}
+
+		value, ok := fields[name]
+		if !ok {
+			return false, fmt.Errorf("%w: crit: missing %s", ErrMalformedHeader, name)
+		}
+
+		if name == "b64" {
+			var b64 bool
+			if err := json.Unmarshal(value, &b64); err != nil {
+				return false, fmt.Errorf("%w: b64: %v", ErrMalformedHeader, err)
+			}
+
+			unencoded = !b64
+			continue
+		}
+
+		if !containsString(understood, name) {
+			return false, fmt.Errorf("%w: %s", ErrUnknownCriticalHeader, name)
+		}
+	}
+
+	return unencoded, nil
+}
+
+// checkEncodedPayload validates the "crit" header field of a token
+// with a base64url-encoded payload, a "b64":false (unencoded payload) one is rejected.
+func checkEncodedPayload(headerDecoded []byte, understood []string) error {
+	unencoded, err := checkCritical(headerDecoded, understood)
+	if err != nil {
+		return err
+	}
+
+	if unencoded {
+		return fmt.Errorf("%w: unencoded payload (b64), see VerifyUnencoded", ErrTokenForm)
+	}
+
+	return nil
+}
@@ -0,0 +1,94 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestUnderstoodCrit(t *testing.T) {
+	claims := Map{"username": "kataras"}
+
+	var tests = []struct {
+		header     string
+		validators []TokenValidator
+		wantErr    error
+	}{
+		{`{"alg":"HS256","typ":"JWT"}`, nil, nil},
+		{`{"alg":"HS256","crit":["exp"],"exp":1}`, nil, ErrUnknownCriticalHeader},
+		{`{"alg":"HS256","crit":["exp"],"exp":1}`, []TokenValidator{UnderstoodCrit("exp")}, nil},
+		{`{"alg":"HS256","crit":["exp","tenant"],"exp":1,"tenant":"a"}`, []TokenValidator{UnderstoodCrit("exp")}, ErrUnknownCriticalHeader},
+		{`{"alg":"HS256","crit":["exp","tenant"],"exp":1,"tenant":"a"}`, []TokenValidator{UnderstoodCrit("exp"), UnderstoodCrit("tenant")}, nil},
+		// The field names may be escaped.
+		{`{"alg":"HS256","\u0063rit":["exp"],"exp":1}`, nil, ErrUnknownCriticalHeader},
+		// Malformed "crit" fields.
+		{`{"alg":"HS256","crit":[]}`, nil, ErrMalformedHeader},
+		{`{"alg":"HS256","crit":"exp","exp":1}`, nil, ErrMalformedHeader},
+		{`{"alg":"HS256","crit":["alg"]}`, nil, ErrMalformedHeader},
+		{`{"alg":"HS256","crit":["exp"]}`, []TokenValidator{UnderstoodCrit("exp")}, ErrMalformedHeader},
+		{`{"alg":"HS256","crit":["exp","exp"],"exp":1}`, []TokenValidator{UnderstoodCrit("exp")}, ErrMalformedHeader},
+		// The "b64" is understood, an unencoded payload is verified by VerifyUnencoded only.
+		{`{"alg":"HS256","b64":true,"crit":["b64"]}`, nil, nil},
+		{`{"alg":"HS256","b64":false,"crit":["b64"]}`, nil, ErrTokenForm},
+		{`{"alg":"HS256","b64":"false","crit":["b64"]}`, nil, ErrMalformedHeader},
+	}
+
+	for i, tt := range tests {
+		signingInput := append(append(Base64Encode([]byte(tt.header)), '.'), Base64Encode([]byte(`{"username":"kataras"}`))...)
+		signature, err := testAlg.Sign(testSecret, signingInput)
+		if err != nil {
+			t.Fatal(err)
+		}
+		token := append(append(signingInput, '.'), Base64Encode(signature)...)
+
+		if _, err = Verify(testAlg, testSecret, token, tt.validators...); !errors.Is(err, tt.wantErr) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.wantErr, err)
+		}
+
+		if err = VerifyDetached(testAlg, testSecret, signingInput, signature); tt.validators == nil && !errors.Is(err, tt.wantErr) {
+			t.Fatalf("[%d] detached: expected error: %v but got: %v", i, tt.wantErr, err)
+		}
+	}
+
+	// A custom header validator is protected too.
+	keys := make(Keys)
+	keys.Register(testAlg, "api", testSecret, testSecret)
+	token, err := SignWithHeader(testAlg, testSecret, claims, Map{"alg": testAlg.Name(), "kid": "api", "crit": []string{"tenant"}, "tenant": "a"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = keys.VerifyToken(token, nil); !errors.Is(err, ErrUnknownCriticalHeader) {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownCriticalHeader, err)
+	}
+
+	if err = keys.VerifyToken(token, nil, UnderstoodCrit("tenant")); err != nil {
+		t.Fatal(err)
+	}
+
+	// The unencoded payload tokens, e.g. of the UK Open Banking message signing.
+	header := Map{
+		"kid":                           "90210ABAD",
+		"http://openbanking.org.uk/iat": 1501497671,
+		"crit":                          []string{"http://openbanking.org.uk/iat"},
+	}
+	body := []byte(`{"Data":{}}`)
+
+	token, err = SignUnencoded(testAlg, testSecret, body, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyUnencoded(testAlg, testSecret, token, body); !errors.Is(err, ErrUnknownCriticalHeader) {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownCriticalHeader, err)
+	}
+
+	verifiedToken, err := VerifyUnencoded(testAlg, testSecret, token, body, "http://openbanking.org.uk/iat")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedHeader := `{"alg":"HS256","b64":false,"crit":["b64","http://openbanking.org.uk/iat"],"http://openbanking.org.uk/iat":1501497671,"kid":"90210ABAD"}`
+	if string(verifiedToken.Header) != expectedHeader {
+		t.Fatalf("expected header:\n%s\nbut got:\n%s", expectedHeader, verifiedToken.Header)
+	}
+}
@@ -0,0 +1,116 @@
+package jwt
+
+import "errors"
+
+type debugLoggerOption struct {
+	logf func(format string, args ...interface{})
+}
+
+// ValidateToken completes the TokenValidator interface.
+// It respects the previous error.
+func (debugLoggerOption) ValidateToken(_ []byte, _ Claims, err error) error {
+	return err
+}
+
+// WithDebugLogger is a TokenValidator which traces the steps of a single verification
+// to the given "logf" (e.g. the `log.Printf`), to diagnose failures in production:
+// the token form, the algorithm, the signature, the payload, the claims (e.g. "exp")
+// and the validators (e.g. "aud"), each one as "ok" up to the failed one, with its error.
+// It never logs the token, the signature or the key.
+// It's distinct from the `WithObserver`, which is meant for metrics.
+// A verification without it has no tracing overhead at all.
+//
+// Usage:
+//  verifiedToken, err := jwt.Verify(jwt.EdDSA, publicKey, token, jwt.WithDebugLogger(log.Printf))
+func WithDebugLogger(logf func(format string, args ...interface{})) TokenValidator {
+	return debugLoggerOption{logf}
+}
+
+// findDebugLogger returns the logger of the last `WithDebugLogger` option, if any.
+func findDebugLogger(validators []TokenValidator) func(format string, args ...interface{}) {
+	for i := len(validators) - 1; i >= 0; i-- {
+		if o, ok := validators[i].(debugLoggerOption); ok && o.logf != nil {
+			return o.logf
+		}
+	}
+
+	return nil
+}
+
+// The verification steps, in order.
+var debugSteps = []string{"form", "alg", "signature", "payload", "claims", "validators"}
+
+// The indexes of the `debugSteps`.
+const (
+	stepForm = iota
+	stepAlg
+	stepSignature
+	stepPayload
+	stepClaims
+	stepValidators
+)
+
+// verifyTrace records the step a verification reached, see `WithDebugLogger`.
+// A nil *verifyTrace records nothing.
+type verifyTrace struct {
+	step int
+}
+
+func (t *verifyTrace) reach(step int) {
+	if t != nil {
+		t.step = step
+	}
+}
+
+// debugVerify traces the outcome of a verification of the "token",
+// which reached the "step" one, either it failed there or it completed.
+func debugVerify(logf func(format string, args ...interface{}), alg Alg, token []byte, step int, err error) {
+	algName := verifyAlgName(alg, token)
+
+	failed, name := len(debugSteps), ""
+	if err != nil {
+		failed, name = debugStep(step, err)
+	}
+
+	for i, step := range debugSteps[:failed] {
+		if i == stepAlg {
+			logf("jwt: verify: %s %q: ok", step, algName)
+			continue
+		}
+
+		logf("jwt: verify: %s: ok", step)
+	}
+
+	if err != nil {
+		logf("jwt: verify: %s: failed: %v", name, err)
+	}
+}
+
+// debugStep returns the index of the verification step the "err" failed at,
+// the "reached" one, and its name, e.g. the claim name of a claims validation error.
+func debugStep(reached int, err error) (int, string) {
+	var validationErr *ValidationError
+
+	if reached == stepClaims {
+		switch {
+		case errors.Is(err, ErrExpired):
+			return stepClaims, "exp"
+		case errors.Is(err, ErrNotValidYet):
+			return stepClaims, "nbf"
+		case errors.Is(err, ErrIssuedInTheFuture):
+			return stepClaims, "iat"
+		case errors.Is(err, ErrInconsistentClaims), errors.Is(err, ErrInvalidNumericDate),
+			errors.Is(err, ErrInvalidClaimType), err == errPayloadNotJSON:
+			return stepClaims, debugSteps[stepClaims]
+		}
+
+		// A validator accepted the claims (e.g. the `ClockSkew`) and then failed.
+		reached = stepValidators
+	}
+
+	if reached == stepValidators && errors.As(err, &validationErr) && validationErr.Claim != "" {
+		return stepValidators, validationErr.Claim
+	}
+
+	return reached, debugSteps[reached]
+}
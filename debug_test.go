@@ -0,0 +1,112 @@
+package jwt
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWithDebugLogger(t *testing.T) {
+	var lines []string
+	logger := WithDebugLogger(func(format string, args ...interface{}) {
+		lines = append(lines, fmt.Sprintf(format, args...))
+	})
+
+	expired, err := Sign(testAlg, testSecret, Claims{Expiry: Clock().Add(-time.Minute).Unix(), Audience: Audience{"api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	valid, err := Sign(testAlg, testSecret, Claims{Expiry: Clock().Add(time.Minute).Unix(), Audience: Audience{"api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		token      []byte
+		key        []byte
+		validators []TokenValidator
+		expected   []string
+	}{
+		{expired, testSecret, nil, []string{
+			"jwt: verify: form: ok",
+			`jwt: verify: alg "HS256": ok`,
+			"jwt: verify: signature: ok",
+			"jwt: verify: payload: ok",
+			"jwt: verify: exp: failed: " + ErrExpired.Error(),
+		}},
+		{valid, []byte("other"), nil, []string{
+			"jwt: verify: form: ok",
+			`jwt: verify: alg "HS256": ok`,
+			"jwt: verify: signature: failed: " + ErrTokenSignature.Error(),
+		}},
+		{valid, testSecret, []TokenValidator{ExpectAudience("web")}, []string{
+			"jwt: verify: form: ok",
+			`jwt: verify: alg "HS256": ok`,
+			"jwt: verify: signature: ok",
+			"jwt: verify: payload: ok",
+			"jwt: verify: claims: ok",
+			`jwt: verify: aud: failed: jwt: field not match: aud: expected: "web", got: ["api"]`,
+		}},
+		{[]byte("not-a-token"), testSecret, nil, []string{
+			"jwt: verify: form: failed: " + ErrMalformedToken.Error(),
+		}},
+		{valid, testSecret, nil, []string{
+			"jwt: verify: form: ok",
+			`jwt: verify: alg "HS256": ok`,
+			"jwt: verify: signature: ok",
+			"jwt: verify: payload: ok",
+			"jwt: verify: claims: ok",
+			"jwt: verify: validators: ok",
+		}},
+	}
+
+	for i, tt := range tests {
+		lines = lines[:0]
+		_, _ = Verify(testAlg, tt.key, tt.token, append(tt.validators, logger)...)
+
+		if got := strings.Join(lines, "\n"); got != strings.Join(tt.expected, "\n") {
+			t.Fatalf("[%d] expected logs:\n%s\nbut got:\n%s", i, strings.Join(tt.expected, "\n"), got)
+		}
+
+		// Never the token parts or the key.
+		for _, part := range append(bytes.Split(tt.token, sep), tt.key) {
+			if strings.Contains(strings.Join(lines, "\n"), string(part)) {
+				t.Fatalf("[%d] expected the logs to not contain %q", i, part)
+			}
+		}
+	}
+
+	check := func(name string, expected ...string) {
+		t.Helper()
+
+		if got := strings.Join(lines, "\n"); got != strings.Join(expected, "\n") {
+			t.Fatalf("[%s] expected logs:\n%s\nbut got:\n%s", name, strings.Join(expected, "\n"), got)
+		}
+	}
+
+	// A key selection failure, no signature was checked.
+	lines = lines[:0]
+	_, err = VerifyWithHeaderValidator(nil, testSecret, valid, AllowedAlgorithms(EdDSA), logger)
+	check("header validator",
+		"jwt: verify: form: ok",
+		"jwt: verify: alg: failed: "+err.Error(),
+	)
+
+	// The payload is inflated after the signature was verified.
+	compressed, err := SignWithHeader(testAlg, testSecret, []byte("not deflated"), Map{"alg": testAlg.Name(), "zip": "DEF"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	lines = lines[:0]
+	_, err = Verify(testAlg, testSecret, compressed, logger)
+	check("inflate",
+		"jwt: verify: form: ok",
+		`jwt: verify: alg "HS256": ok`,
+		"jwt: verify: signature: ok",
+		"jwt: verify: payload: failed: "+err.Error(),
+	)
+}
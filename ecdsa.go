@@ -3,8 +3,10 @@ package jwt
 import (
 	"crypto"
 	"crypto/ecdsa"
+	"crypto/elliptic"
 	"crypto/rand"
 	"crypto/x509"
+	"encoding/asn1"
 	"encoding/pem"
 	"fmt"
 	"math/big"
@@ -39,6 +41,23 @@ func (a *algECDSA) Name() string {
 	return a.name
 }
 
+// ExpectedSignatureSize returns the length of the R and S concatenation.
+func (a *algECDSA) ExpectedSignatureSize(key PublicKey) int {
+	var publicKey *ecdsa.PublicKey
+	switch k := key.(type) {
+	case *ecdsa.PublicKey:
+		publicKey = k
+	case *ecdsa.PrivateKey:
+		publicKey = &k.PublicKey
+	}
+
+	if publicKey == nil || publicKey.Curve == nil || publicKey.Curve.Params().BitSize != a.curveBits {
+		return 0
+	}
+
+	return 2 * a.keySize
+}
+
 // JWT handbook chapter 7.2.2.3.1 Algorithm
 // The following code is a clone of the js code described in the book.
 func (a *algECDSA) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error) {
@@ -47,6 +66,11 @@ func (a *algECDSA) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error)
 		return nil, ErrInvalidKey
 	}
 
+	curveBits := privateKey.Curve.Params().BitSize
+	if a.curveBits != curveBits {
+		return nil, ErrInvalidKey
+	}
+
 	h := a.hasher.New()
 	// header.payload
 	_, err := h.Write(headerAndPayload)
@@ -60,11 +84,6 @@ func (a *algECDSA) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error)
 		return nil, err
 	}
 
-	curveBits := privateKey.Curve.Params().BitSize
-	if a.curveBits != curveBits {
-		return nil, ErrInvalidKey
-	}
-
 	keyBytes := curveBits / 8
 	if curveBits%8 > 0 {
 		keyBytes++
@@ -92,6 +111,11 @@ func (a *algECDSA) Verify(key PublicKey, headerAndPayload []byte, signature []by
 		}
 	}
 
+	if a.curveBits != publicKey.Curve.Params().BitSize {
+		return ErrInvalidKey
+	}
+
+	// The signature is the R and S fixed-width, big-endian, values concatenation.
 	if len(signature) != 2*a.keySize {
 		return ErrTokenSignature
 	}
@@ -114,6 +138,73 @@ func (a *algECDSA) Verify(key PublicKey, headerAndPayload []byte, signature []by
 	return nil
 }
 
+// ErrInvalidSignatureEncoding indicates that an ECDSA signature given to
+// the `ConvertDERToJOSE` or the `ConvertJOSEToDER` is malformed
+// or its R and S values do not fit the curve.
+// It is an ErrTokenSignature too.
+var ErrInvalidSignatureEncoding = fmt.Errorf("%w: invalid ECDSA signature encoding", ErrTokenSignature)
+
+// ecdsaSignature is the ASN.1 DER structure of an ECDSA signature (RFC 3279, section 2.2.3).
+type ecdsaSignature struct {
+	R, S *big.Int
+}
+
+// ConvertDERToJOSE converts an ASN.1 DER-encoded ECDSA signature,
+// as OpenSSL and most non-JOSE signers (e.g. a cloud KMS) produce,
+// to the JOSE form the ES256, ES384 and ES512 algorithms expect (RFC 7518, section 3.4):
+// the R and S values, fixed-width and big-endian, concatenated.
+// The "curve" is the curve of the signing key, e.g. elliptic.P256().
+// It returns an ErrInvalidSignatureEncoding error on malformed input.
+//
+// Usage:
+//  derSignature, err := kms.Sign(jwt.SigningInput(header, payload)) // a remote signer.
+//  signature, err := jwt.ConvertDERToJOSE(derSignature, elliptic.P256())
+func ConvertDERToJOSE(sig []byte, curve elliptic.Curve) ([]byte, error) {
+	var v ecdsaSignature
+	rest, err := asn1.Unmarshal(sig, &v)
+	if err != nil || len(rest) > 0 {
+		return nil, ErrInvalidSignatureEncoding
+	}
+
+	keySize := (curve.Params().BitSize + 7) / 8
+	if !validECDSASignatureValue(v.R, curve) || !validECDSASignatureValue(v.S, curve) {
+		return nil, ErrInvalidSignatureEncoding
+	}
+
+	signature := make([]byte, 2*keySize)
+	v.R.FillBytes(signature[:keySize])
+	v.S.FillBytes(signature[keySize:])
+	return signature, nil
+}
+
+// ConvertJOSEToDER converts a JOSE ECDSA signature (the R and S concatenation)
+// of a token to the ASN.1 DER encoding, e.g. to verify it through a non-JOSE library.
+// The "curve" is the curve of the signing key, e.g. elliptic.P256().
+// It returns an ErrInvalidSignatureEncoding error when the signature
+// is not of the curve's size or its values do not fit the curve.
+func ConvertJOSEToDER(sig []byte, curve elliptic.Curve) ([]byte, error) {
+	keySize := (curve.Params().BitSize + 7) / 8
+	if len(sig) != 2*keySize {
+		return nil, ErrInvalidSignatureEncoding
+	}
+
+	v := ecdsaSignature{
+		R: new(big.Int).SetBytes(sig[:keySize]),
+		S: new(big.Int).SetBytes(sig[keySize:]),
+	}
+
+	if !validECDSASignatureValue(v.R, curve) || !validECDSASignatureValue(v.S, curve) {
+		return nil, ErrInvalidSignatureEncoding
+	}
+
+	return asn1.Marshal(v)
+}
+
+// validECDSASignatureValue reports whether the R or S value "v" is in [1, N-1].
+func validECDSASignatureValue(v *big.Int, curve elliptic.Curve) bool {
+	return v != nil && v.Sign() > 0 && v.Cmp(curve.Params().N) < 0
+}
+
 // Key Helpers.
 
 // MustLoadECDSA accepts private and public PEM filenames
@@ -1,6 +1,12 @@
 package jwt
 
 import (
+	"bytes"
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"encoding/asn1"
 	"testing"
 )
 
@@ -35,3 +41,109 @@ func TestMustLoadECDSA(t *testing.T) {
 		MustLoadECDSA("./invalid.pem", "./invalid.pem")
 	})
 }
+
+func TestEncodeDecodeTokenECDSACurves(t *testing.T) {
+	for _, tt := range []struct {
+		alg   Alg
+		curve elliptic.Curve
+	}{
+		{ES256, elliptic.P256()},
+		{ES384, elliptic.P384()},
+		{ES512, elliptic.P521()},
+	} {
+		privateKey, err := ecdsa.GenerateKey(tt.curve, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		testEncodeDecodeToken(t, tt.alg, privateKey, &privateKey.PublicKey, nil)
+	}
+
+	// Test curve mismatch.
+	privateKey, err := ecdsa.GenerateKey(elliptic.P384(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = ES256.Sign(privateKey, []byte("header.payload")); err != ErrInvalidKey {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidKey, err)
+	}
+
+	signature, err := ES384.Sign(privateKey, []byte("header.payload"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = ES256.Verify(&privateKey.PublicKey, []byte("header.payload"), signature); err != ErrInvalidKey {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidKey, err)
+	}
+
+	// Test invalid signature length.
+	if err = ES384.Verify(&privateKey.PublicKey, []byte("header.payload"), signature[1:]); err != ErrTokenSignature {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+}
+
+func TestConvertECDSASignatureDERAndJOSE(t *testing.T) {
+	for _, tt := range []struct {
+		alg   Alg
+		curve elliptic.Curve
+		hash  crypto.Hash
+	}{
+		{ES256, elliptic.P256(), crypto.SHA256},
+		{ES512, elliptic.P521(), crypto.SHA512},
+	} {
+		privateKey, err := ecdsa.GenerateKey(tt.curve, rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// A non-JOSE signer produces an ASN.1 DER signature.
+		signingInput := SigningInput([]byte(`{"alg":"`+tt.alg.Name()+`","typ":"JWT"}`), []byte(`{"sub":"kataras"}`))
+		h := tt.hash.New()
+		h.Write(signingInput)
+		derSignature, err := ecdsa.SignASN1(rand.Reader, privateKey, h.Sum(nil))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		signature, err := ConvertDERToJOSE(derSignature, tt.curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		token := append(append(signingInput, '.'), Base64Encode(signature)...)
+		if _, err = Verify(tt.alg, &privateKey.PublicKey, token); err != nil {
+			t.Fatalf("%s: %v", tt.alg.Name(), err)
+		}
+
+		got, err := ConvertJOSEToDER(signature, tt.curve)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var expected, actual ecdsaSignature
+		if _, err = asn1.Unmarshal(derSignature, &expected); err != nil {
+			t.Fatal(err)
+		}
+		if _, err = asn1.Unmarshal(got, &actual); err != nil {
+			t.Fatal(err)
+		}
+
+		if expected.R.Cmp(actual.R) != 0 || expected.S.Cmp(actual.S) != 0 || !bytes.Equal(derSignature, got) {
+			t.Fatalf("%s: expected the original R and S values after the round trip", tt.alg.Name())
+		}
+
+		if _, err = ConvertDERToJOSE(append(derSignature, 0), tt.curve); err != ErrInvalidSignatureEncoding {
+			t.Fatalf("expected error: %v but got: %v", ErrInvalidSignatureEncoding, err)
+		}
+
+		if _, err = ConvertJOSEToDER(signature[1:], tt.curve); err != ErrInvalidSignatureEncoding {
+			t.Fatalf("expected error: %v but got: %v", ErrInvalidSignatureEncoding, err)
+		}
+
+		if _, err = ConvertJOSEToDER(make([]byte, len(signature)), tt.curve); err != ErrInvalidSignatureEncoding {
+			t.Fatalf("expected error on zero R and S: %v but got: %v", ErrInvalidSignatureEncoding, err)
+		}
+	}
+}
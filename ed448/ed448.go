@@ -0,0 +1,358 @@
+// Package ed448 implements the "EdDSA" JSON Web Algorithm (RFC 8037)
+// using the Ed448 curve (RFC 8032).
+//
+// RFC 8037 registers a single "EdDSA" algorithm name for both Ed25519 and Ed448,
+// the curve is distinguished by the key itself (and the "crv" field of a JSON Web Key).
+// Therefore the `Ed448` algorithm's Name is "EdDSA" too, like the `jwt.EdDSA` one
+// (see the newer "Ed448" fully-specified identifier draft);
+// what algorithm is used to verify a token is up to the caller,
+// e.g. through the `jwt.Keys` of a "kid" header.
+//
+// It lives in its own module so the users who don't need it
+// don't pull the circl dependency.
+//
+// Usage:
+//
+//  privateKey, publicKey := ed448.MustLoad("./private_key.pem", "./public_key.pem")
+//  token, err := jwt.Sign(ed448.Ed448, privateKey, claims, jwt.MaxAge(15*time.Minute))
+//  verifiedToken, err := jwt.Verify(ed448.Ed448, publicKey, token)
+package ed448
+
+import (
+	"bytes"
+	"encoding/asn1"
+	"encoding/json"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/kataras/jwt"
+
+	"github.com/cloudflare/circl/sign/ed448"
+)
+
+// Ed448 is the Edwards-curve Digital Signature Algorithm using the Ed448 curve.
+// Its Name is "EdDSA", as RFC 8037 defines.
+// Sign   key: ed448.PrivateKey
+// Verify key: ed448.PublicKey (or ed448.PrivateKey)
+//
+// Ed25519 keys are rejected with the jwt.ErrInvalidKey error
+// and the `jwt.EdDSA` algorithm rejects Ed448 keys the same way.
+//
+// Generate a private key:
+// $ openssl genpkey -algorithm ED448 -out private_key.pem
+// Derive the public key from the private key:
+// $ openssl pkey -in private_key.pem -pubout -out public_key.pem
+var Ed448 jwt.Alg = &algEd448{"EdDSA"}
+
+type algEd448 struct {
+	name string
+}
+
+var (
+	_ jwt.AlgParser         = (*algEd448)(nil)
+	_ jwt.AlgSignatureSizer = (*algEd448)(nil)
+)
+
+func (a *algEd448) Parse(private, public []byte) (privateKey jwt.PrivateKey, publicKey jwt.PublicKey, err error) {
+	if len(private) > 0 {
+		privateKey, err = ParsePrivateKey(private)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Ed448: private key: %v", err)
+		}
+	}
+
+	if len(public) > 0 {
+		publicKey, err = ParsePublicKey(public)
+		if err != nil {
+			return nil, nil, fmt.Errorf("Ed448: public key: %v", err)
+		}
+	}
+
+	return
+}
+
+func (a *algEd448) Name() string {
+	return a.name
+}
+
+// ExpectedSignatureSize completes the jwt.AlgSignatureSizer.
+func (a *algEd448) ExpectedSignatureSize(key jwt.PublicKey) int {
+	switch k := key.(type) {
+	case ed448.PublicKey:
+		if len(k) != ed448.PublicKeySize {
+			return 0
+		}
+	case ed448.PrivateKey:
+		if len(k) != ed448.PrivateKeySize {
+			return 0
+		}
+	default:
+		return 0
+	}
+
+	return ed448.SignatureSize
+}
+
+func (a *algEd448) Sign(key jwt.PrivateKey, headerAndPayload []byte) ([]byte, error) {
+	privateKey, ok := key.(ed448.PrivateKey)
+	if !ok {
+		return nil, jwt.ErrInvalidKey
+	}
+
+	if len(privateKey) != ed448.PrivateKeySize {
+		return nil, jwt.ErrInvalidKey
+	}
+
+	return ed448.Sign(privateKey, headerAndPayload, ""), nil // pure Ed448, empty context.
+}
+
+func (a *algEd448) Verify(key jwt.PublicKey, headerAndPayload []byte, signature []byte) error {
+	publicKey, ok := key.(ed448.PublicKey)
+	if !ok {
+		if privateKey, ok := key.(ed448.PrivateKey); ok && len(privateKey) == ed448.PrivateKeySize {
+			publicKey = privateKey.Public().(ed448.PublicKey)
+		} else {
+			return jwt.ErrInvalidKey
+		}
+	}
+
+	if len(publicKey) != ed448.PublicKeySize {
+		return jwt.ErrInvalidKey
+	}
+
+	if len(signature) != ed448.SignatureSize || !ed448.Verify(publicKey, headerAndPayload, signature, "") {
+		return jwt.ErrTokenSignature
+	}
+
+	return nil
+}
+
+// Key Helpers.
+
+var oidEd448 = asn1.ObjectIdentifier{1, 3, 101, 113} // RFC 8410, section 3.
+
+type (
+	algorithmIdentifier struct {
+		Algorithm asn1.ObjectIdentifier
+	}
+
+	// RFC 5208, section 5.
+	pkcs8 struct {
+		Version    int
+		Algo       algorithmIdentifier
+		PrivateKey []byte
+	}
+
+	// RFC 5280, section 4.1.
+	subjectPublicKeyInfo struct {
+		Algorithm algorithmIdentifier
+		PublicKey asn1.BitString
+	}
+)
+
+// MustLoad accepts private and public PEM filenames
+// and returns a pair of private and public Ed448 keys.
+// Pass the returned private key to the `jwt.Sign` function
+// and the public key to the `jwt.Verify` function.
+//
+// It panics on errors.
+func MustLoad(privateKeyFilename, publicKeyFilename string) (ed448.PrivateKey, ed448.PublicKey) {
+	privateKey, err := LoadPrivateKey(privateKeyFilename)
+	if err != nil {
+		panic(err)
+	}
+
+	publicKey, err := LoadPublicKey(publicKeyFilename)
+	if err != nil {
+		panic(err)
+	}
+
+	return privateKey, publicKey
+}
+
+// LoadPrivateKey accepts a file path of a PEM-encoded Ed448 private key
+// and returns the Ed448 private key Go value.
+// It uses the `jwt.ReadFile` package-level variable to read the file.
+func LoadPrivateKey(filename string) (ed448.PrivateKey, error) {
+	b, err := jwt.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePrivateKey(b)
+}
+
+// LoadPublicKey accepts a file path of a PEM-encoded Ed448 public key
+// and returns the Ed448 public key Go value.
+// It uses the `jwt.ReadFile` package-level variable to read the file.
+func LoadPublicKey(filename string) (ed448.PublicKey, error) {
+	b, err := jwt.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePublicKey(b)
+}
+
+// ParsePrivateKey decodes and parses the
+// PEM-encoded (PKCS #8) Ed448 private key's raw contents.
+func ParsePrivateKey(key []byte) (ed448.PrivateKey, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return nil, fmt.Errorf("private key: malformed or missing PEM format (Ed448)")
+	}
+
+	var p8 pkcs8
+	if _, err := asn1.Unmarshal(block.Bytes, &p8); err != nil {
+		return nil, fmt.Errorf("private key: %w", err)
+	}
+
+	if !p8.Algo.Algorithm.Equal(oidEd448) {
+		return nil, fmt.Errorf("private key: expected an Ed448 key")
+	}
+
+	// The private key is the seed wrapped in an OCTET STRING (RFC 8410, section 7).
+	var seed []byte
+	if _, err := asn1.Unmarshal(p8.PrivateKey, &seed); err != nil {
+		return nil, fmt.Errorf("private key: %w", err)
+	}
+
+	if l := len(seed); l != ed448.SeedSize {
+		return nil, fmt.Errorf("private key: bad seed length: %d", l)
+	}
+
+	return ed448.NewKeyFromSeed(seed), nil
+}
+
+// ParsePublicKey decodes and parses the
+// PEM-encoded (PKIX) Ed448 public key's raw contents.
+func ParsePublicKey(key []byte) (ed448.PublicKey, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return nil, fmt.Errorf("public key: malformed or missing PEM format (Ed448)")
+	}
+
+	var info subjectPublicKeyInfo
+	if _, err := asn1.Unmarshal(block.Bytes, &info); err != nil {
+		return nil, fmt.Errorf("public key: %w", err)
+	}
+
+	if !info.Algorithm.Algorithm.Equal(oidEd448) {
+		return nil, fmt.Errorf("public key: expected an Ed448 key")
+	}
+
+	if l := len(info.PublicKey.Bytes); l != ed448.PublicKeySize {
+		return nil, fmt.Errorf("%w: Ed448 public key must be %d bytes, got %d", jwt.ErrInvalidKey, ed448.PublicKeySize, l)
+	}
+
+	return ed448.PublicKey(info.PublicKey.Bytes), nil
+}
+
+// ParsePublicKeyFromJWK decodes and parses the
+// JSON Web Key of an Ed448 public key,
+// e.g. {"kty":"OKP","crv":"Ed448","x":"..."}.
+//
+// It returns a type of jwt.ErrJWKType or jwt.ErrJWKCurve if
+// the JWK is not an "OKP" key of the "Ed448" curve.
+func ParsePublicKeyFromJWK(key []byte) (ed448.PublicKey, error) {
+	jwk, err := parseJWK(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeJWKPublicKey(jwk)
+}
+
+// ParsePrivateKeyFromJWK decodes and parses the
+// JSON Web Key of an Ed448 private key,
+// e.g. {"kty":"OKP","crv":"Ed448","x":"...","d":"..."}.
+//
+// It returns a type of jwt.ErrJWKType or jwt.ErrJWKCurve if
+// the JWK is not an "OKP" key of the "Ed448" curve.
+func ParsePrivateKeyFromJWK(key []byte) (ed448.PrivateKey, error) {
+	jwk, err := parseJWK(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if jwk.D == "" {
+		return nil, fmt.Errorf("private key: jwk: missing \"d\"")
+	}
+
+	seed, err := jwt.Base64Decode([]byte(jwk.D))
+	if err != nil {
+		return nil, fmt.Errorf("private key: jwk: \"d\": %w", err)
+	}
+
+	if l := len(seed); l != ed448.SeedSize {
+		return nil, fmt.Errorf("private key: jwk: bad seed length: %d", l)
+	}
+
+	privateKey := ed448.NewKeyFromSeed(seed)
+
+	if jwk.X != "" { // make sure the key pair matches.
+		publicKey, err := decodeJWKPublicKey(jwk)
+		if err != nil {
+			return nil, err
+		}
+
+		if !bytes.Equal(publicKey, privateKey.Public().(ed448.PublicKey)) {
+			return nil, fmt.Errorf("private key: jwk: \"x\" does not match the \"d\" public key")
+		}
+	}
+
+	return privateKey, nil
+}
+
+// MarshalPublicKeyToJWK encodes the Ed448 public key
+// to the JSON Web Key form, which `ParsePublicKeyFromJWK` expects,
+// e.g. {"kty":"OKP","crv":"Ed448","kid":"...","x":"..."}.
+// The "kid" is optional. See `jwt.MarshalJWKS` too.
+func MarshalPublicKeyToJWK(key ed448.PublicKey, kid string) ([]byte, error) {
+	if len(key) != ed448.PublicKeySize {
+		return nil, jwt.ErrInvalidKey
+	}
+
+	jwk := jwt.JWK{
+		Kty: "OKP",
+		Crv: "Ed448",
+		Kid: kid,
+		X:   string(jwt.Base64Encode(key)),
+	}
+
+	return json.Marshal(jwk)
+}
+
+func parseJWK(key []byte) (*jwt.JWK, error) {
+	var jwk jwt.JWK
+	if err := jwt.Unmarshal(key, &jwk); err != nil {
+		return nil, fmt.Errorf("jwk: %w", err)
+	}
+
+	if jwk.Kty != "OKP" {
+		return nil, fmt.Errorf("%w: %q", jwt.ErrJWKType, jwk.Kty)
+	}
+
+	if jwk.Crv != "Ed448" {
+		return nil, fmt.Errorf("%w: %q", jwt.ErrJWKCurve, jwk.Crv)
+	}
+
+	return &jwk, nil
+}
+
+func decodeJWKPublicKey(jwk *jwt.JWK) (ed448.PublicKey, error) {
+	if jwk.X == "" {
+		return nil, fmt.Errorf("public key: jwk: missing \"x\"")
+	}
+
+	x, err := jwt.Base64Decode([]byte(jwk.X))
+	if err != nil {
+		return nil, fmt.Errorf("public key: jwk: \"x\": %w", err)
+	}
+
+	if l := len(x); l != ed448.PublicKeySize {
+		return nil, fmt.Errorf("public key: jwk: bad length: %d", l)
+	}
+
+	return ed448.PublicKey(x), nil
+}
@@ -0,0 +1,125 @@
+package ed448
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/kataras/jwt"
+
+	"github.com/cloudflare/circl/sign/ed448"
+)
+
+func TestEd448(t *testing.T) {
+	privateKey, publicKey := MustLoad("./_testfiles/ed448_private_key.pem", "./_testfiles/ed448_public_key.pem")
+
+	if !publicKey.Equal(privateKey.Public()) {
+		t.Fatalf("expected public key to match the private one")
+	}
+
+	claims := jwt.Map{"username": "kataras"}
+	token, err := jwt.Sign(Ed448, privateKey, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := jwt.Verify(Ed448, publicKey, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got jwt.Map
+	if err = verifiedToken.Claims(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got["username"] != claims["username"] {
+		t.Fatalf("expected claims: %#+v but got: %#+v", claims, got)
+	}
+
+	// test the automatic extract of public key from private key.
+	if _, err = jwt.Verify(Ed448, privateKey, token); err != nil {
+		t.Fatal(err)
+	}
+
+	// test invalid signature.
+	tampered := append([]byte(nil), token...)
+	tampered[len(tampered)-2] ^= 1
+	if _, err = jwt.Verify(Ed448, publicKey, tampered); !errors.Is(err, jwt.ErrTokenSignature) {
+		t.Fatalf("expected error: %v but got: %v", jwt.ErrTokenSignature, err)
+	}
+
+	// test truncated signature.
+	truncated := token[:len(token)-4]
+	if _, err = jwt.Verify(Ed448, publicKey, truncated); !errors.Is(err, jwt.ErrInvalidSignatureLength) {
+		t.Fatalf("expected error: %v but got: %v", jwt.ErrInvalidSignatureLength, err)
+	}
+
+	// test Ed25519 keys are rejected and vice versa.
+	ed25519PrivateKey, ed25519PublicKey := jwt.MustLoadEdDSA("../_testfiles/ed25519_private_key.pem", "../_testfiles/ed25519_public_key.pem")
+	if _, err = jwt.Sign(Ed448, ed25519PrivateKey, claims); !errors.Is(err, jwt.ErrInvalidKey) {
+		t.Fatalf("expected error: %v but got: %v", jwt.ErrInvalidKey, err)
+	}
+
+	if _, err = jwt.Verify(Ed448, ed25519PublicKey, token); !errors.Is(err, jwt.ErrInvalidKey) {
+		t.Fatalf("expected error: %v but got: %v", jwt.ErrInvalidKey, err)
+	}
+
+	if _, err = jwt.Verify(Ed448, ed448.PublicKey(ed25519PublicKey), token); !errors.Is(err, jwt.ErrInvalidKey) {
+		t.Fatalf("expected error on Ed25519-sized key: %v but got: %v", jwt.ErrInvalidKey, err)
+	}
+
+	if _, err = jwt.Sign(jwt.EdDSA, privateKey, claims); !errors.Is(err, jwt.ErrInvalidKey) {
+		t.Fatalf("expected error: %v but got: %v", jwt.ErrInvalidKey, err)
+	}
+
+	if _, err = jwt.Verify(jwt.EdDSA, publicKey, token); !errors.Is(err, jwt.ErrInvalidKey) {
+		t.Fatalf("expected error: %v but got: %v", jwt.ErrInvalidKey, err)
+	}
+
+	if _, err = LoadPublicKey("../_testfiles/ed25519_public_key.pem"); err == nil {
+		t.Fatalf("expected error on Ed25519 public key")
+	}
+
+	if _, err = LoadPrivateKey("../_testfiles/ed25519_private_key.pem"); err == nil {
+		t.Fatalf("expected error on Ed25519 private key")
+	}
+}
+
+func TestEd448JWK(t *testing.T) {
+	privateKey, publicKey := MustLoad("./_testfiles/ed448_private_key.pem", "./_testfiles/ed448_public_key.pem")
+
+	b, err := MarshalPublicKeyToJWK(publicKey, "ed448-key")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ParsePublicKeyFromJWK(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(got, publicKey) {
+		t.Fatalf("expected public key to match after the JWK round trip")
+	}
+
+	privateJWK := []byte(`{"kty":"OKP","crv":"Ed448","d":"` + string(jwt.Base64Encode(privateKey.Seed())) +
+		`","x":"` + string(jwt.Base64Encode(publicKey)) + `"}`)
+	gotPrivateKey, err := ParsePrivateKeyFromJWK(privateJWK)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !privateKey.Equal(gotPrivateKey) {
+		t.Fatalf("expected private key to match")
+	}
+
+	ed25519JWK := []byte(`{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`)
+	if _, err = ParsePublicKeyFromJWK(ed25519JWK); !errors.Is(err, jwt.ErrJWKCurve) {
+		t.Fatalf("expected error: %v but got: %v", jwt.ErrJWKCurve, err)
+	}
+
+	if _, err = ParsePublicKeyFromJWK([]byte(`{"kty":"RSA"}`)); !errors.Is(err, jwt.ErrJWKType) {
+		t.Fatalf("expected error: %v but got: %v", jwt.ErrJWKType, err)
+	}
+}
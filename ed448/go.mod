@@ -0,0 +1,17 @@
+module github.com/kataras/jwt/ed448
+
+go 1.18
+
+require (
+	github.com/cloudflare/circl v1.3.1
+	github.com/kataras/jwt v0.1.9-0.20261014064858-16955e686d80
+)
+
+require (
+	golang.org/x/crypto v0.17.0 // indirect
+	golang.org/x/sys v0.15.0 // indirect
+)
+
+// The released tags do not include the APIs of this module yet,
+// build it against the parent tree until the next tag.
+replace github.com/kataras/jwt => ../
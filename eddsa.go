@@ -8,6 +8,9 @@ import (
 	"encoding/pem"
 	"errors"
 	"fmt"
+	"io"
+	"os"
+	"path/filepath"
 )
 
 type algEdDSA struct {
@@ -46,6 +49,20 @@ func (a *algEdDSA) Name() string {
 	return a.name
 }
 
+func (a *algEdDSA) ExpectedSignatureSize(key PublicKey) int {
+	switch k := key.(type) {
+	case ed25519.PublicKey:
+		if len(k) != ed25519.PublicKeySize {
+			return 0
+		}
+	case ed25519.PrivateKey:
+	default:
+		return 0
+	}
+
+	return ed25519.SignatureSize
+}
+
 func (a *algEdDSA) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error) {
 	privateKey, ok := key.(ed25519.PrivateKey)
 	if !ok {
@@ -102,6 +119,46 @@ func MustLoadEdDSA(privateKeyFilename, publicKeyFilename string) (ed25519.Privat
 	return privateKey, publicKey
 }
 
+// LoadKeyPairEdDSA accepts a single file path which holds both
+// the PEM-encoded ed25519 private key and, optionally, the public key block,
+// as some tools produce, and returns the pair of private and public ed25519 keys.
+// If the public key block is missing, the public key is derived from the private one.
+// If it's present, it must match the private key.
+//
+// It returns an ErrPEMBlockNotFound error if the file holds no private key block.
+func LoadKeyPairEdDSA(filename string) (ed25519.PrivateKey, ed25519.PublicKey, error) {
+	b, err := ReadFile(filename)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	privateKey, err := ParsePrivateKeyEdDSA(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	derived := privateKey.Public().(ed25519.PublicKey)
+
+	if _, err = decodePEMBlock(b, "PUBLIC KEY"); err != nil {
+		if errors.Is(err, ErrPEMBlockNotFound) {
+			return privateKey, derived, nil
+		}
+
+		return nil, nil, fmt.Errorf("public key: %w (EdDSA)", err)
+	}
+
+	publicKey, err := ParsePublicKeyEdDSA(b)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if !publicKey.Equal(derived) {
+		return nil, nil, fmt.Errorf("%w: the public key does not match the private key (EdDSA)", ErrInvalidKey)
+	}
+
+	return privateKey, publicKey, nil
+}
+
 // LoadPrivateKeyEdDSA accepts a file path of a PEM-encoded ed25519 private key
 // and returns the ed25519 private key Go value.
 // Pass the returned value to the `Token` (signing) function.
@@ -136,10 +193,126 @@ func LoadPublicKeyEdDSA(filename string) (ed25519.PublicKey, error) {
 	return key, nil
 }
 
+// ParsePrivateKeyEdDSAFromReader reads the PEM-encoded ed25519 private key
+// from "r" (e.g. an embed.FS file, a http response body) and parses it.
+// It reads up to `MaxKeySize` bytes.
+// Pass the returned value to the `Token` (signing) function.
+func ParsePrivateKeyEdDSAFromReader(r io.Reader) (ed25519.PrivateKey, error) {
+	b, err := readKey(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePrivateKeyEdDSA(b)
+}
+
+// ParsePublicKeyEdDSAFromReader reads the PEM-encoded ed25519 public key
+// from "r" and parses it.
+// It reads up to `MaxKeySize` bytes.
+// Pass the returned value to the `Verify` function.
+func ParsePublicKeyEdDSAFromReader(r io.Reader) (ed25519.PublicKey, error) {
+	b, err := readKey(r)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePublicKeyEdDSA(b)
+}
+
+// LoadPrivateKeyEdDSAFromEnv same as `LoadPrivateKeyEdDSA`
+// but it reads the PEM-encoded ed25519 private key of the "varName" environment variable,
+// e.g. on containerized deployments. The value can be a raw PEM or a base64-encoded PEM.
+// It returns an ErrEnvKeyNotSet error, which names the variable, if it's not set or empty.
+//
+// Usage:
+//  privateKey, err := jwt.LoadPrivateKeyEdDSAFromEnv("JWT_PRIVATE_KEY")
+func LoadPrivateKeyEdDSAFromEnv(varName string) (ed25519.PrivateKey, error) {
+	b, err := readEnvKey(varName)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ParsePrivateKeyEdDSA(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", varName, err)
+	}
+
+	return key, nil
+}
+
+// LoadPublicKeyEdDSAFromEnv same as `LoadPublicKeyEdDSA`
+// but it reads the PEM-encoded ed25519 public key of the "varName" environment variable.
+// See `LoadPrivateKeyEdDSAFromEnv` too.
+func LoadPublicKeyEdDSAFromEnv(varName string) (ed25519.PublicKey, error) {
+	b, err := readEnvKey(varName)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ParsePublicKeyEdDSA(b)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", varName, err)
+	}
+
+	return key, nil
+}
+
+// LoadPrivateKeyEdDSAWithPassword same as `LoadPrivateKeyEdDSA`
+// but it decrypts the password-protected PEM-encoded ed25519 private key.
+func LoadPrivateKeyEdDSAWithPassword(filename, password string) (ed25519.PrivateKey, error) {
+	b, err := ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := ParsePrivateKeyEdDSAWithPassword(b, password)
+	if err != nil {
+		return nil, err
+	}
+
+	return key, nil
+}
+
 // ParsePrivateKeyEdDSA decodes and parses the
 // PEM-encoded ed25519 private key's raw contents.
+// Blocks of other types, e.g. a certificate before the key, are skipped.
 // Pass the result to the `Token` (signing) function.
+//
+// It returns ErrEncryptedKeyNoPassword if the key is encrypted,
+// see `ParsePrivateKeyEdDSAWithPassword` instead.
 func ParsePrivateKeyEdDSA(key []byte) (ed25519.PrivateKey, error) {
+	return ParsePrivateKeyEdDSAWithPassword(key, "")
+}
+
+// ParsePrivateKeyEdDSAWithPassword same as `ParsePrivateKeyEdDSA`
+// but it decrypts the password-protected (legacy "DEK-Info" or PKCS #8 "ENCRYPTED PRIVATE KEY")
+// PEM-encoded ed25519 private key first.
+// It returns ErrEncryptedKeyNoPassword if the key is encrypted and the "password" is empty.
+func ParsePrivateKeyEdDSAWithPassword(key []byte, password string) (ed25519.PrivateKey, error) {
+	block, err := decodePEMBlock(key, "PRIVATE KEY")
+	if err != nil {
+		return nil, fmt.Errorf("private key: %w (EdDSA)", err)
+	}
+
+	der, err := decryptPEMBlock(block, []byte(password))
+	if err != nil {
+		return nil, err
+	}
+
+	return parsePrivateKeyEdDSA(der)
+}
+
+func parsePrivateKeyEdDSA(der []byte) (ed25519.PrivateKey, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		privateKey, ok := key.(ed25519.PrivateKey)
+		if !ok {
+			return nil, fmt.Errorf("private key: expected an ed25519 key but got: %T", key)
+		}
+
+		return privateKey, nil
+	}
+
+	// Fallback to the raw seed of the key.
 	asn1PrivKey := struct {
 		Version          int
 		ObjectIdentifier struct {
@@ -148,16 +321,16 @@ func ParsePrivateKeyEdDSA(key []byte) (ed25519.PrivateKey, error) {
 		PrivateKey []byte
 	}{}
 
-	block, _ := pem.Decode(key)
-	if block == nil {
-		return nil, fmt.Errorf("private key: %w (EdDSA)", errPEMMalformed)
+	if _, err := asn1.Unmarshal(der, &asn1PrivKey); err != nil {
+		return nil, err
 	}
 
-	if _, err := asn1.Unmarshal(block.Bytes, &asn1PrivKey); err != nil {
-		return nil, err
+	// The seed is wrapped in an OCTET STRING (tag and length bytes first).
+	seed := asn1PrivKey.PrivateKey
+	if len(seed) > 2 && seed[0] == asn1.TagOctetString && int(seed[1]) == len(seed)-2 {
+		seed = seed[2:]
 	}
 
-	seed := asn1PrivKey.PrivateKey[2:]
 	if l := len(seed); l != ed25519.SeedSize {
 		return nil, fmt.Errorf("private key: bad seed length: %d", l)
 	}
@@ -166,10 +339,127 @@ func ParsePrivateKeyEdDSA(key []byte) (ed25519.PrivateKey, error) {
 	return privateKey, nil
 }
 
+// ParseEdDSAPublicKeyFromCertPEM decodes and parses the
+// PEM-encoded X.509 certificate (the first "CERTIFICATE" block)
+// and returns its ed25519 public key.
+// It returns an ErrInvalidKey error if the certificate's key is not an ed25519 one.
+// Pass the result to the `Verify` function.
+//
+// Note that the certificate is NOT verified, see `ParseEdDSAPublicKeyFromCertPEMWithRoots`.
+func ParseEdDSAPublicKeyFromCertPEM(cert []byte) (ed25519.PublicKey, error) {
+	return ParseEdDSAPublicKeyFromCertPEMWithRoots(cert, nil)
+}
+
+// ParseEdDSAPublicKeyFromCertPEMWithRoots same as `ParseEdDSAPublicKeyFromCertPEM`
+// but, if "roots" is not nil, it verifies the certificate against the "roots" pool
+// before trusting its key. The rest of the "CERTIFICATE" blocks of "cert", if any,
+// are used as intermediates of the chain.
+func ParseEdDSAPublicKeyFromCertPEMWithRoots(cert []byte, roots *x509.CertPool) (ed25519.PublicKey, error) {
+	var certs []*x509.Certificate
+
+	for rest := cert; ; {
+		var block *pem.Block
+		block, rest = pem.Decode(rest)
+		if block == nil {
+			break
+		}
+
+		if block.Type != "CERTIFICATE" {
+			continue // e.g. the private key.
+		}
+
+		c, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("certificate: %w", err)
+		}
+
+		certs = append(certs, c)
+	}
+
+	if len(certs) == 0 {
+		if _, err := decodePEMBlock(cert, "CERTIFICATE"); err != nil {
+			return nil, fmt.Errorf("certificate: %w (EdDSA)", err)
+		}
+
+		return nil, fmt.Errorf("certificate: %w: expected a \"CERTIFICATE\" block (EdDSA)", ErrPEMBlockNotFound)
+	}
+
+	leaf := certs[0]
+
+	if roots != nil {
+		intermediates := x509.NewCertPool()
+		for _, c := range certs[1:] {
+			intermediates.AddCert(c)
+		}
+
+		opts := x509.VerifyOptions{
+			Roots:         roots,
+			Intermediates: intermediates,
+			CurrentTime:   Clock(),
+			KeyUsages:     []x509.ExtKeyUsage{x509.ExtKeyUsageAny},
+		}
+		if _, err := leaf.Verify(opts); err != nil {
+			return nil, fmt.Errorf("certificate: %w", err)
+		}
+	}
+
+	publicKey, ok := leaf.PublicKey.(ed25519.PublicKey)
+	if !ok {
+		return nil, fmt.Errorf("%w: expected an ed25519 certificate key but got: %T", ErrInvalidKey, leaf.PublicKey)
+	}
+
+	return publicKey, nil
+}
+
+// ParseRawPrivateKeyEdDSA accepts the raw (not PEM-encoded) ed25519 private key's contents.
+// The "seedOrKey" can be either the 32-byte seed or the 64-byte private key
+// (e.g. as stored in a secrets manager).
+// It returns ErrInvalidKey for any other length.
+// Pass the result to the `Token` (signing) function.
+func ParseRawPrivateKeyEdDSA(seedOrKey []byte) (ed25519.PrivateKey, error) {
+	switch len(seedOrKey) {
+	case ed25519.SeedSize:
+		return ed25519.NewKeyFromSeed(seedOrKey), nil
+	case ed25519.PrivateKeySize:
+		privateKey := make(ed25519.PrivateKey, ed25519.PrivateKeySize)
+		copy(privateKey, seedOrKey)
+		return privateKey, nil
+	default:
+		return nil, ErrInvalidKey
+	}
+}
+
+// NewEdDSAPublicKey accepts a raw (bare) 32-byte ed25519 public key,
+// as libsodium-based systems, the Noise protocol and many web3 tools hand it,
+// and returns a copy of it. It returns ErrInvalidKey for any other length.
+// Pass the result to the `Verify` function.
+func NewEdDSAPublicKey(raw []byte) (ed25519.PublicKey, error) {
+	if len(raw) != ed25519.PublicKeySize {
+		return nil, ErrInvalidKey
+	}
+
+	publicKey := make(ed25519.PublicKey, ed25519.PublicKeySize)
+	copy(publicKey, raw)
+	return publicKey, nil
+}
+
+// NewEdDSAPrivateKeyFromSeed accepts a raw 32-byte ed25519 seed (RFC 8032)
+// and returns its private key. It returns ErrInvalidKey for any other length,
+// see `ParseRawPrivateKeyEdDSA` to accept the 64-byte private key form too.
+// Pass the result to the `Token` (signing) function.
+func NewEdDSAPrivateKeyFromSeed(seed []byte) (ed25519.PrivateKey, error) {
+	if len(seed) != ed25519.SeedSize {
+		return nil, ErrInvalidKey
+	}
+
+	return ed25519.NewKeyFromSeed(seed), nil
+}
+
 var errPEMMalformed = errors.New("pem malformed")
 
 // ParsePublicKeyEdDSA decodes and parses the
 // PEM-encoded ed25519 public key's raw contents.
+// Blocks of other types, e.g. a certificate before the key, are skipped.
 // Pass the result to the `Verify` function.
 func ParsePublicKeyEdDSA(key []byte) (ed25519.PublicKey, error) {
 	asn1PubKey := struct {
@@ -179,44 +469,255 @@ func ParsePublicKeyEdDSA(key []byte) (ed25519.PublicKey, error) {
 		PublicKey asn1.BitString
 	}{}
 
-	block, _ := pem.Decode(key)
-	if block == nil {
-		return nil, fmt.Errorf("public key: %w (EdDSA)", errPEMMalformed)
+	block, err := decodePEMBlock(key, "PUBLIC KEY")
+	if err != nil {
+		return nil, fmt.Errorf("public key: %w (EdDSA)", err)
 	}
 
+	if key, err := x509.ParsePKIXPublicKey(block.Bytes); err == nil {
+		publicKey, ok := key.(ed25519.PublicKey)
+		if !ok {
+			return nil, fmt.Errorf("%w: expected an ed25519 public key but got: %T", ErrInvalidKey, key)
+		}
+
+		return publicKey, nil
+	}
+
+	// Fallback to the manual extraction.
 	if _, err := asn1.Unmarshal(block.Bytes, &asn1PubKey); err != nil {
 		return nil, err
 	}
 
 	publicKey := ed25519.PublicKey(asn1PubKey.PublicKey.Bytes)
+	if l := len(publicKey); l != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("%w: ed25519 public key must be %d bytes, got %d", ErrInvalidKey, ed25519.PublicKeySize, l)
+	}
+
 	return publicKey, nil
 }
 
-// GenerateEdDSA generates random public and private keys for ed25519.
-func GenerateEdDSA() (ed25519.PublicKey, ed25519.PrivateKey, error) {
-	pub, priv, _ := ed25519.GenerateKey(rand.Reader)
-
-	privBytes, err := x509.MarshalPKCS8PrivateKey(priv) // Convert a generated ed25519 key into a PEM block so that the ssh library can ingest it, bit round about tbh
+// GenerateEdDSA generates a random ed25519 key pair.
+// Pass the returned private key to the `Token` (signing) function
+// and the public key to the `Verify` function.
+//
+// Use the `MarshalPrivateKeyEdDSA` and `MarshalPublicKeyEdDSA`
+// to convert the keys to PEM format.
+func GenerateEdDSA() (ed25519.PrivateKey, ed25519.PublicKey, error) {
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
 	if err != nil {
 		return nil, nil, err
 	}
-	privatePEM := pem.EncodeToMemory(
-		&pem.Block{
-			Type:  "PRIVATE KEY",
-			Bytes: privBytes,
-		},
-	)
 
-	pubBytes, err := x509.MarshalPKIXPublicKey(pub)
+	return privateKey, publicKey, nil
+}
+
+// MarshalPrivateKeyEdDSA encodes the ed25519 private key
+// to the PKCS #8, PEM-encoded form, which `ParsePrivateKeyEdDSA` expects.
+func MarshalPrivateKeyEdDSA(key ed25519.PrivateKey) ([]byte, error) {
+	b, err := x509.MarshalPKCS8PrivateKey(key)
 	if err != nil {
-		return nil, nil, err
+		return nil, err
+	}
+
+	privatePEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "PRIVATE KEY",
+		Bytes: b,
+	})
+
+	return privatePEM, nil
+}
+
+// MarshalPublicKeyEdDSA encodes the ed25519 public key
+// to the PKIX, PEM-encoded form, which `ParsePublicKeyEdDSA` expects.
+func MarshalPublicKeyEdDSA(key ed25519.PublicKey) ([]byte, error) {
+	b, err := x509.MarshalPKIXPublicKey(key)
+	if err != nil {
+		return nil, err
+	}
+
+	publicPEM := pem.EncodeToMemory(&pem.Block{
+		Type:  "PUBLIC KEY",
+		Bytes: b,
+	})
+
+	return publicPEM, nil
+}
+
+// GenerateKeyPairToFiles generates a random ed25519 key pair and writes
+// the PEM-encoded private and public keys to the "privateKeyFilename" and "publicKeyFilename" files,
+// e.g. for init scripts and "make keys" targets.
+// The private key file is written with the "perm" permissions, 0600 when "perm" is zero,
+// the public key file with 0644.
+//
+// It refuses to overwrite existing files, with an error which matches the `os.ErrExist`,
+// unless "overwrite" is true.
+// If the public key file can not be written then the new private key file is removed.
+// When "overwrite" is true, both keys are written to temporary files of the same directories first
+// and they replace the existing files only after both of them were written.
+// The existing private key file is restored if the public key file can not be replaced,
+// so a failure never leaves a new private key next to an old public one.
+// Load the keys through `LoadPrivateKeyEdDSA` and `LoadPublicKeyEdDSA`.
+//
+// Usage:
+//  err := jwt.GenerateKeyPairToFiles("ed25519_private_key.pem", "ed25519_public_key.pem", 0600, false)
+func GenerateKeyPairToFiles(privateKeyFilename, publicKeyFilename string, perm os.FileMode, overwrite bool) error {
+	privateKey, publicKey, err := GenerateEdDSA()
+	if err != nil {
+		return err
+	}
+
+	privatePEM, err := MarshalPrivateKeyEdDSA(privateKey)
+	if err != nil {
+		return err
+	}
+
+	publicPEM, err := MarshalPublicKeyEdDSA(publicKey)
+	if err != nil {
+		return err
+	}
+
+	if perm == 0 {
+		perm = 0600
 	}
 
-	publicPEM := pem.EncodeToMemory(
-		&pem.Block{
-			Type:  "PUBLIC KEY",
-			Bytes: pubBytes,
-		})
+	if overwrite {
+		return replaceKeyFiles(privateKeyFilename, privatePEM, perm, publicKeyFilename, publicPEM, 0644)
+	}
+
+	if err = writeKeyFile(privateKeyFilename, privatePEM, perm); err != nil {
+		return err
+	}
+
+	if err = writeKeyFile(publicKeyFilename, publicPEM, 0644); err != nil {
+		os.Remove(privateKeyFilename)
+		return err
+	}
+
+	return nil
+}
+
+// writeKeyFile writes the "data" to a new file, it fails if the file exists.
+func writeKeyFile(filename string, data []byte, perm os.FileMode) error {
+	f, err := os.OpenFile(filename, os.O_WRONLY|os.O_CREATE|os.O_EXCL, perm)
+	if err != nil {
+		return err
+	}
+
+	if err = writeAndClose(f, data, perm); err != nil {
+		// Do not leave a partially written key file behind,
+		// a retry would fail with os.ErrExist.
+		os.Remove(filename)
+		return err
+	}
+
+	return nil
+}
+
+// replaceKeyFiles writes the private and public key files to temporary files
+// and renames them to their final names only after both of them were written.
+// The existing private key file is moved aside first and it's restored
+// if the public key file can not be replaced.
+func replaceKeyFiles(privateKeyFilename string, privatePEM []byte, privatePerm os.FileMode, publicKeyFilename string, publicPEM []byte, publicPerm os.FileMode) error {
+	privateTemp, err := writeTempKeyFile(privateKeyFilename, privatePEM, privatePerm)
+	if err != nil {
+		return err
+	}
+
+	publicTemp, err := writeTempKeyFile(publicKeyFilename, publicPEM, publicPerm)
+	if err != nil {
+		os.Remove(privateTemp)
+		return err
+	}
+
+	privateBackup, err := backupKeyFile(privateKeyFilename)
+	if err != nil {
+		os.Remove(privateTemp)
+		os.Remove(publicTemp)
+		return err
+	}
+
+	// restore puts the old private key file, if any, back in place.
+	restore := func() {
+		if privateBackup != "" {
+			os.Rename(privateBackup, privateKeyFilename)
+		} else {
+			os.Remove(privateKeyFilename)
+		}
+	}
+
+	if err = os.Rename(privateTemp, privateKeyFilename); err != nil {
+		restore()
+		os.Remove(privateTemp)
+		os.Remove(publicTemp)
+		return err
+	}
+
+	if err = os.Rename(publicTemp, publicKeyFilename); err != nil {
+		restore()
+		os.Remove(publicTemp)
+		return err
+	}
+
+	if privateBackup != "" {
+		os.Remove(privateBackup)
+	}
+
+	return nil
+}
+
+// backupKeyFile moves the existing "filename" to a new temporary file
+// of the same directory and returns its name.
+// It returns an empty name if the "filename" does not exist.
+func backupKeyFile(filename string) (string, error) {
+	if _, err := os.Lstat(filename); err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+
+		return "", err
+	}
+
+	f, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*.bak")
+	if err != nil {
+		return "", err
+	}
+	f.Close()
+
+	if err = os.Rename(filename, f.Name()); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+
+	return f.Name(), nil
+}
+
+// writeTempKeyFile writes the "data" to a new temporary file
+// in the directory of the "filename" and returns its name.
+func writeTempKeyFile(filename string, data []byte, perm os.FileMode) (string, error) {
+	f, err := os.CreateTemp(filepath.Dir(filename), "."+filepath.Base(filename)+".*.tmp")
+	if err != nil {
+		return "", err
+	}
+
+	if err = writeAndClose(f, data, perm); err != nil {
+		os.Remove(f.Name())
+		return "", err
+	}
+
+	return f.Name(), nil
+}
+
+func writeAndClose(f *os.File, data []byte, perm os.FileMode) error {
+	// The permissions are not affected by the umask this way.
+	if err := f.Chmod(perm); err != nil {
+		f.Close()
+		return err
+	}
+
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
 
-	return publicPEM, privatePEM, nil
+	return f.Close()
 }
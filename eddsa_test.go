@@ -1,7 +1,19 @@
 package jwt
 
 import (
+	"bytes"
+	"crypto/rand"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
 	"testing"
+	"testing/iotest"
 )
 
 func TestEncodeDecodeTokenEdDSA(t *testing.T) {
@@ -22,6 +34,25 @@ func TestEncodeDecodeTokenEdDSA(t *testing.T) {
 	testEncodeDecodeToken(t, EdDSA, privateKey, privateKey, nil)
 }
 
+func TestLoadPrivateKeyEdDSAPKCS8(t *testing.T) {
+	for _, filename := range []string{
+		"./_testfiles/ed25519_private_key_openssl.pem", // openssl genpkey -algorithm ED25519
+		"./_testfiles/ed25519_private_key_go.pem",      // x509.MarshalPKCS8PrivateKey
+	} {
+		privateKey, err := LoadPrivateKeyEdDSA(filename)
+		if err != nil {
+			t.Fatalf("%s: %v", filename, err)
+		}
+
+		testEncodeDecodeToken(t, EdDSA, privateKey, privateKey, nil)
+	}
+
+	// Test a non ed25519 PKCS #8 key.
+	if _, err := LoadPrivateKeyEdDSA("./_testfiles/rsa_private_key.pem"); err == nil {
+		t.Fatalf("expected to fail on a non ed25519 key")
+	}
+}
+
 func TestMustLoadEdDSA(t *testing.T) {
 	catchPanic(t, false, func() {
 		MustLoadEdDSA("./_testfiles/ed25519_private_key.pem", "./_testfiles/ed25519_public_key.pem")
@@ -36,3 +67,557 @@ func TestMustLoadEdDSA(t *testing.T) {
 		MustLoadEdDSA("./invalid.pem", "./invalid.pem")
 	})
 }
+
+func TestParsePublicKeyEdDSAMalformed(t *testing.T) {
+	type algorithmIdentifier struct {
+		Algorithm asn1.ObjectIdentifier
+	}
+
+	for _, n := range []int{31, 33, 0} {
+		der, err := asn1.Marshal(struct {
+			Algorithm algorithmIdentifier
+			PublicKey asn1.BitString
+		}{
+			Algorithm: algorithmIdentifier{asn1.ObjectIdentifier{1, 3, 101, 112}},
+			PublicKey: asn1.BitString{Bytes: make([]byte, n), BitLength: 8 * n},
+		})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		key := pem.EncodeToMemory(&pem.Block{Type: "PUBLIC KEY", Bytes: der})
+		if _, err = ParsePublicKeyEdDSA(key); !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("[%d] expected error: %v but got: %v", n, ErrInvalidKey, err)
+		}
+	}
+
+	rsaPublicKey, err := os.ReadFile("./_testfiles/rsa_public_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = ParsePublicKeyEdDSA(rsaPublicKey); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidKey, err)
+	}
+}
+
+func TestParseRawPrivateKeyEdDSA(t *testing.T) {
+	privateKey, err := LoadPrivateKeyEdDSA("./_testfiles/ed25519_private_key.pem")
+	if err != nil {
+		t.Fatalf("EdDSA: private key: %v", err)
+	}
+
+	fromSeed, err := ParseRawPrivateKeyEdDSA(privateKey.Seed())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !privateKey.Equal(fromSeed) {
+		t.Fatalf("expected private key from seed to match")
+	}
+
+	fromKey, err := ParseRawPrivateKeyEdDSA(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !privateKey.Equal(fromKey) {
+		t.Fatalf("expected private key from raw key to match")
+	}
+
+	if _, err = ParseRawPrivateKeyEdDSA([]byte("short")); err != ErrInvalidKey {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidKey, err)
+	}
+}
+
+func TestNewEdDSAKeysFromRaw(t *testing.T) {
+	privateKey, publicKey := MustLoadEdDSA("./_testfiles/ed25519_private_key.pem", "./_testfiles/ed25519_public_key.pem")
+
+	fromSeed, err := NewEdDSAPrivateKeyFromSeed(privateKey.Seed())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !privateKey.Equal(fromSeed) {
+		t.Fatalf("expected private key from seed to match")
+	}
+
+	raw := []byte(publicKey)
+	fromRaw, err := NewEdDSAPublicKey(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !publicKey.Equal(fromRaw) {
+		t.Fatalf("expected public key from raw bytes to match")
+	}
+
+	token, err := Sign(EdDSA, fromSeed, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	raw[0] ^= 0xff // the returned key is a copy.
+	if _, err = Verify(EdDSA, fromRaw, token); err != nil {
+		t.Fatal(err)
+	}
+	raw[0] ^= 0xff
+
+	for _, n := range []int{0, 31, 33, 64} {
+		if _, err = NewEdDSAPublicKey(make([]byte, n)); err != ErrInvalidKey {
+			t.Fatalf("[%d] expected error: %v but got: %v", n, ErrInvalidKey, err)
+		}
+
+		if _, err = NewEdDSAPrivateKeyFromSeed(make([]byte, n)); err != ErrInvalidKey {
+			t.Fatalf("[%d] expected error: %v but got: %v", n, ErrInvalidKey, err)
+		}
+	}
+}
+
+func TestParseEdDSAFromReader(t *testing.T) {
+	f, err := os.Open("./_testfiles/ed25519_private_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+
+	if _, err = ParsePrivateKeyEdDSAFromReader(f); err != nil {
+		t.Fatalf("EdDSA: private key: %v", err)
+	}
+
+	b, err := os.ReadFile("./_testfiles/ed25519_public_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = ParsePublicKeyEdDSAFromReader(bytes.NewReader(b)); err != nil {
+		t.Fatalf("EdDSA: public key: %v", err)
+	}
+
+	// Test read errors are propagated.
+	readErr := errors.New("read error")
+	if _, err = ParsePublicKeyEdDSAFromReader(iotest.ErrReader(readErr)); err != readErr {
+		t.Fatalf("expected error: %v but got: %v", readErr, err)
+	}
+
+	// Test max key size.
+	prevMaxKeySize := MaxKeySize
+	t.Cleanup(func() {
+		MaxKeySize = prevMaxKeySize
+	})
+	MaxKeySize = int64(len(b) - 1)
+	if _, err = ParsePublicKeyEdDSAFromReader(bytes.NewReader(b)); err != ErrKeyTooLarge {
+		t.Fatalf("expected error: %v but got: %v", ErrKeyTooLarge, err)
+	}
+}
+
+func TestGenerateEdDSA(t *testing.T) {
+	privateKey, publicKey, err := GenerateEdDSA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	testEncodeDecodeToken(t, EdDSA, privateKey, publicKey, nil)
+
+	privatePEM, err := MarshalPrivateKeyEdDSA(privateKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicPEM, err := MarshalPublicKeyEdDSA(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parsedPrivateKey, err := ParsePrivateKeyEdDSA(privatePEM)
+	if err != nil {
+		t.Fatalf("EdDSA: private key: %v", err)
+	}
+
+	if !bytes.Equal(privateKey, parsedPrivateKey) {
+		t.Fatalf("expected parsed private key to match the generated one")
+	}
+
+	parsedPublicKey, err := ParsePublicKeyEdDSA(publicPEM)
+	if err != nil {
+		t.Fatalf("EdDSA: public key: %v", err)
+	}
+
+	if !bytes.Equal(publicKey, parsedPublicKey) {
+		t.Fatalf("expected parsed public key to match the generated one")
+	}
+}
+
+func TestGenerateKeyPairToFiles(t *testing.T) {
+	dir := t.TempDir()
+	privateKeyFilename, publicKeyFilename := filepath.Join(dir, "private.pem"), filepath.Join(dir, "public.pem")
+
+	if err := GenerateKeyPairToFiles(privateKeyFilename, publicKeyFilename, 0, false); err != nil {
+		t.Fatal(err)
+	}
+
+	privateKey, publicKey := MustLoadEdDSA(privateKeyFilename, publicKeyFilename)
+	testEncodeDecodeToken(t, EdDSA, privateKey, publicKey, nil)
+
+	if runtime.GOOS != "windows" {
+		for filename, perm := range map[string]os.FileMode{privateKeyFilename: 0600, publicKeyFilename: 0644} {
+			info, err := os.Stat(filename)
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			if got := info.Mode().Perm(); got != perm {
+				t.Fatalf("%s: expected permissions: %v but got: %v", filename, perm, got)
+			}
+		}
+	}
+
+	// Existing files are not overwritten.
+	if err := GenerateKeyPairToFiles(privateKeyFilename, publicKeyFilename, 0, false); !errors.Is(err, os.ErrExist) {
+		t.Fatalf("expected error: %v but got: %v", os.ErrExist, err)
+	}
+
+	loadedPrivateKey, err := LoadPrivateKeyEdDSA(privateKeyFilename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(loadedPrivateKey, privateKey) {
+		t.Fatalf("expected the private key to be left untouched")
+	}
+
+	// A new private key file is removed when the public one exists.
+	newPrivateKeyFilename := filepath.Join(dir, "new_private.pem")
+	if err = GenerateKeyPairToFiles(newPrivateKeyFilename, publicKeyFilename, 0, false); !errors.Is(err, os.ErrExist) {
+		t.Fatalf("expected error: %v but got: %v", os.ErrExist, err)
+	}
+
+	if _, err = os.Stat(newPrivateKeyFilename); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected the new private key file to be removed but got: %v", err)
+	}
+
+	// Overwrite.
+	if err = GenerateKeyPairToFiles(privateKeyFilename, publicKeyFilename, 0400, true); err != nil {
+		t.Fatal(err)
+	}
+
+	newPrivateKey, newPublicKey := MustLoadEdDSA(privateKeyFilename, publicKeyFilename)
+	if bytes.Equal(newPrivateKey, privateKey) || bytes.Equal(newPublicKey, publicKey) {
+		t.Fatalf("expected the keys to be overwritten")
+	}
+
+	if runtime.GOOS != "windows" {
+		info, err := os.Stat(privateKeyFilename)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := info.Mode().Perm(); got != 0400 {
+			t.Fatalf("expected permissions: %v but got: %v", os.FileMode(0400), got)
+		}
+	}
+
+	// A failed overwrite leaves the existing key pair untouched.
+	if err = GenerateKeyPairToFiles(privateKeyFilename, filepath.Join(dir, "missing", "public.pem"), 0, true); !errors.Is(err, os.ErrNotExist) {
+		t.Fatalf("expected error: %v but got: %v", os.ErrNotExist, err)
+	}
+
+	if loadedPrivateKey, _ := MustLoadEdDSA(privateKeyFilename, publicKeyFilename); !bytes.Equal(loadedPrivateKey, newPrivateKey) {
+		t.Fatalf("expected the private key to be left untouched")
+	}
+
+	// The old private key is restored when the public key file can not be replaced,
+	// a file can not be renamed over a non-empty directory.
+	publicKeyDir := filepath.Join(dir, "public_dir")
+	if err = os.MkdirAll(filepath.Join(publicKeyDir, "keep"), 0700); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = GenerateKeyPairToFiles(privateKeyFilename, publicKeyDir, 0, true); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if loadedPrivateKey, _ := MustLoadEdDSA(privateKeyFilename, publicKeyFilename); !bytes.Equal(loadedPrivateKey, newPrivateKey) {
+		t.Fatalf("expected the private key to be restored")
+	}
+
+	if err = os.RemoveAll(publicKeyDir); err != nil {
+		t.Fatal(err)
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(entries) != 2 {
+		t.Fatalf("expected only the key files but got: %d files", len(entries))
+	}
+}
+
+func TestLoadEdDSAFromEnv(t *testing.T) {
+	privateKey, publicKey := MustLoadEdDSA("./_testfiles/ed25519_private_key.pem", "./_testfiles/ed25519_public_key.pem")
+
+	privatePEM, err := os.ReadFile("./_testfiles/ed25519_private_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicPEM, err := os.ReadFile("./_testfiles/ed25519_public_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for name, encode := range map[string]func([]byte) string{
+		"raw":    func(b []byte) string { return string(b) },
+		"base64": base64.StdEncoding.EncodeToString,
+		"raw base64 with new line": func(b []byte) string {
+			return base64.RawStdEncoding.EncodeToString(b) + "\n"
+		},
+	} {
+		t.Setenv("JWT_TEST_PRIVATE_KEY", encode(privatePEM))
+		t.Setenv("JWT_TEST_PUBLIC_KEY", encode(publicPEM))
+
+		gotPrivateKey, err := LoadPrivateKeyEdDSAFromEnv("JWT_TEST_PRIVATE_KEY")
+		if err != nil {
+			t.Fatalf("[%s] %v", name, err)
+		}
+
+		if !privateKey.Equal(gotPrivateKey) {
+			t.Fatalf("[%s] expected private key to match", name)
+		}
+
+		gotPublicKey, err := LoadPublicKeyEdDSAFromEnv("JWT_TEST_PUBLIC_KEY")
+		if err != nil {
+			t.Fatalf("[%s] %v", name, err)
+		}
+
+		if !publicKey.Equal(gotPublicKey) {
+			t.Fatalf("[%s] expected public key to match", name)
+		}
+	}
+
+	t.Setenv("JWT_TEST_PRIVATE_KEY", " ")
+	if _, err = LoadPrivateKeyEdDSAFromEnv("JWT_TEST_PRIVATE_KEY"); !errors.Is(err, ErrEnvKeyNotSet) || !strings.Contains(err.Error(), "JWT_TEST_PRIVATE_KEY") {
+		t.Fatalf("expected error: %v naming the variable but got: %v", ErrEnvKeyNotSet, err)
+	}
+
+	if _, err = LoadPublicKeyEdDSAFromEnv("JWT_TEST_UNSET_PUBLIC_KEY"); !errors.Is(err, ErrEnvKeyNotSet) || !strings.Contains(err.Error(), "JWT_TEST_UNSET_PUBLIC_KEY") {
+		t.Fatalf("expected error: %v naming the variable but got: %v", ErrEnvKeyNotSet, err)
+	}
+
+	t.Setenv("JWT_TEST_PUBLIC_KEY", "not a key")
+	if _, err = LoadPublicKeyEdDSAFromEnv("JWT_TEST_PUBLIC_KEY"); err == nil || !strings.Contains(err.Error(), "JWT_TEST_PUBLIC_KEY") {
+		t.Fatalf("expected a parse error naming the variable but got: %v", err)
+	}
+}
+
+func TestLoadPrivateKeyEdDSAWithPassword(t *testing.T) {
+	expected, err := LoadPrivateKeyEdDSA("./_testfiles/ed25519_private_key.pem")
+	if err != nil {
+		t.Fatalf("EdDSA: private key: %v", err)
+	}
+
+	// Generated through:
+	// $ openssl pkcs8 -topk8 -in ed25519_private_key.pem -v2 aes-256-cbc -passout pass:secret
+	filename := "./_testfiles/ed25519_private_key_encrypted.pem"
+	got, err := LoadPrivateKeyEdDSAWithPassword(filename, "secret")
+	if err != nil {
+		t.Fatalf("EdDSA: encrypted private key: %v", err)
+	}
+
+	if !expected.Equal(got) {
+		t.Fatalf("expected decrypted private key to match")
+	}
+
+	if _, err = LoadPrivateKeyEdDSA(filename); err != ErrEncryptedKeyNoPassword {
+		t.Fatalf("expected error: %v but got: %v", ErrEncryptedKeyNoPassword, err)
+	}
+
+	if _, err = LoadPrivateKeyEdDSAWithPassword(filename, "invalid"); err == nil {
+		t.Fatalf("expected error on invalid password")
+	}
+
+	// Test legacy encryption.
+	der, err := x509.MarshalPKCS8PrivateKey(expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+	block, err := x509.EncryptPEMBlock(rand.Reader, "PRIVATE KEY", der, []byte("secret"), x509.PEMCipherAES256) // ignore:lint
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err = ParsePrivateKeyEdDSAWithPassword(pem.EncodeToMemory(block), "secret")
+	if err != nil {
+		t.Fatalf("EdDSA: legacy encrypted private key: %v", err)
+	}
+
+	if !expected.Equal(got) {
+		t.Fatalf("expected decrypted private key to match")
+	}
+
+	if _, err = ParsePrivateKeyEdDSA(pem.EncodeToMemory(block)); err != ErrEncryptedKeyNoPassword {
+		t.Fatalf("expected error: %v but got: %v", ErrEncryptedKeyNoPassword, err)
+	}
+}
+
+func TestDecryptPKCS8IterationCount(t *testing.T) {
+	encrypted := func(iterationCount int) []byte {
+		t.Helper()
+
+		marshal := func(v interface{}) []byte {
+			b, err := asn1.Marshal(v)
+			if err != nil {
+				t.Fatal(err)
+			}
+			return b
+		}
+
+		kdf := marshal(pbkdf2Params{Salt: []byte("saltsalt"), IterationCount: iterationCount})
+		iv := marshal(make([]byte, 16))
+		params := marshal(pbes2Params{
+			KeyDerivationFunc: pkixAlgorithmIdentifier{Algorithm: oidPBKDF2, Parameters: asn1.RawValue{FullBytes: kdf}},
+			EncryptionScheme:  pkixAlgorithmIdentifier{Algorithm: oidAES256CBC, Parameters: asn1.RawValue{FullBytes: iv}},
+		})
+
+		return marshal(encryptedPrivateKeyInfo{
+			Algorithm:     pkixAlgorithmIdentifier{Algorithm: oidPBES2, Parameters: asn1.RawValue{FullBytes: params}},
+			EncryptedData: make([]byte, 16),
+		})
+	}
+
+	// A crafted key file can not pin the CPU.
+	for _, iterationCount := range []int{0, -1, maxPBKDF2Iterations + 1, 1<<31 - 1} {
+		if _, err := decryptPKCS8(encrypted(iterationCount), []byte("secret")); err == nil || !strings.Contains(err.Error(), "iteration count out of range") {
+			t.Fatalf("%d: expected an iteration count error but got: %v", iterationCount, err)
+		}
+	}
+
+	// The data is not a key, the password is wrong most of the times.
+	if _, err := decryptPKCS8(encrypted(1), []byte("secret")); err == nil || strings.Contains(err.Error(), "iteration count") {
+		t.Fatalf("expected a decryption error but got: %v", err)
+	}
+}
+
+func TestLoadEdDSAMultiplePEMBlocks(t *testing.T) {
+	privateKey, publicKey := MustLoadEdDSA("./_testfiles/ed25519_private_key.pem", "./_testfiles/ed25519_public_key.pem")
+
+	// A certificate block before the key.
+	gotPrivateKey, err := LoadPrivateKeyEdDSA("./_testfiles/ed25519_cert_and_private_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !privateKey.Equal(gotPrivateKey) {
+		t.Fatalf("expected private key to match")
+	}
+
+	gotPublicKey, err := LoadPublicKeyEdDSA("./_testfiles/ed25519_cert_and_public_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !publicKey.Equal(gotPublicKey) {
+		t.Fatalf("expected public key to match")
+	}
+
+	// No block of the expected type.
+	if _, err = LoadPublicKeyEdDSA("./_testfiles/ed25519_cert_and_private_key.pem"); !errors.Is(err, ErrPEMBlockNotFound) {
+		t.Fatalf("expected error: %v but got: %v", ErrPEMBlockNotFound, err)
+	}
+
+	if _, err = LoadPrivateKeyEdDSA("./_testfiles/ed25519_public_key.pem"); !errors.Is(err, ErrPEMBlockNotFound) {
+		t.Fatalf("expected error: %v but got: %v", ErrPEMBlockNotFound, err)
+	}
+}
+
+func TestLoadKeyPairEdDSA(t *testing.T) {
+	privateKey, publicKey := MustLoadEdDSA("./_testfiles/ed25519_private_key.pem", "./_testfiles/ed25519_public_key.pem")
+
+	for _, filename := range []string{"./_testfiles/ed25519_key_pair.pem", "./_testfiles/ed25519_private_key.pem"} {
+		gotPrivateKey, gotPublicKey, err := LoadKeyPairEdDSA(filename)
+		if err != nil {
+			t.Fatalf("%s: %v", filename, err)
+		}
+
+		if !privateKey.Equal(gotPrivateKey) || !publicKey.Equal(gotPublicKey) {
+			t.Fatalf("%s: expected the key pair to match", filename)
+		}
+	}
+
+	if _, _, err := LoadKeyPairEdDSA("./_testfiles/ed25519_public_key.pem"); !errors.Is(err, ErrPEMBlockNotFound) {
+		t.Fatalf("expected error: %v but got: %v", ErrPEMBlockNotFound, err)
+	}
+
+	// A public key of another private key.
+	otherPrivateKey, err := os.ReadFile("./_testfiles/ed25519_private_key_go.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKeyPEM, err := os.ReadFile("./_testfiles/ed25519_public_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	filename := t.TempDir() + "/mismatch.pem"
+	if err = os.WriteFile(filename, append(otherPrivateKey, publicKeyPEM...), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, _, err = LoadKeyPairEdDSA(filename); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidKey, err)
+	}
+}
+
+func TestParseEdDSAPublicKeyFromCertPEM(t *testing.T) {
+	publicKey, err := LoadPublicKeyEdDSA("./_testfiles/ed25519_public_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cert, err := os.ReadFile("./_testfiles/ed25519_cert.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	got, err := ParseEdDSAPublicKeyFromCertPEM(cert)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !publicKey.Equal(got) {
+		t.Fatalf("expected the certificate's public key to match")
+	}
+
+	// Verify the (self-signed) certificate first.
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(cert) {
+		t.Fatalf("expected the certificate to be added to the pool")
+	}
+
+	if got, err = ParseEdDSAPublicKeyFromCertPEMWithRoots(cert, roots); err != nil {
+		t.Fatal(err)
+	} else if !publicKey.Equal(got) {
+		t.Fatalf("expected the verified certificate's public key to match")
+	}
+
+	if _, err = ParseEdDSAPublicKeyFromCertPEMWithRoots(cert, x509.NewCertPool()); err == nil {
+		t.Fatalf("expected error on unknown authority")
+	}
+
+	ecdsaCert, err := os.ReadFile("./_testfiles/ecdsa_cert.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = ParseEdDSAPublicKeyFromCertPEM(ecdsaCert); !errors.Is(err, ErrInvalidKey) {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidKey, err)
+	}
+
+	publicKeyPEM, err := os.ReadFile("./_testfiles/ed25519_public_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = ParseEdDSAPublicKeyFromCertPEM(publicKeyPEM); !errors.Is(err, ErrPEMBlockNotFound) {
+		t.Fatalf("expected error: %v but got: %v", ErrPEMBlockNotFound, err)
+	}
+}
@@ -0,0 +1,263 @@
+// Package es256k implements the "ES256K" JSON Web Algorithm (RFC 8812),
+// ECDSA using the secp256k1 curve and SHA-256.
+//
+// It lives in its own module so the users who don't need it
+// don't pull the secp256k1 dependency.
+//
+// Usage:
+//
+//  privateKey, publicKey := es256k.MustLoad("./private_key.pem", "./public_key.pem")
+//  token, err := jwt.Sign(es256k.ES256K, privateKey, claims, jwt.MaxAge(15*time.Minute))
+//  verifiedToken, err := jwt.Verify(es256k.ES256K, publicKey, token)
+package es256k
+
+import (
+	"crypto/sha256"
+	"encoding/asn1"
+	"encoding/pem"
+	"fmt"
+
+	"github.com/kataras/jwt"
+
+	"github.com/decred/dcrd/dcrec/secp256k1/v4"
+	"github.com/decred/dcrd/dcrec/secp256k1/v4/ecdsa"
+)
+
+// ES256K is the ECDSA using secp256k1 curve and SHA-256 signing algorithm.
+// Sign   key: *secp256k1.PrivateKey
+// Verify key: *secp256k1.PublicKey (or *secp256k1.PrivateKey)
+//
+// Generate a private key:
+// $ openssl ecparam -name secp256k1 -genkey -noout -out private_key.pem
+// Derive the public key from the private key:
+// $ openssl ec -in private_key.pem -pubout -out public_key.pem
+var ES256K jwt.Alg = &algES256K{"ES256K"}
+
+// The size in bytes of each one of the R and S signature values.
+const keySize = 32
+
+type algES256K struct {
+	name string
+}
+
+var (
+	_ jwt.AlgParser         = (*algES256K)(nil)
+	_ jwt.AlgSignatureSizer = (*algES256K)(nil)
+)
+
+func (a *algES256K) Parse(private, public []byte) (privateKey jwt.PrivateKey, publicKey jwt.PublicKey, err error) {
+	if len(private) > 0 {
+		privateKey, err = ParsePrivateKey(private)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ES256K: private key: %v", err)
+		}
+	}
+
+	if len(public) > 0 {
+		publicKey, err = ParsePublicKey(public)
+		if err != nil {
+			return nil, nil, fmt.Errorf("ES256K: public key: %v", err)
+		}
+	}
+
+	return
+}
+
+func (a *algES256K) Name() string {
+	return a.name
+}
+
+// ExpectedSignatureSize completes the jwt.AlgSignatureSizer,
+// it returns the length of the R and S concatenation.
+func (a *algES256K) ExpectedSignatureSize(key jwt.PublicKey) int {
+	switch key.(type) {
+	case *secp256k1.PublicKey, *secp256k1.PrivateKey:
+		return 2 * keySize
+	default:
+		return 0
+	}
+}
+
+func (a *algES256K) Sign(key jwt.PrivateKey, headerAndPayload []byte) ([]byte, error) {
+	privateKey, ok := key.(*secp256k1.PrivateKey)
+	if !ok {
+		return nil, jwt.ErrInvalidKey
+	}
+
+	hashed := sha256.Sum256(headerAndPayload)
+	sig := ecdsa.Sign(privateKey, hashed[:]) // RFC 6979 deterministic signature.
+
+	r, s := sig.R(), sig.S()
+	rBytes, sBytes := r.Bytes(), s.Bytes()
+
+	signature := make([]byte, 2*keySize)
+	copy(signature, rBytes[:])
+	copy(signature[keySize:], sBytes[:])
+	return signature, nil
+}
+
+func (a *algES256K) Verify(key jwt.PublicKey, headerAndPayload []byte, signature []byte) error {
+	publicKey, ok := key.(*secp256k1.PublicKey)
+	if !ok {
+		if privateKey, ok := key.(*secp256k1.PrivateKey); ok {
+			publicKey = privateKey.PubKey()
+		} else {
+			return jwt.ErrInvalidKey
+		}
+	}
+
+	// The signature is the R and S fixed-width, big-endian, values concatenation.
+	if len(signature) != 2*keySize {
+		return jwt.ErrTokenSignature
+	}
+
+	var r, s secp256k1.ModNScalar
+	if overflow := r.SetByteSlice(signature[:keySize]); overflow || r.IsZero() {
+		return jwt.ErrTokenSignature
+	}
+	if overflow := s.SetByteSlice(signature[keySize:]); overflow || s.IsZero() {
+		return jwt.ErrTokenSignature
+	}
+
+	hashed := sha256.Sum256(headerAndPayload)
+	if !ecdsa.NewSignature(&r, &s).Verify(hashed[:], publicKey) {
+		return jwt.ErrTokenSignature
+	}
+
+	return nil
+}
+
+// Key Helpers.
+
+var (
+	oidPublicKeyECDSA = asn1.ObjectIdentifier{1, 2, 840, 10045, 2, 1}
+	oidSecp256k1      = asn1.ObjectIdentifier{1, 3, 132, 0, 10}
+)
+
+type (
+	// RFC 5915, section 3.
+	ecPrivateKey struct {
+		Version       int
+		PrivateKey    []byte
+		NamedCurveOID asn1.ObjectIdentifier `asn1:"optional,explicit,tag:0"`
+		PublicKey     asn1.BitString        `asn1:"optional,explicit,tag:1"`
+	}
+
+	algorithmIdentifier struct {
+		Algorithm  asn1.ObjectIdentifier
+		Parameters asn1.ObjectIdentifier `asn1:"optional"`
+	}
+
+	// RFC 5208, section 5.
+	pkcs8 struct {
+		Version    int
+		Algo       algorithmIdentifier
+		PrivateKey []byte
+	}
+
+	// RFC 5280, section 4.1.
+	subjectPublicKeyInfo struct {
+		Algorithm algorithmIdentifier
+		PublicKey asn1.BitString
+	}
+)
+
+// MustLoad accepts private and public PEM filenames
+// and returns a pair of private and public secp256k1 keys.
+// Pass the returned private key to the `jwt.Sign` function
+// and the public key to the `jwt.Verify` function.
+//
+// It panics on errors.
+func MustLoad(privateKeyFilename, publicKeyFilename string) (*secp256k1.PrivateKey, *secp256k1.PublicKey) {
+	privateKey, err := LoadPrivateKey(privateKeyFilename)
+	if err != nil {
+		panic(err)
+	}
+
+	publicKey, err := LoadPublicKey(publicKeyFilename)
+	if err != nil {
+		panic(err)
+	}
+
+	return privateKey, publicKey
+}
+
+// LoadPrivateKey accepts a file path of a PEM-encoded secp256k1 private key
+// and returns the secp256k1 private key Go value.
+// It uses the `jwt.ReadFile` package-level variable to read the file.
+func LoadPrivateKey(filename string) (*secp256k1.PrivateKey, error) {
+	b, err := jwt.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePrivateKey(b)
+}
+
+// LoadPublicKey accepts a file path of a PEM-encoded secp256k1 public key
+// and returns the secp256k1 public key Go value.
+// It uses the `jwt.ReadFile` package-level variable to read the file.
+func LoadPublicKey(filename string) (*secp256k1.PublicKey, error) {
+	b, err := jwt.ReadFile(filename)
+	if err != nil {
+		return nil, err
+	}
+
+	return ParsePublicKey(b)
+}
+
+// ParsePrivateKey decodes and parses the
+// PEM-encoded secp256k1 private key's raw contents.
+// The key can be SEC 1 ("EC PRIVATE KEY") or PKCS #8 ("PRIVATE KEY") encoded.
+func ParsePrivateKey(key []byte) (*secp256k1.PrivateKey, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return nil, fmt.Errorf("private key: malformed or missing PEM format (ES256K)")
+	}
+
+	der := block.Bytes
+
+	var p8 pkcs8
+	if _, err := asn1.Unmarshal(der, &p8); err == nil && p8.Algo.Algorithm.Equal(oidPublicKeyECDSA) {
+		if !p8.Algo.Parameters.Equal(oidSecp256k1) {
+			return nil, fmt.Errorf("private key: unexpected curve: %s", p8.Algo.Parameters)
+		}
+
+		der = p8.PrivateKey
+	}
+
+	var ecKey ecPrivateKey
+	if _, err := asn1.Unmarshal(der, &ecKey); err != nil {
+		return nil, fmt.Errorf("private key: %w", err)
+	}
+
+	if len(ecKey.NamedCurveOID) > 0 && !ecKey.NamedCurveOID.Equal(oidSecp256k1) {
+		return nil, fmt.Errorf("private key: unexpected curve: %s", ecKey.NamedCurveOID)
+	}
+
+	if len(ecKey.PrivateKey) != keySize {
+		return nil, fmt.Errorf("private key: bad length: %d", len(ecKey.PrivateKey))
+	}
+
+	return secp256k1.PrivKeyFromBytes(ecKey.PrivateKey), nil
+}
+
+// ParsePublicKey decodes and parses the
+// PEM-encoded (PKIX) secp256k1 public key's raw contents.
+func ParsePublicKey(key []byte) (*secp256k1.PublicKey, error) {
+	block, _ := pem.Decode(key)
+	if block == nil {
+		return nil, fmt.Errorf("public key: malformed or missing PEM format (ES256K)")
+	}
+
+	var info subjectPublicKeyInfo
+	if _, err := asn1.Unmarshal(block.Bytes, &info); err != nil {
+		return nil, fmt.Errorf("public key: %w", err)
+	}
+
+	if !info.Algorithm.Algorithm.Equal(oidPublicKeyECDSA) || !info.Algorithm.Parameters.Equal(oidSecp256k1) {
+		return nil, fmt.Errorf("public key: expected a secp256k1 key")
+	}
+
+	return secp256k1.ParsePubKey(info.PublicKey.Bytes)
+}
@@ -0,0 +1,87 @@
+package es256k
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+
+	"github.com/kataras/jwt"
+)
+
+func TestES256K(t *testing.T) {
+	privateKey, publicKey := MustLoad("./_testfiles/es256k_private_key.pem", "./_testfiles/es256k_public_key.pem")
+
+	if !privateKey.PubKey().IsEqual(publicKey) {
+		t.Fatalf("expected public key to match the private one")
+	}
+
+	pkcs8Key, err := LoadPrivateKey("./_testfiles/es256k_private_key_pkcs8.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !privateKey.Key.Equals(&pkcs8Key.Key) {
+		t.Fatalf("expected pkcs8 private key to match")
+	}
+
+	claims := jwt.Map{"username": "kataras"}
+	token, err := jwt.Sign(ES256K, privateKey, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := jwt.Verify(ES256K, publicKey, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got jwt.Map
+	if err = verifiedToken.Claims(&got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got["username"] != claims["username"] {
+		t.Fatalf("expected claims: %#+v but got: %#+v", claims, got)
+	}
+
+	// test the automatic extract of public key from private key.
+	if _, err = jwt.Verify(ES256K, privateKey, token); err != nil {
+		t.Fatal(err)
+	}
+
+	// test truncated signature.
+	lastPartIdx := bytes.LastIndexByte(token, '.') + 1
+	signature, err := jwt.Base64Decode(token[lastPartIdx:])
+	if err != nil {
+		t.Fatal(err)
+	}
+	truncated := append(append([]byte(nil), token[:lastPartIdx]...), jwt.Base64Encode(signature[:len(signature)-1])...)
+	if _, err = jwt.Verify(ES256K, publicKey, truncated); !errors.Is(err, jwt.ErrInvalidSignatureLength) {
+		t.Fatalf("expected error: %v but got: %v", jwt.ErrInvalidSignatureLength, err)
+	}
+
+	// test invalid signature.
+	// the key, so the signature, differs on every run:
+	// make sure the changed character is a valid base64url one.
+	if token[len(token)-2] == 'A' {
+		token[len(token)-2] = 'B'
+	} else {
+		token[len(token)-2] = 'A'
+	}
+	if _, err = jwt.Verify(ES256K, publicKey, token); !errors.Is(err, jwt.ErrTokenSignature) {
+		t.Fatalf("expected error: %v but got: %v", jwt.ErrTokenSignature, err)
+	}
+
+	// test invalid keys.
+	if _, err = jwt.Sign(ES256K, []byte("secret"), claims); !errors.Is(err, jwt.ErrInvalidKey) {
+		t.Fatalf("expected error: %v but got: %v", jwt.ErrInvalidKey, err)
+	}
+
+	if _, err = LoadPublicKey("../_testfiles/ecdsa_public_key.pem"); err == nil {
+		t.Fatalf("expected error on P-256 public key")
+	}
+
+	if _, err = LoadPrivateKey("../_testfiles/ecdsa_private_key.pem"); err == nil {
+		t.Fatalf("expected error on P-256 private key")
+	}
+}
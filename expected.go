@@ -3,6 +3,7 @@ package jwt
 import (
 	"errors"
 	"fmt"
+	"strings"
 )
 
 // Expected is a TokenValidator which performs simple checks
@@ -54,7 +55,7 @@ func (e Expected) ValidateToken(token []byte, c Claims, err error) error {
 	}
 
 	if v := e.ID; v != "" {
-		if v != c.ID {
+		if !SecureCompare([]byte(v), []byte(c.ID)) { // the id may be a stored secret, e.g. of a refresh token.
 			return fmt.Errorf("%w: jti", ErrExpected)
 		}
 	}
@@ -85,3 +86,185 @@ func (e Expected) ValidateToken(token []byte, c Claims, err error) error {
 
 	return nil
 }
+
+var (
+	// ErrInvalidIssuer indicates that the token's "iss" claim
+	// does not match the expected issuer, see `ExpectIssuer`.
+	// It is an ErrExpected too.
+	ErrInvalidIssuer = fmt.Errorf("%w: iss", ErrExpected)
+	// ErrInvalidSubject indicates that the token's "sub" claim
+	// does not match the expected subject, see `ExpectSubject`.
+	// It is an ErrExpected too.
+	ErrInvalidSubject = fmt.Errorf("%w: sub", ErrExpected)
+)
+
+// ExpectIssuer is a TokenValidator which makes sure that
+// the token's "iss" claim is equal to the given "iss" value.
+// A token without an "iss" claim fails.
+//
+// It returns a type of ValidationError which wraps the ErrInvalidIssuer on validation failure.
+// It can be combined with the rest of the validators, e.g.
+//  verifiedToken, err := Verify(..., ClockSkew(time.Minute), ExpectIssuer("my-idp"), ExpectAudience("my-service"))
+func ExpectIssuer(iss string) TokenValidatorFunc {
+	return func(_ []byte, c Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if iss == "" || c.Issuer != iss {
+			return &ValidationError{Err: ErrInvalidIssuer, Claim: "iss", Expected: iss, Actual: c.Issuer}
+		}
+
+		return nil
+	}
+}
+
+// ExpectSubject is a TokenValidator which makes sure that
+// the token's "sub" claim is equal to the given "sub" value.
+// A token without a "sub" claim fails.
+//
+// It returns a type of ValidationError which wraps the ErrInvalidSubject on validation failure.
+func ExpectSubject(sub string) TokenValidatorFunc {
+	return func(_ []byte, c Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if sub == "" || c.Subject != sub {
+			return &ValidationError{Err: ErrInvalidSubject, Claim: "sub", Expected: sub, Actual: c.Subject}
+		}
+
+		return nil
+	}
+}
+
+// ErrInvalidAudience indicates that the token's "aud" claim
+// does not contain the expected audience, see `ExpectAudience`,
+// `ExpectAnyAudience` and `ExpectAllAudiences`.
+// It is an ErrExpected too.
+var ErrInvalidAudience = fmt.Errorf("%w: aud", ErrExpected)
+
+// ExpectAudience is a TokenValidator which makes sure that
+// the token's "aud" claim contains the given "aud" value.
+// The "aud" claim can be a single string or an array of strings,
+// a token without an "aud" claim fails.
+//
+// It returns a type of ValidationError which wraps the ErrInvalidAudience on validation failure.
+//
+// Usage:
+//  verifiedToken, err := Verify(..., ExpectAudience("my-service"))
+func ExpectAudience(aud string) TokenValidatorFunc {
+	return func(_ []byte, c Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if aud == "" || !c.Audience.Contains(aud) {
+			return &ValidationError{Err: ErrInvalidAudience, Claim: "aud", ExpectedAudience: aud, ActualAudience: c.Audience}
+		}
+
+		return nil
+	}
+}
+
+// ExpectAnyAudience is a TokenValidator which makes sure that
+// the token's "aud" claim contains at least one of the given "auds" values,
+// e.g. for a service which accepts both "api" and "api.internal" tokens.
+// A token without an "aud" claim fails, as well as an empty "auds".
+//
+// It returns a type of ValidationError which wraps the ErrInvalidAudience on validation failure,
+// its ExpectedAudiences field holds the "auds".
+//
+// Usage:
+//  verifiedToken, err := Verify(..., ExpectAnyAudience("api", "api.internal"))
+func ExpectAnyAudience(auds ...string) TokenValidatorFunc {
+	return func(_ []byte, c Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		for _, aud := range auds {
+			if aud != "" && c.Audience.Contains(aud) {
+				return nil
+			}
+		}
+
+		return &ValidationError{Err: ErrInvalidAudience, Claim: "aud", ExpectedAudiences: auds, ActualAudience: c.Audience}
+	}
+}
+
+// ExpectAllAudiences is a TokenValidator which makes sure that
+// the token's "aud" claim contains all of the given "auds" values.
+// A token without an "aud" claim fails, as well as an empty "auds".
+//
+// It returns a type of ValidationError which wraps the ErrInvalidAudience on validation failure,
+// its ExpectedAudiences field holds the "auds".
+//
+// Usage:
+//  verifiedToken, err := Verify(..., ExpectAllAudiences("api", "billing"))
+func ExpectAllAudiences(auds ...string) TokenValidatorFunc {
+	return func(_ []byte, c Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		ok := len(auds) > 0
+		for _, aud := range auds {
+			if aud == "" || !c.Audience.Contains(aud) {
+				ok = false
+				break
+			}
+		}
+
+		if !ok {
+			return &ValidationError{Err: ErrInvalidAudience, Claim: "aud", ExpectedAudiences: auds, ActualAudience: c.Audience}
+		}
+
+		return nil
+	}
+}
+
+// ErrInvalidType indicates that the token's "typ" header field
+// does not match the expected token type, e.g. a refresh token
+// passed to `VerifyAccessToken`, see `ExpectType` too.
+// It is an ErrExpected too.
+var ErrInvalidType = fmt.Errorf("%w: typ", ErrExpected)
+
+// ExpectType is a TokenValidator which makes sure that
+// the token's "typ" header field matches the given "typ" value,
+// e.g. "at+jwt" for OAuth 2.0 access tokens (RFC 9068).
+// The comparison is case-insensitive and the optional "application/" prefix is ignored,
+// so "JWT", "jwt" and "application/jwt" are all equal.
+// A token without a "typ" header field fails.
+//
+// It returns ErrInvalidType on validation failure.
+// See the `WithType` SignOption too.
+//
+// Usage:
+//  verifiedToken, err := Verify(..., ExpectType("at+jwt"))
+func ExpectType(typ string) TokenValidatorFunc {
+	expected := normalizeType(typ)
+
+	return func(token []byte, _ Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		header, headerErr := DecodeHeader(token) // the token is already verified.
+		if headerErr != nil {
+			return headerErr
+		}
+
+		if expected == "" || normalizeType(header.Typ) != expected {
+			return ErrInvalidType
+		}
+
+		return nil
+	}
+}
+
+// normalizeType returns the lower-case "typ" without the "application/" prefix (RFC 7515, section 4.1.9).
+func normalizeType(typ string) string {
+	typ = strings.ToLower(typ)
+	return strings.TrimPrefix(typ, "application/")
+}
@@ -4,6 +4,7 @@ import (
 	"errors"
 	"fmt"
 	"testing"
+	"time"
 )
 
 func TestExpected(t *testing.T) {
@@ -147,3 +148,188 @@ func TestExpected(t *testing.T) {
 		t.Fatalf("expected error: %v but got: %v", expectedErr, gotErr)
 	}
 }
+
+func TestExpectAudience(t *testing.T) {
+	var tests = []struct {
+		payload string
+		ok      bool
+	}{
+		{`{"aud":"my-service"}`, true},
+		{`{"aud":["other","my-service"]}`, true},
+		{`{"aud":"other"}`, false},
+		{`{"aud":["other"]}`, false},
+		{`{"aud":[]}`, false},
+		{`{"aud":""}`, false},
+		{`{}`, false},
+	}
+
+	validator := ExpectAudience("my-service")
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, []byte(tt.payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = Verify(testAlg, testSecret, token, validator)
+		if tt.ok && err != nil {
+			t.Fatalf("[%d] expected to pass but got error: %v", i, err)
+		}
+
+		if !tt.ok {
+			if !errors.Is(err, ErrInvalidAudience) {
+				t.Fatalf("[%d] expected error: %v but got: %v", i, ErrInvalidAudience, err)
+			}
+
+			if !errors.Is(err, ErrExpected) {
+				t.Fatalf("[%d] expected error to be an ErrExpected too", i)
+			}
+		}
+	}
+}
+
+func TestExpectAnyAndAllAudiences(t *testing.T) {
+	var tests = []struct {
+		payload string
+		any     bool
+		all     bool
+	}{
+		{`{"aud":"api"}`, true, false},
+		{`{"aud":["other","api.internal"]}`, true, false},
+		{`{"aud":["api.internal","other","api"]}`, true, true},
+		{`{"aud":["api","api.internal"]}`, true, true},
+		{`{"aud":["other"]}`, false, false},
+		{`{"aud":[]}`, false, false},
+		{`{}`, false, false},
+	}
+
+	expected := []string{"api", "api.internal"}
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, []byte(tt.payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		for _, v := range []struct {
+			validator TokenValidator
+			ok        bool
+		}{
+			{ExpectAnyAudience(expected...), tt.any},
+			{ExpectAllAudiences(expected...), tt.all},
+		} {
+			_, err = Verify(testAlg, testSecret, token, v.validator)
+			if v.ok && err != nil {
+				t.Fatalf("[%d] expected to pass but got error: %v", i, err)
+			}
+
+			if !v.ok {
+				var vErr *ValidationError
+				if !errors.As(err, &vErr) || !errors.Is(err, ErrInvalidAudience) {
+					t.Fatalf("[%d] expected error: %v but got: %v", i, ErrInvalidAudience, err)
+				}
+
+				if len(vErr.ExpectedAudiences) != len(expected) {
+					t.Fatalf("[%d] expected audiences: %q but got: %q", i, expected, vErr.ExpectedAudiences)
+				}
+			}
+		}
+	}
+
+	token, err := Sign(testAlg, testSecret, Claims{Audience: []string{"api"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, validator := range []TokenValidator{ExpectAnyAudience(), ExpectAllAudiences()} {
+		if _, err = Verify(testAlg, testSecret, token, validator); !errors.Is(err, ErrInvalidAudience) {
+			t.Fatalf("expected error on empty audiences: %v but got: %v", ErrInvalidAudience, err)
+		}
+	}
+}
+
+func TestExpectIssuerAndSubject(t *testing.T) {
+	prevClock := Clock
+	defer func() {
+		Clock = prevClock
+	}()
+
+	now := time.Now()
+	Clock = func() time.Time {
+		return now
+	}
+
+	validators := []TokenValidator{
+		ClockSkew(time.Minute), // the skew should be the first one, so the rest can see its result.
+		ExpectIssuer("my-idp"),
+		ExpectSubject("kataras"),
+		ExpectAudience("my-service"),
+	}
+
+	var tests = []struct {
+		claims  Claims
+		wantErr error
+	}{
+		{Claims{Issuer: "my-idp", Subject: "kataras", Audience: []string{"my-service"}}, nil},
+		{Claims{Issuer: "my-idp", Subject: "kataras", Audience: []string{"my-service"}, Expiry: now.Add(-30 * time.Second).Unix()}, nil},
+		{Claims{Issuer: "my-idp", Subject: "kataras", Audience: []string{"my-service"}, Expiry: now.Add(-2 * time.Minute).Unix()}, ErrExpired},
+		{Claims{Issuer: "other", Subject: "kataras", Audience: []string{"my-service"}}, ErrInvalidIssuer},
+		{Claims{Subject: "kataras", Audience: []string{"my-service"}}, ErrInvalidIssuer},
+		{Claims{Issuer: "my-idp", Subject: "other", Audience: []string{"my-service"}}, ErrInvalidSubject},
+		{Claims{Issuer: "my-idp", Audience: []string{"my-service"}}, ErrInvalidSubject},
+		{Claims{Issuer: "my-idp", Subject: "kataras"}, ErrInvalidAudience},
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, tt.claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = Verify(testAlg, testSecret, token, validators...)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.wantErr, err)
+		}
+	}
+}
+
+func TestExpectType(t *testing.T) {
+	var tests = []struct {
+		typ      string
+		expected string
+		ok       bool
+	}{
+		{"", "JWT", true}, // the default typ.
+		{"JWT", "jwt", true},
+		{"JWT", "application/jwt", true},
+		{"at+jwt", "at+jwt", true},
+		{"application/at+jwt", "at+jwt", true},
+		{"AT+JWT", "application/at+jwt", true},
+		{"JWT", "at+jwt", false},
+		{"at+jwt", "JWT", false},
+		{"at+jwt", "", false},
+	}
+
+	for i, tt := range tests {
+		var opts []SignOption
+		if tt.typ != "" {
+			opts = append(opts, WithType(tt.typ))
+		}
+
+		token, err := Sign(testAlg, testSecret, Map{"foo": "bar"}, opts...)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = Verify(testAlg, testSecret, token, ExpectType(tt.expected))
+		if tt.ok {
+			if err != nil {
+				t.Fatalf("[%d] expected to pass but got error: %v", i, err)
+			}
+
+			continue
+		}
+
+		if err != ErrInvalidType || !errors.Is(err, ErrExpected) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, ErrInvalidType, err)
+		}
+	}
+}
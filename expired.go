@@ -0,0 +1,43 @@
+package jwt
+
+import "time"
+
+// VerifyAllowExpired same as `Verify` but it accepts a token which expired
+// up to "maxExpiredFor" ago, e.g. for a refresh endpoint which
+// renews a recently expired access token. The signature, the "nbf" and "iat" claims
+// and the validators are verified as usual.
+// It reports whether the token is expired, instead of failing with ErrExpired.
+//
+// It's a separate function, not a TokenValidator, on purpose:
+// it can not slip into the main authorization path through a `Profile` or a `Verifier`'s defaults.
+// A zero or negative "maxExpiredFor" accepts no expired token at all.
+//
+// Usage:
+//  verifiedToken, expired, err := jwt.VerifyAllowExpired(jwt.EdDSA, publicKey, accessToken, 5*time.Minute)
+//  [handle error...]
+//  if expired {
+//      [renew the access token...]
+//  }
+func VerifyAllowExpired(alg Alg, key PublicKey, token []byte, maxExpiredFor time.Duration, validators ...TokenValidator) (*VerifiedToken, bool, error) {
+	expired := false
+	allowExpired := TimeValidatorFunc(func(now time.Time, _ []byte, standardClaims Claims, err error) error {
+		if err != ErrExpired || maxExpiredFor <= 0 {
+			return err
+		}
+
+		if now.Round(time.Second).Unix()-standardClaims.Expiry > int64(maxExpiredFor/time.Second) {
+			return err
+		}
+
+		expired = true
+		return nil
+	})
+
+	// It should be the first one, so the rest see its result.
+	verifiedToken, err := Verify(alg, key, token, joinValidators([]TokenValidator{allowExpired}, validators)...)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return verifiedToken, expired, nil
+}
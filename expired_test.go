@@ -0,0 +1,75 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestVerifyAllowExpired(t *testing.T) {
+	prevClock := Clock
+	defer func() {
+		Clock = prevClock
+	}()
+
+	now := time.Date(2020, 10, 26, 1, 1, 1, 0, time.UTC)
+	Clock = func() time.Time {
+		return now
+	}
+
+	var tests = []struct {
+		claims     Claims
+		validators []TokenValidator
+		expired    bool
+		wantErr    error
+	}{
+		{Claims{Expiry: now.Add(time.Minute).Unix()}, nil, false, nil},
+		{Claims{Expiry: now.Add(-30 * time.Second).Unix()}, nil, true, nil},
+		// Exactly at the boundary and a second after it.
+		{Claims{Expiry: now.Add(-5 * time.Minute).Unix()}, nil, true, nil},
+		{Claims{Expiry: now.Add(-5*time.Minute - time.Second).Unix()}, nil, false, ErrExpired},
+		// The rest claims and validators are still verified.
+		{Claims{Expiry: now.Add(-30 * time.Second).Unix(), NotBefore: now.Add(-time.Hour).Unix(), Issuer: "my-app"}, []TokenValidator{ExpectIssuer("my-app")}, true, nil},
+		{Claims{Expiry: now.Add(-30 * time.Second).Unix(), Issuer: "other"}, []TokenValidator{ExpectIssuer("my-app")}, false, ErrExpected},
+		{Claims{Expiry: now.Add(-30 * time.Second).Unix(), IssuedAt: now.Add(time.Minute).Unix()}, nil, false, ErrInconsistentClaims},
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, tt.claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		verifiedToken, expired, err := VerifyAllowExpired(testAlg, testSecret, token, 5*time.Minute, tt.validators...)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.wantErr, err)
+		}
+
+		if expired != tt.expired {
+			t.Fatalf("[%d] expected expired: %v but got: %v", i, tt.expired, expired)
+		}
+
+		if err == nil && verifiedToken.StandardClaims.Expiry != tt.claims.Expiry {
+			t.Fatalf("[%d] expected the claims to be read", i)
+		}
+	}
+
+	token, err := Sign(testAlg, testSecret, Claims{Expiry: now.Add(-30 * time.Second).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The main path is not affected.
+	if _, err = Verify(testAlg, testSecret, token); err != ErrExpired {
+		t.Fatalf("expected error: %v but got: %v", ErrExpired, err)
+	}
+
+	if _, expired, err := VerifyAllowExpired(testAlg, testSecret, token, 0); !errors.Is(err, ErrExpired) || expired {
+		t.Fatalf("expected error: %v but got: %v (expired: %v)", ErrExpired, err, expired)
+	}
+
+	// The signature is verified.
+	if _, _, err = VerifyAllowExpired(testAlg, []byte("other"), token, time.Hour); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+}
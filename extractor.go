@@ -0,0 +1,85 @@
+package jwt
+
+import (
+	"errors"
+	"net/http"
+)
+
+// TokenExtractor extracts the token of an HTTP request.
+// It should return an ErrMissing error when the request does not hold a token at all,
+// so a `Chain` of extractors can try the next one.
+// See `MiddlewareWithExtractor`.
+type TokenExtractor func(r *http.Request) ([]byte, error)
+
+// FromAuthHeader is a TokenExtractor which reads the token of the
+// "Authorization: Bearer $token" request header.
+// The scheme is case-insensitive, e.g. "bearer" and "BEARER" are accepted too,
+// and any spaces or tabs around the scheme and the token are trimmed.
+// It returns an ErrAuthorizationScheme error on a non "Bearer" header
+// and an ErrMissingBearerToken one on a header without the scheme or the token.
+// It's the TokenExtractor of the `Middleware`.
+var FromAuthHeader TokenExtractor = bearerToken
+
+// FromCookie returns a TokenExtractor which reads the token
+// of the "name" request cookie.
+//
+// Usage:
+//  jwt.MiddlewareWithExtractor(jwt.FromCookie("access_token"), jwt.EdDSA, publicKey)
+func FromCookie(name string) TokenExtractor {
+	return func(r *http.Request) ([]byte, error) {
+		cookie, err := r.Cookie(name)
+		if err != nil || cookie.Value == "" {
+			return nil, ErrMissing
+		}
+
+		return []byte(cookie.Value), nil
+	}
+}
+
+// FromQuery returns a TokenExtractor which reads the token
+// of the "param" URL query parameter.
+//
+// It's discouraged: the URLs, and so the tokens,
+// are written to the access logs of servers and proxies and to the browser's history,
+// prefer the `FromAuthHeader` or `FromCookie` ones.
+// It's supported for compatibility with legacy clients.
+func FromQuery(param string) TokenExtractor {
+	return func(r *http.Request) ([]byte, error) {
+		token := r.URL.Query().Get(param)
+		if token == "" {
+			return nil, ErrMissing
+		}
+
+		return []byte(token), nil
+	}
+}
+
+// Chain returns a TokenExtractor which tries the given extractors in order
+// and returns the first extracted token.
+// When no extractor finds a token, it returns the first error
+// which is not an ErrMissing one (e.g. ErrAuthorizationScheme), if any, otherwise ErrMissing.
+//
+// Usage:
+//  extractor := jwt.Chain(jwt.FromAuthHeader, jwt.FromCookie("access_token"))
+func Chain(extractors ...TokenExtractor) TokenExtractor {
+	return func(r *http.Request) ([]byte, error) {
+		var firstErr error
+
+		for _, extract := range extractors {
+			token, err := extract(r)
+			if err == nil {
+				return token, nil
+			}
+
+			if firstErr == nil && !errors.Is(err, ErrMissing) {
+				firstErr = err
+			}
+		}
+
+		if firstErr != nil {
+			return nil, firstErr
+		}
+
+		return nil, ErrMissing
+	}
+}
@@ -0,0 +1,110 @@
+package jwt
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+func TestTokenExtractors(t *testing.T) {
+	newRequest := func(target, authorization string, cookie *http.Cookie) *http.Request {
+		req := httptest.NewRequest(http.MethodGet, target, nil)
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		if cookie != nil {
+			req.AddCookie(cookie)
+		}
+		return req
+	}
+
+	var tests = []struct {
+		name      string
+		extractor TokenExtractor
+		req       *http.Request
+		token     string
+		err       error
+	}{
+		{"header", FromAuthHeader, newRequest("/", "Bearer header-token", nil), "header-token", nil},
+		{"header missing", FromAuthHeader, newRequest("/", "", nil), "", ErrMissing},
+		{"header scheme", FromAuthHeader, newRequest("/", "Basic dXNlcjpwYXNz", nil), "", ErrAuthorizationScheme},
+		{"header lowercase scheme", FromAuthHeader, newRequest("/", "bearer header-token", nil), "header-token", nil},
+		{"header uppercase scheme", FromAuthHeader, newRequest("/", "BEARER header-token", nil), "header-token", nil},
+		{"header mixed case scheme", FromAuthHeader, newRequest("/", "bEaReR header-token", nil), "header-token", nil},
+		{"header tab", FromAuthHeader, newRequest("/", "Bearer\theader-token", nil), "header-token", nil},
+		{"header extra whitespace", FromAuthHeader, newRequest("/", " \tBearer  \t header-token \t", nil), "header-token", nil},
+		{"header without scheme", FromAuthHeader, newRequest("/", "header-token", nil), "", ErrMissingBearerToken},
+		{"header without token", FromAuthHeader, newRequest("/", "Bearer \t", nil), "", ErrMissingBearerToken},
+		{"header scheme prefix", FromAuthHeader, newRequest("/", "Bearerheader-token", nil), "", ErrMissingBearerToken},
+		{"cookie", FromCookie("access_token"), newRequest("/", "", &http.Cookie{Name: "access_token", Value: "cookie-token"}), "cookie-token", nil},
+		{"cookie missing", FromCookie("access_token"), newRequest("/", "", &http.Cookie{Name: "other", Value: "cookie-token"}), "", ErrMissing},
+		{"cookie empty", FromCookie("access_token"), newRequest("/", "", &http.Cookie{Name: "access_token", Value: ""}), "", ErrMissing},
+		{"query", FromQuery("token"), newRequest("/?token=query-token", "", nil), "query-token", nil},
+		{"query missing", FromQuery("token"), newRequest("/?other=query-token", "", nil), "", ErrMissing},
+		{
+			"chain first", Chain(FromAuthHeader, FromCookie("access_token"), FromQuery("token")),
+			newRequest("/?token=query-token", "Bearer header-token", &http.Cookie{Name: "access_token", Value: "cookie-token"}), "header-token", nil,
+		},
+		{
+			"chain fallthrough", Chain(FromAuthHeader, FromCookie("access_token"), FromQuery("token")),
+			newRequest("/?token=query-token", "", nil), "query-token", nil,
+		},
+		{
+			"chain fallthrough on error", Chain(FromAuthHeader, FromCookie("access_token")),
+			newRequest("/", "Basic dXNlcjpwYXNz", &http.Cookie{Name: "access_token", Value: "cookie-token"}), "cookie-token", nil,
+		},
+		{
+			"chain first error", Chain(FromCookie("access_token"), FromAuthHeader, FromQuery("token")),
+			newRequest("/", "Basic dXNlcjpwYXNz", nil), "", ErrAuthorizationScheme,
+		},
+		{"chain missing", Chain(FromAuthHeader, FromCookie("access_token")), newRequest("/", "", nil), "", ErrMissing},
+		{"chain empty", Chain(), newRequest("/", "Bearer header-token", nil), "", ErrMissing},
+	}
+
+	for _, tt := range tests {
+		token, err := tt.extractor(tt.req)
+		if !errors.Is(err, tt.err) {
+			t.Fatalf("[%s] expected error: %v but got: %v", tt.name, tt.err, err)
+		}
+
+		if got := string(token); got != tt.token {
+			t.Fatalf("[%s] expected token: %q but got: %q", tt.name, tt.token, got)
+		}
+	}
+}
+
+func TestMiddlewareWithExtractor(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Claims{Subject: "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	extractor := Chain(FromAuthHeader, FromCookie("access_token"))
+	handler := MiddlewareWithExtractor(extractor, testAlg, testSecret)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, _ := ClaimsFromContext(r.Context())
+		w.Write([]byte(claims.Subject))
+	}))
+
+	req := httptest.NewRequest(http.MethodGet, "/", nil)
+	req.AddCookie(&http.Cookie{Name: "access_token", Value: string(token)})
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusOK {
+		t.Fatalf("expected status code: %d but got: %d", http.StatusOK, rec.Code)
+	}
+
+	if got := rec.Body.String(); got != "kataras" {
+		t.Fatalf("expected body: %q but got: %q", "kataras", got)
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/?token="+string(token), nil)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	if rec.Code != http.StatusUnauthorized {
+		t.Fatalf("expected status code: %d as the query is not an accepted source but got: %d", http.StatusUnauthorized, rec.Code)
+	}
+}
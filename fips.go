@@ -0,0 +1,48 @@
+package jwt
+
+import "errors"
+
+// ErrAlgorithmNotPermitted indicates that a token was signed or verified
+// with an algorithm which is not permitted while the `FIPSOnly` mode is enabled.
+var ErrAlgorithmNotPermitted = errors.New("jwt: algorithm not permitted")
+
+// FIPSOnly, when enabled, restricts the `Sign` and `Verify` functions
+// (and all the functions, methods and middlewares built on them)
+// to the FIPS 140 approved algorithms:
+//  - HS256, HS384, HS512
+//  - RS256, RS384, RS512
+//  - PS256, PS384, PS512
+//  - ES256, ES384, ES512
+// Any other algorithm, including EdDSA, NONE and the custom ones
+// (e.g. the es256k and ed448 modules), fails with ErrAlgorithmNotPermitted.
+//
+// Note that it only restricts the algorithms of this package,
+// a FIPS 140 validated cryptographic module is the responsibility of the Go toolchain.
+// It's not safe to modify it while tokens are signed or verified,
+// set it once, e.g. at the program's initialization.
+//
+// Defaults to false.
+var FIPSOnly = false
+
+var fipsAlgs = []Alg{
+	HS256, HS384, HS512,
+	RS256, RS384, RS512,
+	PS256, PS384, PS512,
+	ES256, ES384, ES512,
+}
+
+// checkAlgorithmPermitted returns ErrAlgorithmNotPermitted
+// if the `FIPSOnly` is enabled and the "alg" is not a FIPS approved one.
+func checkAlgorithmPermitted(alg Alg) error {
+	if !FIPSOnly {
+		return nil
+	}
+
+	for _, fipsAlg := range fipsAlgs {
+		if alg == fipsAlg {
+			return nil
+		}
+	}
+
+	return ErrAlgorithmNotPermitted
+}
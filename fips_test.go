@@ -0,0 +1,57 @@
+package jwt
+
+import (
+	"testing"
+)
+
+func TestFIPSOnly(t *testing.T) {
+	edPrivateKey, edPublicKey, err := GenerateEdDSA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecPrivateKey, err := LoadPrivateKeyECDSA("./_testfiles/ecdsa_private_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := Map{"username": "kataras"}
+	edToken, err := Sign(EdDSA, edPrivateKey, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	FIPSOnly = true
+	defer func() {
+		FIPSOnly = false
+	}()
+
+	if _, err = Sign(EdDSA, edPrivateKey, claims); err != ErrAlgorithmNotPermitted {
+		t.Fatalf("expected error: %v but got: %v", ErrAlgorithmNotPermitted, err)
+	}
+
+	if _, err = Verify(EdDSA, edPublicKey, edToken); err != ErrAlgorithmNotPermitted {
+		t.Fatalf("expected error: %v but got: %v", ErrAlgorithmNotPermitted, err)
+	}
+
+	if _, err = VerifyWithHeaderValidator(nil, edPublicKey, edToken, AllowedAlgorithms(EdDSA)); err != ErrAlgorithmNotPermitted {
+		t.Fatalf("expected error: %v but got: %v", ErrAlgorithmNotPermitted, err)
+	}
+
+	if _, err = Verify(NONE, nil, []byte("eyJhbGciOiJOT05FIiwidHlwIjoiSldUIn0.eyJ1c2VybmFtZSI6ImthdGFyYXMifQ.")); err != ErrAlgorithmNotPermitted {
+		t.Fatalf("expected error: %v but got: %v", ErrAlgorithmNotPermitted, err)
+	}
+
+	ecToken, err := Sign(ES256, ecPrivateKey, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(ES256, &ecPrivateKey.PublicKey, ecToken); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, testToken); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -1,3 +1,5 @@
 module github.com/kataras/jwt
 
 go 1.18
+
+require golang.org/x/crypto v0.17.0
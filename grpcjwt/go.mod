@@ -0,0 +1,22 @@
+module github.com/kataras/jwt/grpcjwt
+
+go 1.18
+
+require (
+	github.com/kataras/jwt v0.1.9-0.20261014064858-16955e686d80
+	google.golang.org/grpc v1.56.3
+)
+
+require (
+	github.com/golang/protobuf v1.5.3 // indirect
+	golang.org/x/crypto v0.17.0 // indirect
+	golang.org/x/net v0.10.0 // indirect
+	golang.org/x/sys v0.15.0 // indirect
+	golang.org/x/text v0.14.0 // indirect
+	google.golang.org/genproto v0.0.0-20230410155749-daa745c078e1 // indirect
+	google.golang.org/protobuf v1.30.0 // indirect
+)
+
+// The released tags do not include the APIs of this module yet,
+// build it against the parent tree until the next tag.
+replace github.com/kataras/jwt => ../
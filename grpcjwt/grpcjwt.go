@@ -0,0 +1,142 @@
+// Package grpcjwt provides gRPC server interceptors which verify
+// the token of the "authorization" request metadata,
+// like the `jwt.Middleware` does for net/http.
+//
+// It lives in its own module so the users who don't need it
+// don't pull the gRPC dependency.
+//
+// Usage:
+//
+//  srv := grpc.NewServer(
+//    grpc.UnaryInterceptor(grpcjwt.UnaryServerInterceptor(jwt.EdDSA, publicKey)),
+//    grpc.StreamInterceptor(grpcjwt.StreamServerInterceptor(jwt.EdDSA, publicKey)),
+//  )
+//
+//  func (s *server) SayHello(ctx context.Context, in *pb.HelloRequest) (*pb.HelloReply, error) {
+//    claims, _ := jwt.ClaimsFromContext(ctx)
+//    [claims.Subject...]
+//  }
+package grpcjwt
+
+import (
+	"context"
+	"errors"
+
+	"github.com/kataras/jwt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// MetadataKey is the request metadata key which holds the "Bearer $token" value.
+const MetadataKey = "authorization"
+
+// ErrAuthorizationScheme indicates that the "authorization" request metadata
+// does not hold a "Bearer" token.
+var ErrAuthorizationScheme = errors.New("jwt: authorization metadata: expected a Bearer token")
+
+// UnaryServerInterceptor returns a gRPC unary server interceptor which verifies
+// the "Bearer" token of the "authorization" request metadata
+// with the given algorithm, key and validators.
+// On success, the verified token is stored to the handler's context,
+// see `jwt.ClaimsFromContext` and `jwt.VerifiedTokenFromContext`.
+// Otherwise the call fails with a codes.Unauthenticated status error.
+// Tokens larger than `jwt.DefaultMiddlewareMaxTokenBytes` are rejected before decoding,
+// pass a `jwt.MaxTokenBytes` validator to modify the limit.
+func UnaryServerInterceptor(alg jwt.Alg, key jwt.PublicKey, validators ...jwt.TokenValidator) grpc.UnaryServerInterceptor {
+	validators = withDefaultMaxTokenBytes(validators)
+
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := verify(ctx, alg, key, validators)
+		if err != nil {
+			return nil, err
+		}
+
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor same as `UnaryServerInterceptor` but for streaming calls.
+// The verified token is stored to the context of the stream.
+func StreamServerInterceptor(alg jwt.Alg, key jwt.PublicKey, validators ...jwt.TokenValidator) grpc.StreamServerInterceptor {
+	validators = withDefaultMaxTokenBytes(validators)
+
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := verify(ss.Context(), alg, key, validators)
+		if err != nil {
+			return err
+		}
+
+		return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+	}
+}
+
+// withDefaultMaxTokenBytes returns a new slice of the default limit and the "validators",
+// the default goes first, so a `jwt.MaxTokenBytes` of the caller overrides it.
+func withDefaultMaxTokenBytes(validators []jwt.TokenValidator) []jwt.TokenValidator {
+	return append([]jwt.TokenValidator{jwt.MaxTokenBytes(jwt.DefaultMiddlewareMaxTokenBytes)}, validators...)
+}
+
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func verify(ctx context.Context, alg jwt.Alg, key jwt.PublicKey, validators []jwt.TokenValidator) (context.Context, error) {
+	token, err := bearerToken(ctx)
+	if err != nil {
+		return nil, unauthenticated(err)
+	}
+
+	verifiedToken, err := jwt.VerifyContext(ctx, alg, key, token, validators...)
+	if err != nil {
+		return nil, unauthenticated(err)
+	}
+
+	return jwt.ContextWithVerifiedToken(ctx, verifiedToken), nil
+}
+
+// bearerToken returns the token of the "authorization: Bearer $token" request metadata,
+// it's parsed the same way the `jwt.FromAuthHeader` parses the HTTP header, see `jwt.BearerToken`.
+func bearerToken(ctx context.Context) ([]byte, error) {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := md.Get(MetadataKey)
+	if len(values) == 0 {
+		return nil, jwt.ErrMissing
+	}
+
+	token, err := jwt.BearerToken(values[0])
+	if err != nil {
+		if errors.Is(err, jwt.ErrAuthorizationScheme) {
+			return nil, ErrAuthorizationScheme
+		}
+
+		return nil, err
+	}
+
+	return token, nil
+}
+
+// unauthenticated converts "err" to a codes.Unauthenticated status error.
+func unauthenticated(err error) error {
+	var msg string
+
+	switch {
+	case errors.Is(err, jwt.ErrMissing):
+		msg = "missing token"
+	case errors.Is(err, ErrAuthorizationScheme):
+		msg = "malformed authorization metadata"
+	case errors.Is(err, jwt.ErrExpired):
+		msg = "token expired"
+	default:
+		msg = "invalid token"
+	}
+
+	return status.Error(codes.Unauthenticated, msg)
+}
@@ -0,0 +1,177 @@
+package grpcjwt
+
+import (
+	"context"
+	"net"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/jwt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+)
+
+var testSecret = []byte("sercrethatmaycontainch@r$32chars")
+
+// newTestClient serves the gRPC health service through an in-memory listener,
+// the "subjects" channel receives the subject of each verified call.
+func newTestClient(t *testing.T) (healthpb.HealthClient, <-chan string) {
+	t.Helper()
+
+	subjects := make(chan string, 10)
+	record := func(ctx context.Context) {
+		claims, ok := jwt.ClaimsFromContext(ctx)
+		if !ok {
+			t.Errorf("expected the claims to be stored to the handler's context")
+		}
+		subjects <- claims.Subject
+	}
+
+	srv := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(UnaryServerInterceptor(jwt.HS256, testSecret),
+			func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+				record(ctx)
+				return handler(ctx, req)
+			}),
+		grpc.ChainStreamInterceptor(StreamServerInterceptor(jwt.HS256, testSecret),
+			func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+				record(ss.Context())
+				return handler(srv, ss)
+			}),
+	)
+	healthpb.RegisterHealthServer(srv, health.NewServer())
+
+	lis := bufconn.Listen(1024 * 1024)
+	go srv.Serve(lis)
+	t.Cleanup(srv.Stop)
+
+	conn, err := grpc.DialContext(context.Background(), "bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return lis.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { conn.Close() })
+
+	return healthpb.NewHealthClient(conn), subjects
+}
+
+func TestUnaryServerInterceptor(t *testing.T) {
+	client, subjects := newTestClient(t)
+
+	token, err := jwt.Sign(jwt.HS256, testSecret, jwt.Claims{Subject: "kataras"}, jwt.MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := jwt.Sign(jwt.HS256, testSecret, jwt.Claims{Subject: "kataras", Expiry: time.Now().Add(-time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	large, err := jwt.Sign(jwt.HS256, testSecret, jwt.Map{"sub": "kataras", "data": strings.Repeat("a", jwt.DefaultMiddlewareMaxTokenBytes)}, jwt.MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx := metadata.AppendToOutgoingContext(context.Background(), MetadataKey, "Bearer "+string(token))
+	if _, err = client.Check(ctx, &healthpb.HealthCheckRequest{}); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := <-subjects; got != "kataras" {
+		t.Fatalf("expected subject: %q but got: %q", "kataras", got)
+	}
+
+	// The metadata is parsed like the jwt.FromAuthHeader parses the HTTP header
+	// (a gRPC client does not send tabs, other implementations may).
+	got, err := bearerToken(metadata.NewIncomingContext(context.Background(), metadata.Pairs(MetadataKey, " bearer\t"+string(token)+" ")))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(got) != string(token) {
+		t.Fatalf("expected token: %s but got: %s", token, got)
+	}
+
+	var tests = []struct {
+		authorization string
+		msg           string
+	}{
+		{"", "missing token"},
+		{"Basic dXNlcjpwYXNz", "malformed authorization metadata"},
+		{"Bearer ", "malformed authorization metadata"},
+		{string(token), "malformed authorization metadata"},
+		{"Bearer " + string(large), "invalid token"}, // the default jwt.MaxTokenBytes.
+		{"Bearer " + string(expired), "token expired"},
+		{"Bearer " + string(token[:len(token)-2]), "invalid token"},
+	}
+
+	for i, tt := range tests {
+		ctx := context.Background()
+		if tt.authorization != "" {
+			ctx = metadata.AppendToOutgoingContext(ctx, MetadataKey, tt.authorization)
+		}
+
+		_, err = client.Check(ctx, &healthpb.HealthCheckRequest{})
+		if code := status.Code(err); code != codes.Unauthenticated {
+			t.Fatalf("[%d] expected code: %s but got: %s", i, codes.Unauthenticated, code)
+		}
+
+		if got := status.Convert(err).Message(); got != tt.msg {
+			t.Fatalf("[%d] expected message: %q but got: %q", i, tt.msg, got)
+		}
+	}
+}
+
+func TestStreamServerInterceptor(t *testing.T) {
+	client, subjects := newTestClient(t)
+
+	token, err := jwt.Sign(jwt.HS256, testSecret, jwt.Claims{Subject: "kataras"}, jwt.MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := jwt.Sign(jwt.HS256, testSecret, jwt.Claims{Subject: "kataras", Expiry: time.Now().Add(-time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(metadata.AppendToOutgoingContext(context.Background(), MetadataKey, "Bearer "+string(token)))
+	defer cancel()
+
+	stream, err := client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = stream.Recv(); err != nil {
+		t.Fatal(err)
+	}
+
+	if got := <-subjects; got != "kataras" {
+		t.Fatalf("expected subject: %q but got: %q", "kataras", got)
+	}
+
+	ctx = metadata.AppendToOutgoingContext(context.Background(), MetadataKey, "Bearer "+string(expired))
+	stream, err = client.Watch(ctx, &healthpb.HealthCheckRequest{})
+	if err == nil {
+		_, err = stream.Recv()
+	}
+
+	if code := status.Code(err); code != codes.Unauthenticated {
+		t.Fatalf("expected code: %s but got: %s", codes.Unauthenticated, code)
+	}
+
+	if got, expected := status.Convert(err).Message(), "token expired"; got != expected {
+		t.Fatalf("expected message: %q but got: %q", expected, got)
+	}
+}
@@ -0,0 +1,236 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Header holds the JOSE header (the first part) of a token.
+//
+// Note that the header is parsed before the signature verification,
+// e.g. to select the verification key through its "kid" field,
+// BUT its values should only be trusted after the verification succeeds.
+// The `DecodeHeader` function does NOT verify the token,
+// use the `VerifiedToken.ParseHeader` method instead.
+type Header struct {
+	// The algorithm used to sign the token.
+	Alg string `json:"alg"`
+	// The media type of the token, e.g. "JWT".
+	Typ string `json:"typ,omitempty"`
+	// The content type of the payload, e.g. "JWT" for nested tokens.
+	Cty string `json:"cty,omitempty"`
+	// The key id which hints which key was used to sign the token.
+	Kid string `json:"kid,omitempty"`
+	// Any other header fields.
+	Extra map[string]interface{} `json:"-"`
+}
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// It decodes the well-known fields and stores the rest into the `Extra` map.
+func (h *Header) UnmarshalJSON(data []byte) error {
+	type headerFields Header // avoid recursion.
+
+	var fields headerFields
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+
+	var all map[string]interface{}
+	if err := json.Unmarshal(data, &all); err != nil {
+		return err
+	}
+
+	for _, k := range [...]string{"alg", "typ", "cty", "kid"} {
+		delete(all, k)
+	}
+
+	*h = Header(fields)
+	if len(all) > 0 {
+		h.Extra = all
+	}
+
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// It encodes the well-known fields along with the `Extra` ones.
+func (h Header) MarshalJSON() ([]byte, error) {
+	type headerFields Header // avoid recursion.
+
+	b, err := json.Marshal(headerFields(h))
+	if err != nil || len(h.Extra) == 0 {
+		return b, err
+	}
+
+	extra := make(map[string]interface{}, len(h.Extra))
+	for k, v := range h.Extra {
+		switch k {
+		case "alg", "typ", "cty", "kid": // the fields take precedence.
+		default:
+			extra[k] = v
+		}
+	}
+
+	if len(extra) == 0 {
+		return b, nil
+	}
+
+	extraBytes, err := json.Marshal(extra)
+	if err != nil {
+		return nil, err
+	}
+
+	// Join the two JSON objects.
+	b = append(b[:len(b)-1], ',')
+	return append(b, extraBytes[1:]...), nil
+}
+
+// Get returns the value of the header field of the given "key",
+// it returns nil if the field is missing.
+// The well-known fields are returned as strings, e.g. Get("kid").
+func (h Header) Get(key string) interface{} {
+	var v string
+
+	switch key {
+	case "alg":
+		v = h.Alg
+	case "typ":
+		v = h.Typ
+	case "cty":
+		v = h.Cty
+	case "kid":
+		v = h.Kid
+	default:
+		return h.Extra[key]
+	}
+
+	if v == "" {
+		return nil
+	}
+
+	return v
+}
+
+func (h *Header) set(key string, value interface{}) error {
+	switch key {
+	case "alg", "typ", "b64", "crit", "zip": // "b64", "crit" and "zip" change how the token is verified.
+		return fmt.Errorf("%w: %q", ErrProtectedHeader, key)
+	case "cty", "kid":
+		v, ok := value.(string)
+		if !ok {
+			return fmt.Errorf("jwt: header: %q: expected a string value but got: %T", key, value)
+		}
+
+		if key == "cty" {
+			h.Cty = v
+		} else {
+			h.Kid = v
+		}
+	default:
+		if h.Extra == nil {
+			h.Extra = make(map[string]interface{})
+		}
+
+		h.Extra[key] = value
+	}
+
+	return nil
+}
+
+// ErrMalformedHeader indicates that the token's header could not be parsed:
+// it's not base64url-encoded, it's not a JSON object or it exceeds
+// the `MaxHeaderSize` or the `MaxHeaderDepth` limits.
+// It is an ErrTokenForm too.
+var ErrMalformedHeader = fmt.Errorf("%w: malformed header", ErrTokenForm)
+
+var (
+	// MaxHeaderSize is the maximum size, in bytes, of a (base64-decoded) header.
+	// The header is attacker-controlled and it's parsed before the verification,
+	// larger headers are rejected with an ErrMalformedHeader error.
+	// Defaults to 4KiB, which is plenty for the standard fields and a few custom ones.
+	MaxHeaderSize = 4 << 10
+	// MaxHeaderDepth is the maximum nesting depth of the header's JSON,
+	// the header object itself is the first level.
+	// Deeper headers are rejected with an ErrMalformedHeader error.
+	// Defaults to 8.
+	MaxHeaderDepth = 8
+)
+
+// checkHeaderLimits reports an ErrMalformedHeader error
+// when the "headerDecoded" exceeds the `MaxHeaderSize` or the `MaxHeaderDepth`.
+// The depth is counted without parsing, so deeply nested input costs nothing more.
+func checkHeaderLimits(headerDecoded []byte) error {
+	if len(headerDecoded) > MaxHeaderSize {
+		return fmt.Errorf("%w: exceeds %d bytes", ErrMalformedHeader, MaxHeaderSize)
+	}
+
+	depth := 0
+	inString, escaped := false, false
+	for _, c := range headerDecoded {
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case c == '\\':
+				escaped = true
+			case c == '"':
+				inString = false
+			}
+
+			continue
+		}
+
+		switch c {
+		case '"':
+			inString = true
+		case '{', '[':
+			if depth++; depth > MaxHeaderDepth {
+				return fmt.Errorf("%w: exceeds %d levels of nesting", ErrMalformedHeader, MaxHeaderDepth)
+			}
+		case '}', ']':
+			depth--
+		}
+	}
+
+	return nil
+}
+
+// DecodeHeader decodes the header part of the given "token"
+// WITHOUT verifying the token. See the `Header` type for more.
+// Any parse failure is reported as an ErrMalformedHeader error.
+func DecodeHeader(token []byte) (Header, error) {
+	var h Header
+
+	if bytes.Count(token, sep) != 2 {
+		return h, ErrMalformedToken
+	}
+
+	idx := bytes.Index(token, sep)
+	header := token[:idx]
+	headerDecoded, err := Base64Decode(header)
+	if err != nil {
+		return h, fmt.Errorf("%w: header segment: %v", ErrMalformedHeader, err)
+	}
+
+	if err = checkHeaderLimits(headerDecoded); err != nil {
+		return h, err
+	}
+
+	if !isJSONObject(headerDecoded) { // the decoder would stop at the first value, e.g. of "{}4".
+		return h, fmt.Errorf("%w: not a JSON object", ErrMalformedHeader)
+	}
+
+	if err = Unmarshal(headerDecoded, &h); err != nil {
+		return Header{}, fmt.Errorf("%w: %v", ErrMalformedHeader, err)
+	}
+
+	return h, nil
+}
+
+// ParseHeader decodes the token's verified `Header` part.
+func (t *VerifiedToken) ParseHeader() (Header, error) {
+	var h Header
+	err := Unmarshal(t.Header, &h)
+	return h, err
+}
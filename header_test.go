@@ -0,0 +1,156 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestDecodeHeader(t *testing.T) {
+	token, err := SignWithHeader(testAlg, testSecret, Map{"username": "kataras"}, Map{
+		"alg":    testAlg.Name(),
+		"typ":    "JWT",
+		"cty":    "JWT",
+		"kid":    "api",
+		"custom": "value",
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := Header{
+		Alg:   testAlg.Name(),
+		Typ:   "JWT",
+		Cty:   "JWT",
+		Kid:   "api",
+		Extra: map[string]interface{}{"custom": "value"},
+	}
+
+	h, err := DecodeHeader(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(expected, h) {
+		t.Fatalf("expected header:\n%#+v\n\nbut got:\n%#+v", expected, h)
+	}
+
+	if _, err = DecodeHeader([]byte("header.payload")); err != ErrMalformedToken {
+		t.Fatalf("expected error: %v but got: %v", ErrMalformedToken, err)
+	}
+
+	// Test the round trip.
+	b, err := json.Marshal(h)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got Header
+	if err = json.Unmarshal(b, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if !reflect.DeepEqual(expected, got) {
+		t.Fatalf("expected header:\n%#+v\n\nbut got:\n%#+v", expected, got)
+	}
+}
+
+func TestVerifiedTokenParseHeader(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := verifiedToken.ParseHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (Header{Alg: testAlg.Name(), Typ: "JWT"}); !reflect.DeepEqual(expected, h) {
+		t.Fatalf("expected header:\n%#+v\n\nbut got:\n%#+v", expected, h)
+	}
+}
+
+func TestDecodeHeaderLimits(t *testing.T) {
+	encode := func(header string) []byte {
+		return []byte(string(Base64Encode([]byte(header))) + ".e30.sig")
+	}
+
+	var tests = []struct {
+		header  string
+		wantErr error
+	}{
+		{`{"alg":"HS256","x":` + strings.Repeat("[", MaxHeaderDepth-1) + strings.Repeat("]", MaxHeaderDepth-1) + `}`, nil},
+		{`{"alg":"HS256","x":` + strings.Repeat("[", MaxHeaderDepth) + strings.Repeat("]", MaxHeaderDepth) + `}`, ErrMalformedHeader},
+		{`{"alg":"HS256","x":"` + strings.Repeat("[", 2*MaxHeaderDepth) + `"}`, nil}, // inside a string.
+		{`{"alg":"HS256","x":"` + strings.Repeat("a", MaxHeaderSize) + `"}`, ErrMalformedHeader},
+		{`{"alg":"HS256"`, ErrMalformedHeader},
+		{`null`, ErrMalformedHeader},
+		{`["alg"]`, ErrMalformedHeader},
+		{`{"alg":1}`, ErrMalformedHeader},
+	}
+
+	for i, tt := range tests {
+		if _, err := DecodeHeader(encode(tt.header)); !errors.Is(err, tt.wantErr) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.wantErr, err)
+		}
+	}
+
+	if _, err := DecodeHeader([]byte("!.e30.sig")); !errors.Is(err, ErrMalformedHeader) || !errors.Is(err, ErrTokenForm) {
+		t.Fatalf("expected error: %v but got: %v", ErrMalformedHeader, err)
+	}
+
+	// The limits are applied before the verification too.
+	deep := `{"alg":"HS256","typ":"JWT","x":` + strings.Repeat("[", MaxHeaderDepth) + strings.Repeat("]", MaxHeaderDepth) + `}`
+	token, err := SignWithHeader(testAlg, testSecret, Map{"username": "kataras"}, json.RawMessage(deep))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token); !errors.Is(err, ErrMalformedHeader) {
+		t.Fatalf("expected error: %v but got: %v", ErrMalformedHeader, err)
+	}
+}
+
+func FuzzDecodeHeader(f *testing.F) {
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, WithKid("api"))
+	if err != nil {
+		f.Fatal(err)
+	}
+
+	f.Add(token)
+	f.Add([]byte("eyJhbGciOiJIUzI1NiJ9.e30."))
+	f.Add([]byte("W1tbW1tbW1tbW10.e30.")) // [[[[[[[[[[[]
+	f.Add([]byte("bnVsbA.e30.sig"))       // null
+	f.Add([]byte(".."))
+	f.Add([]byte("e300..")) // {}4
+
+	f.Fuzz(func(t *testing.T, token []byte) {
+		if _, err := DecodeHeader(token); err != nil {
+			return
+		}
+
+		// On success the header must be a JSON object of the limits.
+		parts := bytes.Split(token, sep)
+		if len(parts) != 3 {
+			t.Fatalf("expected an error on a token of %d parts", len(parts))
+		}
+
+		headerDecoded, err := Base64Decode(parts[0])
+		if err != nil {
+			t.Fatalf("expected an error on a header which is not base64url-encoded: %v", err)
+		}
+
+		if !isJSONObject(headerDecoded) || len(headerDecoded) > MaxHeaderSize {
+			t.Fatalf("expected an error on the header: %q", headerDecoded)
+		}
+	})
+}
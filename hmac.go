@@ -1,15 +1,26 @@
 package jwt
 
 import (
+	"bytes"
 	"crypto"
 	"crypto/hmac"
 	"crypto/rand"
 	_ "crypto/sha256" // ignore:lint
 	_ "crypto/sha512"
+	"crypto/x509"
 	"fmt"
 	"os"
 )
 
+// ErrPublicKeyAsSecret indicates that the HMAC shared secret given to a `Verify` function
+// looks like a public key, a PEM block or a DER-encoded (PKIX or PKCS #1) one,
+// the well-known algorithm confusion misconfiguration. It is an ErrInvalidKey too.
+//
+// It is a safety net to catch the misconfiguration early, e.g. during testing,
+// NOT a replacement of restricting the accepted algorithms (see `AllowedAlgorithms`):
+// a raw public key (e.g. the 32 bytes of an Ed25519 one) can not be told apart from a random secret.
+var ErrPublicKeyAsSecret = fmt.Errorf("%w: public key used as HMAC secret", ErrInvalidKey)
+
 type algHMAC struct {
 	name   string
 	hasher crypto.Hash
@@ -19,14 +30,34 @@ func (a *algHMAC) Name() string {
 	return a.name
 }
 
-// func (a *algHMAC) Parse(secret string) (string, error) {
-// 	return secret, nil
-// }
+func (a *algHMAC) ExpectedSignatureSize(key PublicKey) int {
+	if _, ok := key.([]byte); !ok {
+		return 0
+	}
+
+	return a.hasher.Size()
+}
+
+// Parse completes the `AlgParser` interface.
+// The shared secret is the "private" one,
+// the "public" is only used if "private" is empty.
+func (a *algHMAC) Parse(private, public []byte) (PrivateKey, PublicKey, error) {
+	secret := private
+	if len(secret) == 0 {
+		secret = public
+	}
+
+	if len(secret) == 0 {
+		return nil, nil, fmt.Errorf("HMAC: %w: empty secret", ErrInvalidKey)
+	}
+
+	return secret, secret, nil
+}
 
 func (a *algHMAC) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error) {
 	secret, ok := key.([]byte)
 	if !ok {
-		return nil, fmt.Errorf("expected a string: %w", ErrInvalidKey)
+		return nil, fmt.Errorf("expected a []byte: %w", ErrInvalidKey)
 	}
 
 	// We can improve its performance (if we store the secret on the same structure)
@@ -42,6 +73,10 @@ func (a *algHMAC) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error)
 }
 
 func (a *algHMAC) Verify(key PublicKey, headerAndPayload []byte, signature []byte) error {
+	if secret, ok := key.([]byte); ok && looksLikePublicKey(secret) {
+		return ErrPublicKeyAsSecret
+	}
+
 	expectedSignature, err := a.Sign(key, headerAndPayload)
 	if err != nil {
 		return err
@@ -54,6 +89,25 @@ func (a *algHMAC) Verify(key PublicKey, headerAndPayload []byte, signature []byt
 	return nil
 }
 
+// looksLikePublicKey reports whether the HMAC "secret" is a PEM block
+// or a DER-encoded public key, see `ErrPublicKeyAsSecret`.
+func looksLikePublicKey(secret []byte) bool {
+	if bytes.Contains(secret, []byte("-----BEGIN ")) {
+		return true
+	}
+
+	if len(secret) == 0 || secret[0] != 0x30 { // not an ASN.1 SEQUENCE, skip the parsing.
+		return false
+	}
+
+	if _, err := x509.ParsePKIXPublicKey(secret); err == nil {
+		return true
+	}
+
+	_, err := x509.ParsePKCS1PublicKey(secret)
+	return err == nil
+}
+
 // Key Helper.
 
 var panicHandler = func(v interface{}) {
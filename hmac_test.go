@@ -1,6 +1,8 @@
 package jwt
 
 import (
+	"crypto/x509"
+	"errors"
 	"testing"
 )
 
@@ -12,6 +14,35 @@ func TestEncodeDecodeTokenHMAC(t *testing.T) {
 	testEncodeDecodeToken(t, HS256, key, key, expectedToken)
 }
 
+func TestHMACPublicKeyAsSecret(t *testing.T) {
+	publicKeyPEM, err := ReadFile("./_testfiles/rsa_public_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, publicKey := MustLoadRSA("./_testfiles/rsa_private_key.pem", "./_testfiles/rsa_public_key.pem")
+	publicKeyDER, err := x509.MarshalPKIXPublicKey(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, secret := range [][]byte{publicKeyPEM, publicKeyDER} {
+		// The attacker signs an HS256 token using the (known) RSA public key as the HMAC secret.
+		forged, err := Sign(HS256, secret, Map{"username": "kataras"})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = Verify(HS256, secret, forged); err != ErrPublicKeyAsSecret {
+			t.Fatalf("expected error: %v but got: %v", ErrPublicKeyAsSecret, err)
+		}
+
+		if !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("expected error to be an ErrInvalidKey too")
+		}
+	}
+}
+
 func TestMustLoadHMAC(t *testing.T) {
 	catchPanic(t, false, func() {
 		MustLoadHMAC("./_testfiles/hmac.key")
@@ -37,3 +68,35 @@ func catchPanic(t *testing.T, shouldPanic bool, fn func()) {
 		t.Fatalf("expected panic: %v: %v", got, val)
 	}
 }
+
+func TestLoadKeysConfigurationHMAC(t *testing.T) {
+	keys, err := KeysConfiguration{
+		{ID: "api", Alg: "HS512", Private: string(testSecret)},
+	}.Load()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, ok := keys.Get("api")
+	if !ok {
+		t.Fatalf("expected key to be registered")
+	}
+
+	if key.Alg != HS512 {
+		t.Fatalf("expected algorithm: %s but got: %s", HS512.Name(), key.Alg.Name())
+	}
+
+	token, err := keys.SignToken("api", Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims Map
+	if err = keys.VerifyToken(token, &claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if claims["username"] != "kataras" {
+		t.Fatalf("unexpected claims: %#+v", claims)
+	}
+}
@@ -0,0 +1,150 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrAuthorizationScheme indicates that the "Authorization" request header
+// does not hold a "Bearer" token (RFC 6750, section 2.1).
+var ErrAuthorizationScheme = errors.New("jwt: authorization header: expected a Bearer token")
+
+// ErrMissingBearerToken indicates that the "Authorization" request header
+// holds a single value, e.g. a bare token without the "Bearer" scheme
+// or the scheme without a token. It wraps the ErrAuthorizationScheme.
+var ErrMissingBearerToken = fmt.Errorf("%w: missing scheme or token", ErrAuthorizationScheme)
+
+type verifiedTokenContextKey struct{}
+
+// Middleware returns a net/http middleware which verifies the "Bearer" token
+// of the "Authorization" request header with the given algorithm, key and validators.
+// On success, the verified token is stored to the request context,
+// see `ClaimsFromContext` and `VerifiedTokenFromContext`.
+// Otherwise the client receives a 401 Unauthorized response
+// with a "WWW-Authenticate" header and a plain text error body:
+// "missing token", "malformed authorization header", "token expired" or "invalid token".
+// Tokens larger than `DefaultMiddlewareMaxTokenBytes` are rejected before decoding,
+// pass a `MaxTokenBytes` validator to modify the limit.
+//
+// Usage:
+//  mux.Handle("/protected", jwt.Middleware(jwt.EdDSA, publicKey)(protectedHandler))
+//
+//  func protectedHandler(w http.ResponseWriter, r *http.Request) {
+//    claims, _ := jwt.ClaimsFromContext(r.Context())
+//    [claims.Subject...]
+//  }
+func Middleware(alg Alg, key PublicKey, validators ...TokenValidator) func(http.Handler) http.Handler {
+	return MiddlewareWithExtractor(FromAuthHeader, alg, key, validators...)
+}
+
+// MiddlewareWithExtractor same as `Middleware` but it reads the token
+// through the given TokenExtractor, e.g. a cookie.
+//
+// Usage:
+//  extractor := jwt.Chain(jwt.FromAuthHeader, jwt.FromCookie("access_token"))
+//  protect := jwt.MiddlewareWithExtractor(extractor, jwt.EdDSA, publicKey)
+func MiddlewareWithExtractor(extract TokenExtractor, alg Alg, key PublicKey, validators ...TokenValidator) func(http.Handler) http.Handler {
+	// The default limit goes first, so a MaxTokenBytes of the caller overrides it.
+	validators = joinValidators([]TokenValidator{MaxTokenBytes(DefaultMiddlewareMaxTokenBytes)}, validators)
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			token, err := extract(r)
+			if err != nil {
+				unauthorized(w, err)
+				return
+			}
+
+			verifiedToken, err := VerifyContext(r.Context(), alg, key, token, validators...)
+			if err != nil {
+				unauthorized(w, err)
+				return
+			}
+
+			next.ServeHTTP(w, r.WithContext(ContextWithVerifiedToken(r.Context(), verifiedToken)))
+		})
+	}
+}
+
+// ContextWithVerifiedToken returns a copy of "ctx" which holds the verified token,
+// so the `ClaimsFromContext` and `VerifiedTokenFromContext` can read it.
+// The `Middleware` calls it, it's exported for other transports too (e.g. the grpcjwt interceptors).
+func ContextWithVerifiedToken(ctx context.Context, verifiedToken *VerifiedToken) context.Context {
+	return context.WithValue(ctx, verifiedTokenContextKey{}, verifiedToken)
+}
+
+// ClaimsFromContext returns the standard claims of the token
+// verified by the `Middleware`.
+// Look `VerifiedTokenFromContext` to decode custom claims.
+func ClaimsFromContext(ctx context.Context) (Claims, bool) {
+	verifiedToken, ok := VerifiedTokenFromContext(ctx)
+	if !ok {
+		return Claims{}, false
+	}
+
+	return verifiedToken.StandardClaims, true
+}
+
+// VerifiedTokenFromContext returns the token verified by the `Middleware`.
+//
+// Usage:
+//  verifiedToken, ok := jwt.VerifiedTokenFromContext(r.Context())
+//  var claims myClaims
+//  err := verifiedToken.Claims(&claims)
+func VerifiedTokenFromContext(ctx context.Context) (*VerifiedToken, bool) {
+	verifiedToken, ok := ctx.Value(verifiedTokenContextKey{}).(*VerifiedToken)
+	return verifiedToken, ok
+}
+
+// bearerToken returns the token of the "Authorization: Bearer $token" request header.
+func bearerToken(r *http.Request) ([]byte, error) {
+	return BearerToken(r.Header.Get("Authorization"))
+}
+
+// BearerToken returns the token of the "Bearer $token" value of an authorization header,
+// e.g. the HTTP "Authorization" request header or the gRPC "authorization" request metadata.
+// The scheme is case-insensitive (RFC 7235, section 2.1),
+// any spaces and tabs around the scheme and the token are trimmed.
+// It returns an ErrMissing error on an empty value, an ErrAuthorizationScheme on a non "Bearer" one
+// and an ErrMissingBearerToken one on a value without the scheme or the token.
+// See `FromAuthHeader` too.
+func BearerToken(authorization string) ([]byte, error) {
+	authorization = strings.TrimSpace(authorization)
+	if authorization == "" {
+		return nil, ErrMissing
+	}
+
+	idx := strings.IndexAny(authorization, " \t")
+	if idx == -1 {
+		return nil, ErrMissingBearerToken
+	}
+
+	if scheme := authorization[:idx]; !strings.EqualFold(scheme, "Bearer") {
+		return nil, ErrAuthorizationScheme
+	}
+
+	token := strings.TrimSpace(authorization[idx+1:])
+	return []byte(token), nil
+}
+
+// unauthorized writes a 401 response of the "err" (RFC 6750, section 3).
+func unauthorized(w http.ResponseWriter, err error) {
+	var challenge, body string
+
+	switch {
+	case errors.Is(err, ErrMissing):
+		challenge, body = `Bearer`, "missing token"
+	case errors.Is(err, ErrAuthorizationScheme):
+		challenge, body = `Bearer error="invalid_request"`, "malformed authorization header"
+	case errors.Is(err, ErrExpired):
+		challenge, body = `Bearer error="invalid_token", error_description="token expired"`, "token expired"
+	default:
+		challenge, body = `Bearer error="invalid_token"`, "invalid token"
+	}
+
+	w.Header().Set("WWW-Authenticate", challenge)
+	http.Error(w, body, http.StatusUnauthorized)
+}
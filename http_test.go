@@ -0,0 +1,91 @@
+package jwt
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestMiddleware(t *testing.T) {
+	handler := Middleware(testAlg, testSecret)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		claims, ok := ClaimsFromContext(r.Context())
+		if !ok {
+			t.Fatalf("expected the claims to be stored to the request context")
+		}
+
+		verifiedToken, ok := VerifiedTokenFromContext(r.Context())
+		if !ok {
+			t.Fatalf("expected the verified token to be stored to the request context")
+		}
+
+		var custom Map
+		if err := verifiedToken.Claims(&custom); err != nil {
+			t.Fatal(err)
+		}
+
+		w.Write([]byte(claims.Subject + ":" + custom["role"].(string)))
+	}))
+
+	token, err := Sign(testAlg, testSecret, Map{"sub": "kataras", "role": "admin"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := Sign(testAlg, testSecret, Claims{Subject: "kataras", Expiry: Clock().Add(-time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	large, err := Sign(testAlg, testSecret, Map{"sub": "kataras", "role": strings.Repeat("a", DefaultMiddlewareMaxTokenBytes)}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pair, err := SignTokenPair(testAlg, testSecret, Map{"sub": "kataras", "role": "admin"}, time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		authorization     string
+		status            int
+		body              string
+		wwwAuthentication string
+	}{
+		{"Bearer " + string(token), http.StatusOK, "kataras:admin", ""},
+		{"bearer " + string(token), http.StatusOK, "kataras:admin", ""},
+		{"", http.StatusUnauthorized, "missing token", "Bearer"},
+		{"Basic dXNlcjpwYXNz", http.StatusUnauthorized, "malformed authorization header", `Bearer error="invalid_request"`},
+		{"Bearer", http.StatusUnauthorized, "malformed authorization header", `Bearer error="invalid_request"`},
+		{"Bearer " + string(expired), http.StatusUnauthorized, "token expired", `Bearer error="invalid_token", error_description="token expired"`},
+		{"Bearer " + string(token[:len(token)-2]), http.StatusUnauthorized, "invalid token", `Bearer error="invalid_token"`},
+		{"Bearer " + string(large), http.StatusUnauthorized, "invalid token", `Bearer error="invalid_token"`},
+		// A refresh token is not an access token.
+		{"Bearer " + string(unquoteToken(pair.RefreshToken)), http.StatusUnauthorized, "invalid token", `Bearer error="invalid_token"`},
+		{"Bearer " + string(unquoteToken(pair.AccessToken)), http.StatusOK, "kataras:admin", ""},
+	}
+
+	for i, tt := range tests {
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tt.authorization != "" {
+			req.Header.Set("Authorization", tt.authorization)
+		}
+
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+
+		if rec.Code != tt.status {
+			t.Fatalf("[%d] expected status code: %d but got: %d", i, tt.status, rec.Code)
+		}
+
+		if got := strings.TrimSpace(rec.Body.String()); got != tt.body {
+			t.Fatalf("[%d] expected body: %q but got: %q", i, tt.body, got)
+		}
+
+		if got := rec.Header().Get("WWW-Authenticate"); got != tt.wwwAuthentication {
+			t.Fatalf("[%d] expected WWW-Authenticate: %q but got: %q", i, tt.wwwAuthentication, got)
+		}
+	}
+}
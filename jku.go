@@ -0,0 +1,155 @@
+package jwt
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"path"
+	"strings"
+	"sync"
+)
+
+// ErrJKUNotAllowed indicates that the token's "jku" header field
+// is missing or it does not match one of the URL prefixes given to `AllowJKU`.
+var ErrJKUNotAllowed = errors.New("jwt: jku not allowed")
+
+// maxJKUClients is the maximum number of key set URLs an `AllowJKU` validator caches,
+// the least recently used one is dropped to make room for a new one,
+// so tokens can not make it keep endless URLs under an allowed prefix.
+const maxJKUClients = 16
+
+// AllowJKU returns a ContextHeaderValidator which honors the "jku" header field
+// (RFC 7515, section 4.1.2), the URL of the key set the token was signed with,
+// but only if it matches one of the given URL "prefixes".
+// The keys are fetched and cached per URL by a `JWKSClient`
+// and the token's "kid" selects the verification key.
+//
+// The "jku" and "x5u" header fields are never fetched otherwise:
+// the `Verify` functions verify a token carrying them against the caller's keys only,
+// as blindly fetching a header-supplied URL lets an attacker choose the verification key
+// (and makes the server send requests to the attacker's infrastructure).
+//
+// The "jku" is parsed, not compared as a string: its scheme and host (including the port)
+// must equal the ones of a prefix and its path must be under the prefix's path,
+// segment by segment, e.g. "https://idp.example.com/keys" allows "https://idp.example.com/keys/jwks.json"
+// but not "https://idp.example.com/keys-other" or "https://idp.example.com.attacker.com/keys".
+// URLs with credentials, a query, a fragment, "." or ".." path segments
+// or a non canonical percent-encoding of their path are rejected.
+// It returns an ErrJKUNotAllowed error when the "jku" is missing or not allowed.
+//
+// Usage:
+//  verifiedToken, err := jwt.VerifyWithHeaderValidatorContext(ctx, nil, nil, token, jwt.AllowJKU("https://idp.example.com/keys/"))
+func AllowJKU(prefixes ...string) ContextHeaderValidator {
+	r := &jkuResolver{
+		clients: make(map[string]*list.Element),
+		lru:     list.New(),
+	}
+
+	for _, prefix := range prefixes {
+		if prefix == "" { // an empty one would allow any URL.
+			continue
+		}
+
+		u, ok := parseJKU(prefix)
+		if !ok {
+			continue
+		}
+
+		r.prefixes = append(r.prefixes, u)
+	}
+
+	return r.ValidateHeaderContext
+}
+
+type jkuResolver struct {
+	prefixes []*url.URL
+
+	mu      sync.Mutex               // protects the clients and the lru.
+	clients map[string]*list.Element // key = jku | value = the lru element of its *JWKSClient.
+	lru     *list.List               // the most recently used client first.
+}
+
+func (r *jkuResolver) ValidateHeaderContext(ctx context.Context, alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+	var h struct {
+		JKU string `json:"jku"`
+	}
+	if err := Unmarshal(headerDecoded, &h); err != nil {
+		return nil, nil, nil, err
+	}
+
+	if h.JKU == "" {
+		return nil, nil, nil, fmt.Errorf("%w: missing jku", ErrJKUNotAllowed)
+	}
+
+	if !r.allowed(h.JKU) {
+		return nil, nil, nil, fmt.Errorf("%w: %s", ErrJKUNotAllowed, h.JKU)
+	}
+
+	return r.client(h.JKU).ValidateHeaderContext(ctx, alg, headerDecoded)
+}
+
+// parseJKU parses the "rawURL" of a "jku" or a prefix of them.
+// It reports false if it's not an absolute URL of a host
+// or it holds anything which could fool the comparison of its path.
+func parseJKU(rawURL string) (*url.URL, bool) {
+	u, err := url.Parse(rawURL)
+	if err != nil || u.Scheme == "" || u.Host == "" || u.Opaque != "" ||
+		u.User != nil || u.RawQuery != "" || u.ForceQuery || u.Fragment != "" ||
+		u.RawPath != "" || strings.Contains(u.Path, "%") { // e.g. "%2F" or "%252e".
+		return nil, false
+	}
+
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	// A path which is not clean, e.g. "/keys/../admin" or "//keys", is not trusted.
+	if cleaned := path.Clean(u.Path); cleaned != u.Path && cleaned+"/" != u.Path {
+		return nil, false
+	}
+
+	return u, true
+}
+
+func (r *jkuResolver) allowed(jku string) bool {
+	u, ok := parseJKU(jku)
+	if !ok {
+		return false
+	}
+
+	for _, prefix := range r.prefixes {
+		if !strings.EqualFold(u.Scheme, prefix.Scheme) || !strings.EqualFold(u.Host, prefix.Host) {
+			continue
+		}
+
+		if u.Path == prefix.Path || strings.HasPrefix(u.Path, strings.TrimSuffix(prefix.Path, "/")+"/") {
+			return true
+		}
+	}
+
+	return false
+}
+
+// client returns the cached JWKSClient of the "jku", a new one if it's not cached,
+// which may drop the least recently used one.
+func (r *jkuResolver) client(jku string) *JWKSClient {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if elem, ok := r.clients[jku]; ok {
+		r.lru.MoveToFront(elem)
+		return elem.Value.(*JWKSClient)
+	}
+
+	if r.lru.Len() >= maxJKUClients {
+		oldest := r.lru.Back()
+		r.lru.Remove(oldest)
+		delete(r.clients, oldest.Value.(*JWKSClient).URL)
+	}
+
+	client := NewJWKSClient(jku)
+	r.clients[jku] = r.lru.PushFront(client)
+	return client
+}
@@ -0,0 +1,186 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+)
+
+func TestAllowJKU(t *testing.T) {
+	privateKey, publicKey, err := GenerateEdDSA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwk := `{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"first","x":"` + string(Base64Encode(publicKey)) + `"}]}`
+
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Write([]byte(jwk))
+	}))
+	defer srv.Close()
+
+	// An attacker signs a token with their own key and points the "jku" to their key set.
+	attackerPrivateKey, attackerPublicKey, err := GenerateEdDSA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var attackerHits int32
+	attackerSrv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attackerHits, 1)
+		w.Write([]byte(`{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"first","x":"` + string(Base64Encode(attackerPublicKey)) + `"}]}`))
+	}))
+	defer attackerSrv.Close()
+
+	sign := func(key PrivateKey, jku string) []byte {
+		token, err := Sign(EdDSA, key, Map{"username": "kataras"}, WithKid("first"), WithHeader("jku", jku), WithHeader("x5u", jku))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	malicious := sign(attackerPrivateKey, attackerSrv.URL+"/keys.json")
+
+	// By default the header URLs are ignored, the caller's keys are used.
+	if _, err = Verify(EdDSA, publicKey, malicious); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+
+	keys := make(Keys)
+	keys.Register(EdDSA, "first", publicKey, nil)
+	if err = keys.VerifyToken(malicious, nil); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+
+	// A token of a legit "jku" is verified against the caller's keys too.
+	legit := sign(privateKey, srv.URL+"/keys.json")
+	if _, err = Verify(EdDSA, publicKey, legit); err != nil {
+		t.Fatal(err)
+	}
+
+	allowJKU := AllowJKU(srv.URL+"/", "")
+	if _, err = VerifyWithHeaderValidatorContext(context.Background(), nil, nil, malicious, allowJKU); !errors.Is(err, ErrJKUNotAllowed) {
+		t.Fatalf("expected error: %v but got: %v", ErrJKUNotAllowed, err)
+	}
+
+	if hits := atomic.LoadInt32(&attackerHits); hits != 0 {
+		t.Fatalf("expected the attacker's key set to never be fetched but got: %d requests", hits)
+	}
+
+	// The allowed "jku" is fetched.
+	verifiedToken, err := VerifyWithHeaderValidatorContext(context.Background(), nil, nil, legit, allowJKU)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(verifiedToken.Payload) != `{"username":"kataras"}` {
+		t.Fatalf("unexpected payload: %s", verifiedToken.Payload)
+	}
+
+	if _, err = VerifyWithHeaderValidatorContext(context.Background(), nil, nil, legit, allowJKU); err != nil {
+		t.Fatal(err)
+	}
+
+	if hits := atomic.LoadInt32(&hits); hits != 1 {
+		t.Fatalf("expected the key set to be fetched once but got: %d requests", hits)
+	}
+
+	// Missing, look-alike and credentials, query or fragment URLs.
+	for i, jku := range []string{
+		"",
+		srv.URL + ".attacker.com/keys.json",
+		srv.URL + "/keys.json?k=v",
+		srv.URL + "/keys.json#k",
+		"http://user:pass@" + srv.URL[len("http://"):] + "/keys.json",
+		// Tricks of the path.
+		srv.URL + "/keys/../../keys.json",
+		srv.URL + "/%2e%2e/keys.json",
+		srv.URL + "/keys%2Fjwks.json",
+		srv.URL + "/keys%252e.json",
+		srv.URL + "//keys.json",
+		srv.URL + "/./keys.json",
+		// Other scheme or port.
+		"https://" + srv.URL[len("http://"):] + "/keys.json",
+		srv.URL + "0/keys.json",
+	} {
+		token := sign(privateKey, jku)
+		if _, err = VerifyWithHeaderValidatorContext(context.Background(), nil, nil, token, allowJKU); !errors.Is(err, ErrJKUNotAllowed) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, ErrJKUNotAllowed, err)
+		}
+	}
+
+	if hits := atomic.LoadInt32(&hits); hits != 1 {
+		t.Fatalf("expected no more requests but got: %d requests", hits)
+	}
+
+	// The path of a prefix is compared segment by segment.
+	allowKeys := AllowJKU(srv.URL + "/keys")
+	for jku, wantErr := range map[string]error{
+		srv.URL + "/keys":           nil,
+		srv.URL + "/keys/jwks.json": nil,
+		srv.URL + "/keys-other":     ErrJKUNotAllowed,
+		srv.URL + "/key":            ErrJKUNotAllowed,
+		srv.URL + "/":               ErrJKUNotAllowed,
+	} {
+		if _, err = VerifyWithHeaderValidatorContext(context.Background(), nil, nil, sign(privateKey, jku), allowKeys); !errors.Is(err, wantErr) {
+			t.Fatalf("%s: expected error: %v but got: %v", jku, wantErr, err)
+		}
+	}
+}
+
+func TestAllowJKUCache(t *testing.T) {
+	privateKey, publicKey, err := GenerateEdDSA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	jwk := `{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"first","x":"` + string(Base64Encode(publicKey)) + `"}]}`
+
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Write([]byte(jwk))
+	}))
+	defer srv.Close()
+
+	allowJKU := AllowJKU(srv.URL + "/")
+	verify := func(i int) {
+		token, err := Sign(EdDSA, privateKey, Map{"username": "kataras"}, WithKid("first"), WithHeader("jku", fmt.Sprintf("%s/%d.json", srv.URL, i)))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = VerifyWithHeaderValidatorContext(context.Background(), nil, nil, token, allowJKU); err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+	}
+
+	// More key set URLs than the cache holds are still verified.
+	for i := 0; i <= maxJKUClients; i++ {
+		verify(i)
+	}
+
+	if n := atomic.LoadInt32(&hits); n != maxJKUClients+1 {
+		t.Fatalf("expected %d requests but got: %d", maxJKUClients+1, n)
+	}
+
+	// The most recently used ones are still cached.
+	verify(maxJKUClients)
+	verify(1)
+	if n := atomic.LoadInt32(&hits); n != maxJKUClients+1 {
+		t.Fatalf("expected no more requests but got: %d", n)
+	}
+
+	// The least recently used one was dropped.
+	verify(0)
+	if n := atomic.LoadInt32(&hits); n != maxJKUClients+2 {
+		t.Fatalf("expected the dropped key set to be fetched again but got: %d requests", n)
+	}
+}
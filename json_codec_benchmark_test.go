@@ -0,0 +1,36 @@
+package jwt
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func benchmarkVerifyJSONCodec(b *testing.B) {
+	token, err := Sign(testAlg, testSecret, testStructValue, MaxAge(15*time.Minute))
+	if err != nil {
+		b.Fatal(err)
+	}
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		var claims testStruct
+		if err = VerifyToken(testAlg, testSecret, token, &claims); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkVerifyDefaultJSONCodec(b *testing.B) {
+	benchmarkVerifyJSONCodec(b)
+}
+
+func BenchmarkVerifyCustomJSONCodec(b *testing.B) {
+	resetJSONCodec(b)
+	// A trivial codec, which skips the default json.Number decoding.
+	SetJSONCodec(json.Marshal, json.Unmarshal)
+
+	benchmarkVerifyJSONCodec(b)
+}
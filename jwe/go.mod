@@ -0,0 +1,8 @@
+module github.com/kataras/jwt/jwe
+
+go 1.18
+
+require (
+	github.com/kataras/jwt v0.1.8
+	golang.org/x/crypto v0.17.0
+)
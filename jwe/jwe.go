@@ -0,0 +1,306 @@
+// Package jwe encrypts signed tokens to nested JSON Web Tokens (RFC 7519, section 5.2),
+// JSON Web Encryption tokens (RFC 7516) with a "cty":"JWT" header,
+// so the claims can not be read in transit.
+//
+// The content encryption key is agreed through the ECDH-ES key agreement
+// (RFC 7518, section 4.6) using ephemeral X25519 keys (RFC 8037)
+// and the content is encrypted with AES-GCM.
+//
+// It lives in its own module so the users who don't need it
+// don't pull the golang.org/x/crypto dependency.
+// The signing API of the jwt package is not affected,
+// the token is signed first and then encrypted for the recipient.
+//
+// Usage:
+//
+//  // recipient
+//  privateKey, publicKey, err := jwe.GenerateKey()
+//  // producer
+//  token, err := jwt.Sign(jwt.EdDSA, signingKey, claims, jwt.MaxAge(15*time.Minute))
+//  encrypted, err := jwe.EncryptJWT(token, publicKey, jwe.A256GCM)
+//  // recipient
+//  token, err = jwe.DecryptJWT(encrypted, privateKey)
+//  verifiedToken, err := jwt.Verify(jwt.EdDSA, verifyKey, token)
+package jwe
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/kataras/jwt"
+
+	"golang.org/x/crypto/curve25519"
+)
+
+// The content encryption algorithms, the "enc" header field.
+const (
+	A128GCM = "A128GCM"
+	A192GCM = "A192GCM"
+	A256GCM = "A256GCM"
+)
+
+// ECDHES is the key agreement algorithm, the "alg" header field.
+const ECDHES = "ECDH-ES"
+
+// KeySize is the size, in bytes, of the X25519 private and public keys.
+const KeySize = curve25519.ScalarSize
+
+// ErrDecryption indicates that the token could not be decrypted,
+// e.g. it was encrypted for another recipient or it was modified.
+var ErrDecryption = errors.New("jwt: jwe: decryption failed")
+
+// GenerateKey generates a new pair of X25519 private and public keys.
+// The public key is given to the producers of the tokens (`EncryptJWT`)
+// and the private key is kept by the recipient (`DecryptJWT`).
+func GenerateKey() (privateKey, publicKey []byte, err error) {
+	privateKey = make([]byte, KeySize)
+	if _, err = rand.Read(privateKey); err != nil {
+		return nil, nil, err
+	}
+
+	publicKey, err = curve25519.X25519(privateKey, curve25519.Basepoint)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return privateKey, publicKey, nil
+}
+
+type (
+	header struct {
+		Alg  string      `json:"alg"`
+		Enc  string      `json:"enc"`
+		Cty  string      `json:"cty"`
+		Epk  ephemeralPK `json:"epk"`
+		Zip  string      `json:"zip,omitempty"`
+		Crit []string    `json:"crit,omitempty"`
+	}
+
+	ephemeralPK struct {
+		Kty string `json:"kty"`
+		Crv string `json:"crv"`
+		X   string `json:"x"`
+	}
+)
+
+// EncryptJWT encrypts the "signedToken" (e.g. the result of `jwt.Sign`)
+// for the recipient of the X25519 "recipientPublicKey"
+// and returns the JWE token of compact form.
+// The "enc" is the content encryption algorithm: A128GCM, A192GCM or A256GCM.
+func EncryptJWT(signedToken []byte, recipientPublicKey []byte, enc string) ([]byte, error) {
+	if len(signedToken) == 0 {
+		return nil, jwt.ErrMissing
+	}
+
+	if len(recipientPublicKey) != KeySize {
+		return nil, jwt.ErrInvalidKey
+	}
+
+	keySize, err := contentKeySize(enc)
+	if err != nil {
+		return nil, err
+	}
+
+	ephemeralPrivateKey, ephemeralPublicKey, err := GenerateKey()
+	if err != nil {
+		return nil, err
+	}
+
+	sharedSecret, err := curve25519.X25519(ephemeralPrivateKey, recipientPublicKey)
+	if err != nil { // a low order point.
+		return nil, fmt.Errorf("%w: %v", jwt.ErrInvalidKey, err)
+	}
+
+	h := header{
+		Alg: ECDHES,
+		Enc: enc,
+		Cty: "JWT",
+		Epk: ephemeralPK{Kty: "OKP", Crv: "X25519", X: string(jwt.Base64Encode(ephemeralPublicKey))},
+	}
+
+	headerJSON, err := json.Marshal(h)
+	if err != nil {
+		return nil, err
+	}
+	encodedHeader := jwt.Base64Encode(headerJSON)
+
+	aead, err := newAEAD(concatKDF(sharedSecret, enc, nil, nil, keySize))
+	if err != nil {
+		return nil, err
+	}
+
+	iv := make([]byte, aead.NonceSize())
+	if _, err = rand.Read(iv); err != nil {
+		return nil, err
+	}
+
+	// The additional authenticated data is the encoded protected header.
+	sealed := aead.Seal(nil, iv, signedToken, encodedHeader)
+	ciphertext, tag := sealed[:len(sealed)-aead.Overhead()], sealed[len(sealed)-aead.Overhead():]
+
+	// header.encryptedKey(empty, direct key agreement).iv.ciphertext.tag
+	token := make([]byte, 0, len(encodedHeader)+len(sealed)*2+32)
+	token = append(token, encodedHeader...)
+	token = append(token, '.', '.')
+	token = append(token, jwt.Base64Encode(iv)...)
+	token = append(token, '.')
+	token = append(token, jwt.Base64Encode(ciphertext)...)
+	token = append(token, '.')
+	token = append(token, jwt.Base64Encode(tag)...)
+	return token, nil
+}
+
+// DecryptJWT decrypts the JWE "token" of compact form, produced by `EncryptJWT`,
+// with the recipient's X25519 "privateKey" and returns the nested signed token.
+// The returned token is NOT verified, pass it to `jwt.Verify`.
+//
+// It returns a jwt.ErrTokenForm error when the token is malformed,
+// a jwt.ErrTokenAlg error when its "alg", "enc" or "cty" is not supported
+// and an ErrDecryption error when it can not be decrypted with the "privateKey".
+func DecryptJWT(token []byte, privateKey []byte) ([]byte, error) {
+	if len(token) == 0 {
+		return nil, jwt.ErrMissing
+	}
+
+	if len(privateKey) != KeySize {
+		return nil, jwt.ErrInvalidKey
+	}
+
+	parts := bytes.Split(token, []byte("."))
+	if len(parts) != 5 {
+		return nil, jwt.ErrTokenForm
+	}
+
+	encodedHeader, encryptedKey := parts[0], parts[1]
+	if len(encryptedKey) != 0 { // direct key agreement, no encrypted key.
+		return nil, fmt.Errorf("%w: unexpected encrypted key", jwt.ErrTokenForm)
+	}
+
+	headerJSON, err := jwt.Base64Decode(encodedHeader)
+	if err != nil {
+		return nil, fmt.Errorf("%w: header: %v", jwt.ErrTokenForm, err)
+	}
+
+	var h header
+	if err = json.Unmarshal(headerJSON, &h); err != nil {
+		return nil, fmt.Errorf("%w: header: %v", jwt.ErrTokenForm, err)
+	}
+
+	if h.Alg != ECDHES {
+		return nil, fmt.Errorf("%w: alg: %q", jwt.ErrTokenAlg, h.Alg)
+	}
+
+	if !strings.EqualFold(h.Cty, "JWT") {
+		return nil, fmt.Errorf("%w: cty: %q", jwt.ErrTokenAlg, h.Cty)
+	}
+
+	if h.Zip != "" || len(h.Crit) > 0 {
+		return nil, fmt.Errorf("%w: unsupported zip or crit header", jwt.ErrTokenAlg)
+	}
+
+	keySize, err := contentKeySize(h.Enc)
+	if err != nil {
+		return nil, err
+	}
+
+	if h.Epk.Kty != "OKP" || h.Epk.Crv != "X25519" {
+		return nil, fmt.Errorf("%w: epk: %s %s", jwt.ErrTokenAlg, h.Epk.Kty, h.Epk.Crv)
+	}
+
+	ephemeralPublicKey, err := jwt.Base64Decode([]byte(h.Epk.X))
+	if err != nil || len(ephemeralPublicKey) != KeySize {
+		return nil, fmt.Errorf("%w: epk: invalid x", jwt.ErrTokenForm)
+	}
+
+	var decoded [3][]byte // iv, ciphertext, tag.
+	for i, part := range parts[2:] {
+		if decoded[i], err = jwt.Base64Decode(part); err != nil {
+			return nil, fmt.Errorf("%w: %v", jwt.ErrTokenForm, err)
+		}
+	}
+	iv, ciphertext, tag := decoded[0], decoded[1], decoded[2]
+
+	sharedSecret, err := curve25519.X25519(privateKey, ephemeralPublicKey)
+	if err != nil { // a low order point.
+		return nil, ErrDecryption
+	}
+
+	aead, err := newAEAD(concatKDF(sharedSecret, h.Enc, nil, nil, keySize))
+	if err != nil {
+		return nil, err
+	}
+
+	if len(iv) != aead.NonceSize() || len(tag) != aead.Overhead() {
+		return nil, jwt.ErrTokenForm
+	}
+
+	signedToken, err := aead.Open(nil, iv, append(ciphertext, tag...), encodedHeader)
+	if err != nil {
+		return nil, ErrDecryption
+	}
+
+	return signedToken, nil
+}
+
+func contentKeySize(enc string) (int, error) {
+	switch enc {
+	case A128GCM:
+		return 16, nil
+	case A192GCM:
+		return 24, nil
+	case A256GCM:
+		return 32, nil
+	default:
+		return 0, fmt.Errorf("%w: enc: %q", jwt.ErrTokenAlg, enc)
+	}
+}
+
+func newAEAD(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return cipher.NewGCM(block)
+}
+
+// concatKDF derives the content encryption key of "keySize" bytes
+// from the shared secret "z", RFC 7518, section 4.6.2 (NIST SP 800-56A Concat KDF with SHA-256).
+// The "algID" is the "enc" value on direct key agreement.
+func concatKDF(z []byte, algID string, apu, apv []byte, keySize int) []byte {
+	var otherInfo []byte
+	otherInfo = appendLengthPrefixed(otherInfo, []byte(algID))
+	otherInfo = appendLengthPrefixed(otherInfo, apu)
+	otherInfo = appendLengthPrefixed(otherInfo, apv)
+	otherInfo = appendUint32(otherInfo, uint32(keySize*8)) // SuppPubInfo: keydatalen in bits.
+
+	key := make([]byte, 0, keySize+sha256.Size)
+	for counter := uint32(1); len(key) < keySize; counter++ {
+		h := sha256.New()
+		h.Write(appendUint32(nil, counter))
+		h.Write(z)
+		h.Write(otherInfo)
+		key = h.Sum(key)
+	}
+
+	return key[:keySize]
+}
+
+func appendLengthPrefixed(dst, b []byte) []byte {
+	dst = appendUint32(dst, uint32(len(b)))
+	return append(dst, b...)
+}
+
+func appendUint32(dst []byte, v uint32) []byte {
+	var b [4]byte
+	binary.BigEndian.PutUint32(b[:], v)
+	return append(dst, b[:]...)
+}
@@ -0,0 +1,139 @@
+package jwe
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/kataras/jwt"
+)
+
+var testSecret = []byte("sercrethatmaycontainch@r$32chars")
+
+func TestEncryptJWT(t *testing.T) {
+	privateKey, publicKey, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := jwt.Sign(jwt.HS256, testSecret, jwt.Map{"username": "kataras"}, jwt.MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, enc := range []string{A128GCM, A192GCM, A256GCM} {
+		encrypted, err := EncryptJWT(token, publicKey, enc)
+		if err != nil {
+			t.Fatalf("[%s] %v", enc, err)
+		}
+
+		if bytes.Contains(encrypted, []byte(strings.Split(string(token), ".")[1])) {
+			t.Fatalf("[%s] expected the claims to be encrypted", enc)
+		}
+
+		parts := strings.Split(string(encrypted), ".")
+		if len(parts) != 5 || parts[1] != "" {
+			t.Fatalf("[%s] expected a compact JWE with an empty encrypted key but got: %s", enc, encrypted)
+		}
+
+		headerJSON, err := jwt.Base64Decode([]byte(parts[0]))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var h header
+		if err = json.Unmarshal(headerJSON, &h); err != nil {
+			t.Fatal(err)
+		}
+
+		if h.Alg != ECDHES || h.Enc != enc || h.Cty != "JWT" || h.Epk.Kty != "OKP" || h.Epk.Crv != "X25519" {
+			t.Fatalf("[%s] unexpected header: %s", enc, headerJSON)
+		}
+
+		decrypted, err := DecryptJWT(encrypted, privateKey)
+		if err != nil {
+			t.Fatalf("[%s] %v", enc, err)
+		}
+
+		if !bytes.Equal(decrypted, token) {
+			t.Fatalf("[%s] expected the signed token:\n%s\nbut got:\n%s", enc, token, decrypted)
+		}
+
+		if _, err = jwt.Verify(jwt.HS256, testSecret, decrypted); err != nil {
+			t.Fatalf("[%s] %v", enc, err)
+		}
+	}
+}
+
+func TestDecryptJWTFailures(t *testing.T) {
+	privateKey, publicKey, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	otherPrivateKey, _, err := GenerateKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	encrypted, err := EncryptJWT([]byte("header.payload.signature"), publicKey, A256GCM)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = DecryptJWT(encrypted, otherPrivateKey); !errors.Is(err, ErrDecryption) {
+		t.Fatalf("expected error: %v but got: %v", ErrDecryption, err)
+	}
+
+	parts := strings.Split(string(encrypted), ".")
+	ciphertext, _ := jwt.Base64Decode([]byte(parts[3]))
+	ciphertext[0] ^= 1
+	parts[3] = string(jwt.Base64Encode(ciphertext))
+	if _, err = DecryptJWT([]byte(strings.Join(parts, ".")), privateKey); !errors.Is(err, ErrDecryption) {
+		t.Fatalf("expected error on modified ciphertext: %v but got: %v", ErrDecryption, err)
+	}
+
+	// The header is authenticated too.
+	parts = strings.Split(string(encrypted), ".")
+	headerJSON, _ := jwt.Base64Decode([]byte(parts[0]))
+	parts[0] = string(jwt.Base64Encode(bytes.Replace(headerJSON, []byte(`"cty":"JWT"`), []byte(`"cty":"jwt"`), 1)))
+	if _, err = DecryptJWT([]byte(strings.Join(parts, ".")), privateKey); !errors.Is(err, ErrDecryption) {
+		t.Fatalf("expected error on modified header: %v but got: %v", ErrDecryption, err)
+	}
+
+	parts[0] = string(jwt.Base64Encode(bytes.Replace(headerJSON, []byte(`"enc":"A256GCM"`), []byte(`"enc":"A256CBC-HS512"`), 1)))
+	if _, err = DecryptJWT([]byte(strings.Join(parts, ".")), privateKey); !errors.Is(err, jwt.ErrTokenAlg) {
+		t.Fatalf("expected error on unsupported enc: %v but got: %v", jwt.ErrTokenAlg, err)
+	}
+
+	if _, err = DecryptJWT([]byte("a.b.c"), privateKey); !errors.Is(err, jwt.ErrTokenForm) {
+		t.Fatalf("expected error: %v but got: %v", jwt.ErrTokenForm, err)
+	}
+
+	if _, err = EncryptJWT([]byte("header.payload.signature"), publicKey[:10], A256GCM); !errors.Is(err, jwt.ErrInvalidKey) {
+		t.Fatalf("expected error: %v but got: %v", jwt.ErrInvalidKey, err)
+	}
+
+	if _, err = EncryptJWT([]byte("header.payload.signature"), make([]byte, KeySize), A256GCM); !errors.Is(err, jwt.ErrInvalidKey) {
+		t.Fatalf("expected error on a low order public key: %v but got: %v", jwt.ErrInvalidKey, err)
+	}
+
+	if _, err = EncryptJWT([]byte("header.payload.signature"), publicKey, "A512GCM"); !errors.Is(err, jwt.ErrTokenAlg) {
+		t.Fatalf("expected error: %v but got: %v", jwt.ErrTokenAlg, err)
+	}
+}
+
+// RFC 7518, appendix C.
+func TestConcatKDF(t *testing.T) {
+	z := []byte{158, 86, 217, 29, 129, 113, 53, 211, 114, 131, 66, 131, 191, 132,
+		38, 156, 251, 49, 110, 163, 218, 128, 106, 72, 246, 218, 167, 121,
+		140, 254, 144, 196}
+
+	key := concatKDF(z, A128GCM, []byte("Alice"), []byte("Bob"), 16)
+	if expected, got := "VqqN6vgjbSBcIijNcacQGg", string(jwt.Base64Encode(key)); expected != got {
+		t.Fatalf("expected key: %s but got: %s", expected, got)
+	}
+}
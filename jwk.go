@@ -0,0 +1,376 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"crypto/ed25519"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+var (
+	// ErrJWKType indicates that the JSON Web Key's "kty" field
+	// is not the one expected by the parser (e.g. an "RSA" key given to an EdDSA parser).
+	ErrJWKType = errors.New("jwt: jwk: unexpected key type")
+	// ErrJWKCurve indicates that the JSON Web Key's "crv" field
+	// is not the one expected by the parser.
+	ErrJWKCurve = errors.New("jwt: jwk: unexpected curve")
+	// ErrJWKAlg indicates that the JSON Web Key's "alg" field
+	// is not an algorithm of its key type and curve (e.g. an "RSA" key labelled "HS256").
+	ErrJWKAlg = errors.New("jwt: jwk: unexpected algorithm")
+)
+
+// JWK represents a JSON Web Key (RFC 7517).
+// Only the fields this package makes use of are declared.
+type JWK struct {
+	Kty string `json:"kty"`
+	Crv string `json:"crv,omitempty"`
+	Kid string `json:"kid,omitempty"`
+	Alg string `json:"alg,omitempty"`
+	Use string `json:"use,omitempty"`
+	// The public key (base64url-encoded) of an "OKP" key
+	// or the x coordinate of an "EC" key.
+	X string `json:"x,omitempty"`
+	// The y coordinate (base64url-encoded) of an "EC" key.
+	Y string `json:"y,omitempty"`
+	// The modulus (base64url-encoded) of an "RSA" key.
+	N string `json:"n,omitempty"`
+	// The public exponent (base64url-encoded) of an "RSA" key.
+	E string `json:"e,omitempty"`
+	// The private key (base64url-encoded) of an "OKP" key.
+	D string `json:"d,omitempty"`
+}
+
+// PublicKey returns the algorithm and the Go public key value of the JWK.
+// Supported key types are the "OKP" (Ed25519), "EC" (P-256, P-384 and P-521) and "RSA".
+// If the JWK's "alg" field is empty then the algorithm
+// is resolved by the key type: EdDSA, ES256/ES384/ES512 and RS256 respectively.
+// Otherwise it must be one of the builtin algorithms of the key type and curve,
+// e.g. RS256 or PS512 for an "RSA" key, or it fails with ErrJWKAlg.
+func (jwk *JWK) PublicKey() (Alg, PublicKey, error) {
+	var (
+		alg       Alg
+		algs      []Alg // the algorithms the "alg" field may name.
+		publicKey PublicKey
+		err       error
+	)
+
+	switch jwk.Kty {
+	case "OKP":
+		if jwk.Crv != "Ed25519" {
+			return nil, nil, fmt.Errorf("%w: %q", ErrJWKCurve, jwk.Crv)
+		}
+
+		alg, algs = EdDSA, []Alg{EdDSA}
+		publicKey, err = decodeJWKPublicKeyEdDSA(jwk)
+	case "EC":
+		var curve elliptic.Curve
+		switch jwk.Crv {
+		case "P-256":
+			alg, curve = ES256, elliptic.P256()
+		case "P-384":
+			alg, curve = ES384, elliptic.P384()
+		case "P-521":
+			alg, curve = ES512, elliptic.P521()
+		default:
+			return nil, nil, fmt.Errorf("%w: %q", ErrJWKCurve, jwk.Crv)
+		}
+
+		algs = []Alg{alg}
+		publicKey, err = decodeJWKPublicKeyECDSA(jwk, curve)
+	case "RSA":
+		alg, algs = RS256, []Alg{RS256, RS384, RS512, PS256, PS384, PS512}
+		publicKey, err = decodeJWKPublicKeyRSA(jwk)
+	default:
+		return nil, nil, fmt.Errorf("%w: %q", ErrJWKType, jwk.Kty)
+	}
+
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if jwk.Alg != "" {
+		// Not through the AlgByName, a registered algorithm
+		// could replace a builtin one of the same name.
+		alg = nil
+		for _, a := range algs {
+			if a.Name() == jwk.Alg {
+				alg = a
+				break
+			}
+		}
+
+		if alg == nil {
+			return nil, nil, fmt.Errorf("%w: %q for key type: %q", ErrJWKAlg, jwk.Alg, jwk.Kty)
+		}
+	}
+
+	return alg, publicKey, nil
+}
+
+// ParsePublicKeyEdDSAFromJWK decodes and parses the
+// JSON Web Key of an ed25519 public key,
+// e.g. {"kty":"OKP","crv":"Ed25519","x":"..."}.
+// Pass the result to the `Verify` function.
+//
+// It returns a type of ErrJWKType or ErrJWKCurve if
+// the JWK is not an "OKP" key of the "Ed25519" curve.
+func ParsePublicKeyEdDSAFromJWK(key []byte) (ed25519.PublicKey, error) {
+	jwk, err := parseJWKEdDSA(key)
+	if err != nil {
+		return nil, err
+	}
+
+	return decodeJWKPublicKeyEdDSA(jwk)
+}
+
+// ParsePrivateKeyEdDSAFromJWK decodes and parses the
+// JSON Web Key of an ed25519 private key,
+// e.g. {"kty":"OKP","crv":"Ed25519","x":"...","d":"..."}.
+// Pass the result to the `Token` (signing) function.
+//
+// It returns a type of ErrJWKType or ErrJWKCurve if
+// the JWK is not an "OKP" key of the "Ed25519" curve.
+func ParsePrivateKeyEdDSAFromJWK(key []byte) (ed25519.PrivateKey, error) {
+	jwk, err := parseJWKEdDSA(key)
+	if err != nil {
+		return nil, err
+	}
+
+	if jwk.D == "" {
+		return nil, fmt.Errorf("private key: jwk: missing \"d\"")
+	}
+
+	seed, err := Base64Decode([]byte(jwk.D))
+	if err != nil {
+		return nil, fmt.Errorf("private key: jwk: \"d\": %w", err)
+	}
+
+	if l := len(seed); l != ed25519.SeedSize {
+		return nil, fmt.Errorf("private key: jwk: bad seed length: %d", l)
+	}
+
+	privateKey := ed25519.NewKeyFromSeed(seed)
+
+	if jwk.X != "" { // make sure the key pair matches.
+		publicKey, err := decodeJWKPublicKeyEdDSA(jwk)
+		if err != nil {
+			return nil, err
+		}
+
+		if !bytes.Equal(publicKey, privateKey.Public().(ed25519.PublicKey)) {
+			return nil, fmt.Errorf("private key: jwk: \"x\" does not match the \"d\" public key")
+		}
+	}
+
+	return privateKey, nil
+}
+
+// MarshalPublicKeyEdDSAToJWK encodes the ed25519 public key
+// to the JSON Web Key form, which `ParsePublicKeyEdDSAFromJWK` expects,
+// e.g. {"kty":"OKP","crv":"Ed25519","kid":"...","x":"..."}.
+// When "kid" is empty, the key's `ThumbprintEdDSA` is used instead,
+// so the same key always gets the same "kid".
+//
+// See `MarshalJWKS` too.
+func MarshalPublicKeyEdDSAToJWK(key ed25519.PublicKey, kid string) ([]byte, error) {
+	if len(key) != ed25519.PublicKeySize {
+		return nil, ErrInvalidKey
+	}
+
+	if kid == "" {
+		thumbprint, err := ThumbprintEdDSA(key)
+		if err != nil {
+			return nil, err
+		}
+
+		kid = thumbprint
+	}
+
+	jwk := JWK{
+		Kty: "OKP",
+		Crv: "Ed25519",
+		Kid: kid,
+		X:   string(Base64Encode(key)), // raw, no padding.
+	}
+
+	return json.Marshal(jwk)
+}
+
+// ThumbprintEdDSA returns the JWK Thumbprint (RFC 7638) of the ed25519 public key:
+// the base64url-encoded SHA-256 digest of its canonical JSON Web Key,
+// which holds only the required members in lexicographic order:
+// {"crv":"Ed25519","kty":"OKP","x":"..."} (RFC 8037, appendix A.3).
+// Useful as a stable "kid", see `MarshalPublicKeyEdDSAToJWK`.
+func ThumbprintEdDSA(key ed25519.PublicKey) (string, error) {
+	if len(key) != ed25519.PublicKeySize {
+		return "", ErrInvalidKey
+	}
+
+	// The members are written manually, as RFC 7638 requires this exact form
+	// (no whitespace, sorted keys) and the "x" value needs no JSON escaping.
+	canonical := `{"crv":"Ed25519","kty":"OKP","x":"` + string(Base64Encode(key)) + `"}`
+	digest := sha256.Sum256([]byte(canonical))
+
+	return string(Base64Encode(digest[:])), nil
+}
+
+// Thumbprint returns the JWK Thumbprint (RFC 7638) of the JWK's public key:
+// the base64url-encoded SHA-256 digest of its required members in lexicographic order,
+// e.g. the "jkt" of a "cnf" claim (RFC 9449), see `Confirmation`.
+// Supported key types are the "OKP", "EC" and "RSA".
+func (jwk *JWK) Thumbprint() (string, error) {
+	var members interface{}
+
+	// The structure fields are declared in lexicographic order,
+	// so the encoded JSON is the canonical one RFC 7638 requires.
+	switch jwk.Kty {
+	case "OKP":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+		}{jwk.Crv, jwk.Kty, jwk.X}
+		if jwk.Crv == "" || jwk.X == "" {
+			return "", ErrInvalidKey
+		}
+	case "EC":
+		members = struct {
+			Crv string `json:"crv"`
+			Kty string `json:"kty"`
+			X   string `json:"x"`
+			Y   string `json:"y"`
+		}{jwk.Crv, jwk.Kty, jwk.X, jwk.Y}
+		if jwk.Crv == "" || jwk.X == "" || jwk.Y == "" {
+			return "", ErrInvalidKey
+		}
+	case "RSA":
+		members = struct {
+			E   string `json:"e"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+		}{jwk.E, jwk.Kty, jwk.N}
+		if jwk.E == "" || jwk.N == "" {
+			return "", ErrInvalidKey
+		}
+	default:
+		return "", fmt.Errorf("%w: %q", ErrJWKType, jwk.Kty)
+	}
+
+	canonical, err := json.Marshal(members)
+	if err != nil {
+		return "", err
+	}
+
+	digest := sha256.Sum256(canonical)
+	return string(Base64Encode(digest[:])), nil
+}
+
+// MarshalJWKS wraps one or more encoded JSON Web Keys
+// (e.g. the result of `MarshalPublicKeyEdDSAToJWK`)
+// to a JSON Web Key Set document: {"keys":[...]}.
+// Useful to publish the verification keys through a "/.well-known/jwks.json" endpoint.
+func MarshalJWKS(keys ...[]byte) ([]byte, error) {
+	set := struct {
+		Keys []json.RawMessage `json:"keys"`
+	}{
+		Keys: make([]json.RawMessage, 0, len(keys)),
+	}
+
+	for _, key := range keys {
+		set.Keys = append(set.Keys, json.RawMessage(key))
+	}
+
+	return json.Marshal(set) // it fails if a key is not a valid JSON.
+}
+
+func parseJWKEdDSA(key []byte) (*JWK, error) {
+	var jwk JWK
+	if err := Unmarshal(key, &jwk); err != nil {
+		return nil, fmt.Errorf("jwk: %w", err)
+	}
+
+	if jwk.Kty != "OKP" {
+		return nil, fmt.Errorf("%w: %q", ErrJWKType, jwk.Kty)
+	}
+
+	if jwk.Crv != "Ed25519" {
+		return nil, fmt.Errorf("%w: %q", ErrJWKCurve, jwk.Crv)
+	}
+
+	return &jwk, nil
+}
+
+func decodeJWKPublicKeyEdDSA(jwk *JWK) (ed25519.PublicKey, error) {
+	if jwk.X == "" {
+		return nil, fmt.Errorf("public key: jwk: missing \"x\"")
+	}
+
+	x, err := Base64Decode([]byte(jwk.X))
+	if err != nil {
+		return nil, fmt.Errorf("public key: jwk: \"x\": %w", err)
+	}
+
+	if l := len(x); l != ed25519.PublicKeySize {
+		return nil, fmt.Errorf("public key: jwk: bad length: %d", l)
+	}
+
+	return ed25519.PublicKey(x), nil
+}
+
+func decodeJWKPublicKeyECDSA(jwk *JWK, curve elliptic.Curve) (*ecdsa.PublicKey, error) {
+	x, err := Base64Decode([]byte(jwk.X))
+	if err != nil {
+		return nil, fmt.Errorf("public key: jwk: \"x\": %w", err)
+	}
+
+	y, err := Base64Decode([]byte(jwk.Y))
+	if err != nil {
+		return nil, fmt.Errorf("public key: jwk: \"y\": %w", err)
+	}
+
+	publicKey := &ecdsa.PublicKey{
+		Curve: curve,
+		X:     new(big.Int).SetBytes(x),
+		Y:     new(big.Int).SetBytes(y),
+	}
+
+	if !curve.IsOnCurve(publicKey.X, publicKey.Y) {
+		return nil, fmt.Errorf("public key: jwk: the point is not on the %s curve", jwk.Crv)
+	}
+
+	return publicKey, nil
+}
+
+func decodeJWKPublicKeyRSA(jwk *JWK) (*rsa.PublicKey, error) {
+	n, err := Base64Decode([]byte(jwk.N))
+	if err != nil {
+		return nil, fmt.Errorf("public key: jwk: \"n\": %w", err)
+	}
+
+	e, err := Base64Decode([]byte(jwk.E))
+	if err != nil {
+		return nil, fmt.Errorf("public key: jwk: \"e\": %w", err)
+	}
+
+	if len(n) == 0 || len(e) == 0 || len(e) > 4 {
+		return nil, fmt.Errorf("public key: jwk: malformed RSA key")
+	}
+
+	exponent := 0
+	for _, b := range e {
+		exponent = exponent<<8 | int(b)
+	}
+
+	publicKey := &rsa.PublicKey{
+		N: new(big.Int).SetBytes(n),
+		E: exponent,
+	}
+
+	return publicKey, nil
+}
@@ -0,0 +1,219 @@
+package jwt
+
+import (
+	"bytes"
+	"crypto/ed25519"
+	"errors"
+	"testing"
+)
+
+// RFC 8037, appendix A.1 and A.2.
+const (
+	testJWKPrivateKeyEdDSA = `{"kty":"OKP","crv":"Ed25519","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`
+	testJWKPublicKeyEdDSA  = `{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`
+)
+
+func TestParseKeyEdDSAFromJWK(t *testing.T) {
+	privateKey, err := ParsePrivateKeyEdDSAFromJWK([]byte(testJWKPrivateKeyEdDSA))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	publicKey, err := ParsePublicKeyEdDSAFromJWK([]byte(testJWKPublicKeyEdDSA))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(publicKey, privateKey.Public().(ed25519.PublicKey)) {
+		t.Fatalf("expected the public key to match the private's one")
+	}
+
+	testEncodeDecodeToken(t, EdDSA, privateKey, publicKey, nil)
+
+	var tests = []struct {
+		jwk     string
+		wantErr error
+	}{
+		{`{"kty":"RSA","n":"0vx7","e":"AQAB"}`, ErrJWKType},
+		{`{"kty":"OKP","crv":"X25519","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`, ErrJWKCurve},
+		{`{"kty":"OKP","crv":"Ed25519","x":"11qYAYKxCrfVS_7TyWQHOg"}`, nil},
+		{`{"kty":"OKP","crv":"Ed25519"}`, nil},
+		{`{"kty":"OKP"`, nil},
+	}
+
+	for i, tt := range tests {
+		_, err = ParsePublicKeyEdDSAFromJWK([]byte(tt.jwk))
+		if err == nil {
+			t.Fatalf("[%d] expected to fail", i)
+		}
+
+		if tt.wantErr != nil && !errors.Is(err, tt.wantErr) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.wantErr, err)
+		}
+	}
+
+	// Test mismatch of the private and public parts.
+	_, err = ParsePrivateKeyEdDSAFromJWK([]byte(`{"kty":"OKP","crv":"Ed25519","d":"nWGxne_9WmC6hEr0kuwsxERJxWl7MmkZcDusAxyuf2A","x":"` + string(Base64Encode(make([]byte, 32))) + `"}`))
+	if err == nil {
+		t.Fatalf("expected to fail on key pair mismatch")
+	}
+
+	// Test missing private key.
+	if _, err = ParsePrivateKeyEdDSAFromJWK([]byte(testJWKPublicKeyEdDSA)); err == nil {
+		t.Fatalf("expected to fail on missing \"d\"")
+	}
+}
+
+func TestJWKPublicKey(t *testing.T) {
+	rsaPrivateKey, err := LoadPrivateKeyRSA("./_testfiles/rsa_private_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ecdsaPrivateKey, err := LoadPrivateKeyECDSA("./_testfiles/ecdsa_private_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var tests = []struct {
+		jwk        JWK
+		alg        Alg
+		privateKey PrivateKey
+	}{
+		{JWK{Kty: "RSA", N: string(Base64Encode(rsaPrivateKey.N.Bytes())), E: "AQAB"}, RS256, rsaPrivateKey},
+		{JWK{Kty: "RSA", Alg: "PS256", N: string(Base64Encode(rsaPrivateKey.N.Bytes())), E: "AQAB"}, PS256, rsaPrivateKey},
+		{JWK{Kty: "EC", Crv: "P-256", X: string(Base64Encode(ecdsaPrivateKey.X.Bytes())), Y: string(Base64Encode(ecdsaPrivateKey.Y.Bytes()))}, ES256, ecdsaPrivateKey},
+	}
+
+	for i, tt := range tests {
+		alg, publicKey, err := tt.jwk.PublicKey()
+		if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if alg != tt.alg {
+			t.Fatalf("[%d] expected algorithm: %s but got: %s", i, tt.alg.Name(), alg.Name())
+		}
+
+		testEncodeDecodeToken(t, alg, tt.privateKey, publicKey, nil)
+	}
+
+	if _, _, err = (&JWK{Kty: "EC", Crv: "P-256", X: "AQAB", Y: "AQAB"}).PublicKey(); err == nil {
+		t.Fatalf("expected to fail on invalid curve point")
+	}
+
+	// The "alg" must be an algorithm of the key type and curve.
+	for i, jwk := range []JWK{
+		{Kty: "RSA", Alg: "HS256", N: string(Base64Encode(rsaPrivateKey.N.Bytes())), E: "AQAB"},
+		{Kty: "RSA", Alg: "ES256", N: string(Base64Encode(rsaPrivateKey.N.Bytes())), E: "AQAB"},
+		{Kty: "RSA", Alg: "none", N: string(Base64Encode(rsaPrivateKey.N.Bytes())), E: "AQAB"},
+		{Kty: "EC", Crv: "P-256", Alg: "ES384", X: string(Base64Encode(ecdsaPrivateKey.X.Bytes())), Y: string(Base64Encode(ecdsaPrivateKey.Y.Bytes()))},
+		{Kty: "OKP", Crv: "Ed25519", Alg: "RS256", X: "11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"},
+	} {
+		if _, _, err = jwk.PublicKey(); !errors.Is(err, ErrJWKAlg) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, ErrJWKAlg, err)
+		}
+	}
+
+	// A registered algorithm of a builtin name does not replace the builtin one.
+	RegisterAlg(testRenamedAlg{HS256, "PS384"})
+	t.Cleanup(func() {
+		RegisterAlg(PS384)
+	})
+
+	alg, _, err := (&JWK{Kty: "RSA", Alg: "PS384", N: string(Base64Encode(rsaPrivateKey.N.Bytes())), E: "AQAB"}).PublicKey()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if alg != PS384 {
+		t.Fatalf("expected the builtin PS384 algorithm but got: %#+v", alg)
+	}
+}
+
+type testRenamedAlg struct {
+	Alg
+	name string
+}
+
+func (a testRenamedAlg) Name() string { return a.name }
+
+func TestMarshalPublicKeyEdDSAToJWK(t *testing.T) {
+	publicKey, err := ParsePublicKeyEdDSAFromJWK([]byte(testJWKPublicKeyEdDSA))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b, err := MarshalPublicKeyEdDSAToJWK(publicKey, "api")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := `{"kty":"OKP","crv":"Ed25519","kid":"api","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`; string(b) != expected {
+		t.Fatalf("expected jwk:\n%s\nbut got:\n%s", expected, b)
+	}
+
+	got, err := ParsePublicKeyEdDSAFromJWK(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(publicKey, got) {
+		t.Fatalf("expected public key to match after round trip")
+	}
+
+	set, err := MarshalJWKS(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys, err := ParseJWKS(set)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if key, ok := keys.Get("api"); !ok || !bytes.Equal(key.Public.(ed25519.PublicKey), publicKey) {
+		t.Fatalf("expected the key set to contain the public key")
+	}
+
+	if _, err = MarshalJWKS([]byte("{")); err == nil {
+		t.Fatalf("expected to fail on invalid jwk")
+	}
+}
+
+func TestThumbprintEdDSA(t *testing.T) {
+	publicKey, err := ParsePublicKeyEdDSAFromJWK([]byte(testJWKPublicKeyEdDSA))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// RFC 8037, appendix A.3.
+	expected := "kPrK_qmxVWaYVA9wwBF6Iuo3vVzz7TxHCTwXBygrS4k"
+
+	got, err := ThumbprintEdDSA(publicKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got != expected {
+		t.Fatalf("expected thumbprint: %s but got: %s", expected, got)
+	}
+
+	b, err := MarshalPublicKeyEdDSAToJWK(publicKey, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var jwk JWK
+	if err = Unmarshal(b, &jwk); err != nil {
+		t.Fatal(err)
+	}
+
+	if jwk.Kid != expected {
+		t.Fatalf("expected the thumbprint as the default kid: %s but got: %s", expected, jwk.Kid)
+	}
+
+	if _, err = ThumbprintEdDSA(publicKey[:10]); err != ErrInvalidKey {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidKey, err)
+	}
+}
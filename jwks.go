@@ -0,0 +1,357 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// JWKS represents a JSON Web Key Set (RFC 7517, section 5).
+type JWKS struct {
+	Keys []*JWK `json:"keys"`
+}
+
+// ParseJWKS decodes the JSON Web Key Set "data"
+// and returns the verification Keys, keyed by their "kid".
+// Keys without a "kid", keys which are not meant for signatures ("use" is not "sig")
+// and keys of unsupported types are skipped.
+func ParseJWKS(data []byte) (Keys, error) {
+	var set JWKS
+	if err := Unmarshal(data, &set); err != nil {
+		return nil, fmt.Errorf("jwks: %w", err)
+	}
+
+	keys := make(Keys, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Kid == "" || (jwk.Use != "" && jwk.Use != "sig") {
+			continue
+		}
+
+		alg, publicKey, err := jwk.PublicKey()
+		if err != nil {
+			if errors.Is(err, ErrJWKType) || errors.Is(err, ErrJWKCurve) {
+				continue // e.g. a newer key type, don't break the rest of the keys.
+			}
+
+			return nil, fmt.Errorf("jwks: kid: %q: %w", jwk.Kid, err)
+		}
+
+		keys.Register(alg, jwk.Kid, publicKey, nil)
+	}
+
+	return keys, nil
+}
+
+// JWKSClient fetches and caches the keys of a JSON Web Key Set URL,
+// e.g. the "https://{issuer}/.well-known/jwks.json" of an OpenID Connect provider.
+//
+// The keys are fetched on the first token verification,
+// they are refreshed after `RefreshInterval` (or the "Cache-Control: max-age" of the response,
+// at least the `MinRefreshInterval` and at most 24 hours) and when a token arrives with an unknown "kid" (at most once per `MinRefreshInterval`).
+// Concurrent refreshes result to a single request.
+// After a failed refresh, e.g. during an outage of the provider,
+// the next one is attempted after `MinRefreshInterval`, the previous keys are used meanwhile.
+//
+// Its `ValidateHeader` method completes the `HeaderValidator`
+// and its `VerifyToken` method can be used like the `Keys` one.
+//
+// Usage:
+//  client := jwt.NewJWKSClient("https://example.com/.well-known/jwks.json")
+//  var claims myClaims
+//  err := client.VerifyToken(token, &claims)
+type JWKSClient struct {
+	// URL is the JSON Web Key Set URL.
+	URL string
+	// Client is the HTTP client to fetch the keys.
+	// Defaults to a client with 10 seconds timeout.
+	Client *http.Client
+	// RefreshInterval is the duration the keys are cached for,
+	// when the response does not contain a "Cache-Control: max-age" directive.
+	// Defaults to 1 hour.
+	RefreshInterval time.Duration
+	// MinRefreshInterval is the minimum duration between two refreshes
+	// caused by tokens with an unknown "kid" or after a failed refresh.
+	// Defaults to 1 minute.
+	MinRefreshInterval time.Duration
+	// FetchTimeout is the maximum duration of a refresh.
+	// A refresh runs on a context of its own, not the one of the verification which caused it,
+	// so a canceled request does not fail the rest ones which wait for the same refresh.
+	// Defaults to 10 seconds.
+	FetchTimeout time.Duration
+
+	mu        sync.RWMutex
+	keys      Keys
+	fetchedAt time.Time
+	expiresAt time.Time
+	// the time and the error of the last refresh, successful or not.
+	attemptedAt time.Time
+	attemptErr  error
+
+	callMu sync.Mutex
+	call   *jwksCall
+}
+
+type jwksCall struct {
+	done chan struct{}
+	err  error
+}
+
+var (
+	_ HeaderValidator        = (*JWKSClient)(nil).ValidateHeader
+	_ ContextHeaderValidator = (*JWKSClient)(nil).ValidateHeaderContext
+)
+
+// NewJWKSClient returns a new JWKSClient of the given "url" with the default settings.
+func NewJWKSClient(url string) *JWKSClient {
+	return &JWKSClient{
+		URL:                url,
+		Client:             &http.Client{Timeout: 10 * time.Second},
+		RefreshInterval:    time.Hour,
+		MinRefreshInterval: time.Minute,
+		FetchTimeout:       10 * time.Second,
+	}
+}
+
+// Keys returns the cached keys, it fetches them if necessary.
+func (c *JWKSClient) Keys(ctx context.Context) (Keys, error) {
+	c.mu.RLock()
+	keys, expired := c.keys, !Clock().Before(c.expiresAt)
+	backoff, lastErr := c.attemptErr != nil && !c.canRefresh(), c.attemptErr
+	c.mu.RUnlock()
+
+	if keys == nil || expired {
+		if backoff { // the last refresh failed recently, don't fetch on every verification.
+			if keys == nil {
+				return nil, lastErr
+			}
+
+			return keys, nil
+		}
+
+		if err := c.Refresh(ctx); err != nil {
+			if keys == nil {
+				return nil, err
+			}
+			// Keep using the previous keys,
+			// the provider may be temporarily unavailable.
+			return keys, nil
+		}
+
+		c.mu.RLock()
+		keys = c.keys
+		c.mu.RUnlock()
+	}
+
+	return keys, nil
+}
+
+// Refresh fetches the keys from the `URL`.
+// If a refresh is already in progress then it waits for its result instead.
+// The given context cancels the wait, not the refresh itself, see `FetchTimeout`.
+func (c *JWKSClient) Refresh(ctx context.Context) error {
+	c.callMu.Lock()
+	call := c.call
+	if call == nil {
+		call = &jwksCall{done: make(chan struct{})}
+		c.call = call
+		go c.refresh(call)
+	}
+	c.callMu.Unlock()
+
+	select {
+	case <-call.done:
+		return call.err
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// refresh fetches the keys on a context of its own and reports the result to the "call" waiters.
+func (c *JWKSClient) refresh(call *jwksCall) {
+	timeout := c.FetchTimeout
+	if timeout <= 0 {
+		timeout = 10 * time.Second
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	call.err = c.fetch(ctx)
+	cancel()
+
+	c.callMu.Lock()
+	c.call = nil
+	c.callMu.Unlock()
+
+	close(call.done)
+}
+
+// fetch fetches the keys and records the attempt.
+func (c *JWKSClient) fetch(ctx context.Context) error {
+	keys, refreshInterval, err := c.fetchKeys(ctx)
+
+	now := Clock()
+	c.mu.Lock()
+	c.attemptedAt, c.attemptErr = now, err
+	if err == nil {
+		c.keys = keys
+		c.fetchedAt = now
+		c.expiresAt = now.Add(refreshInterval)
+	}
+	c.mu.Unlock()
+
+	return err
+}
+
+// canRefresh reports whether `MinRefreshInterval` has passed since the last refresh attempt.
+// It must be called under the read lock.
+func (c *JWKSClient) canRefresh() bool {
+	minRefreshInterval := c.MinRefreshInterval
+	if minRefreshInterval <= 0 {
+		minRefreshInterval = time.Minute
+	}
+
+	return Clock().Sub(c.attemptedAt) >= minRefreshInterval
+}
+
+func (c *JWKSClient) fetchKeys(ctx context.Context) (Keys, time.Duration, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, c.URL, nil)
+	if err != nil {
+		return nil, 0, fmt.Errorf("jwks: %w", err)
+	}
+	req.Header.Set("Accept", "application/json")
+
+	client := c.Client
+	if client == nil {
+		client = http.DefaultClient
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, 0, fmt.Errorf("jwks: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, 0, fmt.Errorf("jwks: %s: unexpected status code: %d", c.URL, resp.StatusCode)
+	}
+
+	body, err := readKey(resp.Body)
+	if err != nil {
+		return nil, 0, fmt.Errorf("jwks: %w", err)
+	}
+
+	keys, err := ParseJWKS(body)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	refreshInterval := c.RefreshInterval
+	if refreshInterval <= 0 {
+		refreshInterval = time.Hour
+	}
+	if maxAge, ok := parseMaxAge(resp.Header.Get("Cache-Control")); ok {
+		refreshInterval = maxAge
+
+		// A "max-age=0" (or a tiny one) of the provider must not refresh the keys on every verification.
+		minRefreshInterval := c.MinRefreshInterval
+		if minRefreshInterval <= 0 {
+			minRefreshInterval = time.Minute
+		}
+		if refreshInterval < minRefreshInterval {
+			refreshInterval = minRefreshInterval
+		}
+	}
+
+	return keys, refreshInterval, nil
+}
+
+// maxJWKSMaxAge caps the "max-age" of a provider's Cache-Control header,
+// so the keys are refreshed at least once a day.
+const maxJWKSMaxAge = 24 * time.Hour
+
+// parseMaxAge returns the "max-age" directive of a Cache-Control header value,
+// at most the `maxJWKSMaxAge`.
+func parseMaxAge(cacheControl string) (time.Duration, bool) {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(directive)
+		if !strings.HasPrefix(directive, "max-age=") {
+			continue
+		}
+
+		seconds, err := strconv.ParseInt(strings.Trim(directive[len("max-age="):], `"`), 10, 64)
+		if err != nil || seconds < 0 {
+			return 0, false
+		}
+
+		if seconds > int64(maxJWKSMaxAge/time.Second) { // it would overflow the time.Duration too.
+			return maxJWKSMaxAge, true
+		}
+
+		return time.Duration(seconds) * time.Second, true
+	}
+
+	return 0, false
+}
+
+// ValidateHeader validates the given json header value (base64 decoded) based on the fetched keys.
+// If the header's "kid" is unknown then the keys are refreshed,
+// at most once per `MinRefreshInterval` since the last refresh attempt.
+// JWKSClient's ValidateHeader method completes the `HeaderValidator` interface.
+func (c *JWKSClient) ValidateHeader(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+	return c.ValidateHeaderContext(context.Background(), alg, headerDecoded)
+}
+
+// ValidateHeaderContext same as `ValidateHeader` but it accepts a context,
+// so a canceled request stops waiting for the keys.
+// It completes the `ContextHeaderValidator` interface.
+func (c *JWKSClient) ValidateHeaderContext(ctx context.Context, alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+	keys, err := c.Keys(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	verifyAlg, publicKey, decrypt, err := keys.ValidateHeader(alg, headerDecoded)
+	if err != ErrUnknownKid {
+		return verifyAlg, publicKey, decrypt, err
+	}
+
+	c.mu.RLock()
+	canRefresh := c.canRefresh()
+	c.mu.RUnlock()
+
+	if !canRefresh {
+		return nil, nil, nil, ErrUnknownKid
+	}
+
+	if err = c.Refresh(ctx); err != nil {
+		return nil, nil, nil, err
+	}
+
+	keys, err = c.Keys(ctx)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	return keys.ValidateHeader(alg, headerDecoded)
+}
+
+// VerifyToken verifies the "token" based on the fetched keys
+// and sets the custom claims to the destination "claimsPtr".
+func (c *JWKSClient) VerifyToken(token []byte, claimsPtr interface{}, validators ...TokenValidator) error {
+	return c.VerifyTokenContext(context.Background(), token, claimsPtr, validators...)
+}
+
+// VerifyTokenContext same as `VerifyToken` but it accepts a context,
+// a canceled context stops waiting for the keys and it's passed to the validators too, see `VerifyContext`.
+func (c *JWKSClient) VerifyTokenContext(ctx context.Context, token []byte, claimsPtr interface{}, validators ...TokenValidator) error {
+	verifiedToken, err := VerifyWithHeaderValidatorContext(ctx, nil, nil, token, c.ValidateHeaderContext, validators...)
+	if err != nil {
+		return err
+	}
+
+	return verifiedToken.Claims(claimsPtr)
+}
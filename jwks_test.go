@@ -0,0 +1,314 @@
+package jwt
+
+import (
+	"context"
+	"crypto/ed25519"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestJWKSClient(t *testing.T) {
+	privateKey, err := ParsePrivateKeyEdDSAFromJWK([]byte(testJWKPrivateKeyEdDSA))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	rotatedPrivateKey, _, err := GenerateEdDSA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var (
+		hits    int32
+		rotated int32
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		time.Sleep(50 * time.Millisecond) // let the concurrent requests to wait for this one.
+
+		keys := `{"kty":"OKP","crv":"Ed25519","use":"sig","kid":"first","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}`
+		if atomic.LoadInt32(&rotated) == 1 {
+			x := Base64Encode(rotatedPrivateKey.Public().(ed25519.PublicKey))
+			keys += `,{"kty":"OKP","crv":"Ed25519","kid":"second","x":"` + string(x) + `"}`
+		}
+
+		w.Header().Set("Cache-Control", "public, max-age=600")
+		w.Write([]byte(`{"keys":[` + keys + `,{"kty":"oct","kid":"unsupported","k":"c2VjcmV0"}]}`))
+	}))
+	defer srv.Close()
+
+	client := NewJWKSClient(srv.URL)
+	client.MinRefreshInterval = time.Nanosecond
+
+	sign := func(kid string, key ed25519.PrivateKey) []byte {
+		token, err := SignWithHeader(EdDSA, key, Map{"username": "kataras"}, HeaderWithKid{Kid: kid, Alg: EdDSA.Name()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	token := sign("first", privateKey)
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var claims Map
+			if err := client.VerifyToken(token, &claims); err != nil {
+				t.Errorf("expected to pass but got error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Fatalf("expected a single fetch but got: %d", got)
+	}
+
+	client.mu.RLock()
+	expiresIn := client.expiresAt.Sub(client.fetchedAt)
+	client.mu.RUnlock()
+	if expected := 600 * time.Second; expiresIn != expected {
+		t.Fatalf("expected the Cache-Control max-age (%s) to be respected but got: %s", expected, expiresIn)
+	}
+
+	// Test refresh on unknown kid.
+	atomic.StoreInt32(&rotated, 1)
+	var claims Map
+	if err = client.VerifyToken(sign("second", rotatedPrivateKey), &claims); err != nil {
+		t.Fatalf("expected to pass after refresh but got error: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 2 {
+		t.Fatalf("expected a second fetch but got: %d", got)
+	}
+
+	if err = client.VerifyToken(sign("unknown", rotatedPrivateKey), &claims); err != ErrUnknownKid {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownKid, err)
+	}
+}
+
+func TestJWKSClientHTTPError(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unavailable", http.StatusServiceUnavailable)
+	}))
+	defer srv.Close()
+
+	privateKey, err := ParsePrivateKeyEdDSAFromJWK([]byte(testJWKPrivateKeyEdDSA))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := SignWithHeader(EdDSA, privateKey, Map{"username": "kataras"}, HeaderWithKid{Kid: "first", Alg: EdDSA.Name()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims Map
+	if err = NewJWKSClient(srv.URL).VerifyToken(token, &claims); err == nil {
+		t.Fatalf("expected an error on unexpected status code")
+	}
+}
+
+func TestJWKSClientBackoff(t *testing.T) {
+	var (
+		hits        int32
+		unavailable int32
+	)
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		if atomic.LoadInt32(&unavailable) == 1 {
+			http.Error(w, "unavailable", http.StatusServiceUnavailable)
+			return
+		}
+
+		w.Header().Set("Cache-Control", "max-age=60")
+		w.Write([]byte(`{"keys":[{"kty":"OKP","crv":"Ed25519","use":"sig","kid":"first","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`))
+	}))
+	defer srv.Close()
+
+	now := time.Now()
+	prevClock := Clock
+	defer func() {
+		Clock = prevClock
+	}()
+	Clock = func() time.Time {
+		return now
+	}
+
+	privateKey, err := ParsePrivateKeyEdDSAFromJWK([]byte(testJWKPrivateKeyEdDSA))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sign := func(kid string) []byte {
+		token, err := SignWithHeader(EdDSA, privateKey, Map{"username": "kataras"}, HeaderWithKid{Kid: kid, Alg: EdDSA.Name()})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+
+	expectHits := func(expected int32) {
+		t.Helper()
+		if got := atomic.LoadInt32(&hits); got != expected {
+			t.Fatalf("expected %d fetches but got: %d", expected, got)
+		}
+	}
+
+	client := NewJWKSClient(srv.URL)
+	client.MinRefreshInterval = 10 * time.Second
+
+	// The first fetch fails, the next verifications don't fetch again until MinRefreshInterval.
+	atomic.StoreInt32(&unavailable, 1)
+	var claims Map
+	for i := 0; i < 3; i++ {
+		if err = client.VerifyToken(sign("first"), &claims); err == nil {
+			t.Fatalf("expected an error on unavailable provider")
+		}
+	}
+	expectHits(1)
+
+	atomic.StoreInt32(&unavailable, 0)
+	now = now.Add(10 * time.Second)
+	if err = client.VerifyToken(sign("first"), &claims); err != nil {
+		t.Fatal(err)
+	}
+	expectHits(2)
+
+	// The keys expire during an outage: the previous keys are used and the provider is not hit on every verification.
+	atomic.StoreInt32(&unavailable, 1)
+	now = now.Add(time.Minute)
+	for i := 0; i < 3; i++ {
+		if err = client.VerifyToken(sign("first"), &claims); err != nil {
+			t.Fatalf("expected the previous keys to be used but got: %v", err)
+		}
+	}
+	expectHits(3)
+
+	// The unknown kid refreshes respect the failed attempts too.
+	now = now.Add(10 * time.Second)
+	for i := 0; i < 3; i++ {
+		if err = client.VerifyToken(sign("unknown"), &claims); err == nil {
+			t.Fatalf("expected an error on unknown kid")
+		}
+	}
+	expectHits(4)
+}
+
+func TestJWKSClientRefreshCanceled(t *testing.T) {
+	var hits int32
+	release := make(chan struct{})
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		<-release
+
+		w.Write([]byte(`{"keys":[{"kty":"OKP","crv":"Ed25519","use":"sig","kid":"first","x":"11qYAYKxCrfVS_7TyWQHOg7hcvPapiMlrwIaaPcHURo"}]}`))
+	}))
+	defer srv.Close()
+
+	client := NewJWKSClient(srv.URL)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	canceled := make(chan error, 1)
+	go func() {
+		canceled <- client.Refresh(ctx)
+	}()
+
+	for atomic.LoadInt32(&hits) == 0 { // wait for the first caller to start the refresh.
+		time.Sleep(time.Millisecond)
+	}
+
+	waiting := make(chan error, 1)
+	go func() {
+		waiting <- client.Refresh(context.Background())
+	}()
+
+	cancel()
+	if err := <-canceled; err != context.Canceled {
+		t.Fatalf("expected error: %v but got: %v", context.Canceled, err)
+	}
+
+	close(release)
+	if err := <-waiting; err != nil {
+		t.Fatalf("expected the waiting caller to get the keys but got: %v", err)
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Fatalf("expected a single fetch but got: %d", got)
+	}
+
+	keys, err := client.Keys(context.Background())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, ok := keys["first"]; !ok {
+		t.Fatalf("expected the fetched keys but got: %#+v", keys)
+	}
+}
+
+func TestParseMaxAge(t *testing.T) {
+	var tests = []struct {
+		cacheControl string
+		maxAge       time.Duration
+		ok           bool
+	}{
+		{"max-age=60", time.Minute, true},
+		{"public, max-age=3600, must-revalidate", time.Hour, true},
+		{"no-cache", 0, false},
+		{"max-age=invalid", 0, false},
+		{"", 0, false},
+		{"max-age=0", 0, true},
+		// Capped, instead of overflowing the time.Duration.
+		{"max-age=172800", maxJWKSMaxAge, true},
+		{"max-age=9223372036854775807", maxJWKSMaxAge, true},
+	}
+
+	for i, tt := range tests {
+		maxAge, ok := parseMaxAge(tt.cacheControl)
+		if maxAge != tt.maxAge || ok != tt.ok {
+			t.Fatalf("[%d] expected: %s, %v but got: %s, %v", i, tt.maxAge, tt.ok, maxAge, ok)
+		}
+	}
+}
+
+func TestJWKSClientMinMaxAge(t *testing.T) {
+	_, publicKey, err := GenerateEdDSA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var hits int32
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.Header().Set("Cache-Control", "max-age=0")
+		w.Write([]byte(`{"keys":[{"kty":"OKP","crv":"Ed25519","kid":"first","x":"` + string(Base64Encode(publicKey)) + `"}]}`))
+	}))
+	defer srv.Close()
+
+	client := NewJWKSClient(srv.URL)
+	for i := 0; i < 3; i++ {
+		if _, err = client.Keys(context.Background()); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if got := atomic.LoadInt32(&hits); got != 1 {
+		t.Fatalf("expected a single fetch but got: %d", got)
+	}
+
+	client.mu.RLock()
+	expiresIn := client.expiresAt.Sub(client.fetchedAt)
+	client.mu.RUnlock()
+	if expiresIn != client.MinRefreshInterval {
+		t.Fatalf("expected the max-age to be at least the MinRefreshInterval (%s) but got: %s", client.MinRefreshInterval, expiresIn)
+	}
+}
@@ -2,9 +2,15 @@ package jwt
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
 	"io/ioutil"
+	"os"
 	"reflect"
+	"strings"
 	"time"
 )
 
@@ -15,7 +21,9 @@ type Map = map[string]interface{}
 // It can be overridden to use any other time value, useful for testing.
 //
 // Usage: now := Clock()
-var Clock = time.Now
+//
+// See `WithClock` to set the clock of a single verification.
+var Clock ClockFunc = time.Now
 
 // CompareHeader is the function which compares and validates
 // the decoded header against the defined signature algorithm.
@@ -33,6 +41,51 @@ var CompareHeader HeaderValidator = compareHeader
 // Defaults to the `ioutil.ReadFile` which reads the file from the physical disk.
 var ReadFile = ioutil.ReadFile
 
+// ErrKeyTooLarge indicates that a key read from an io.Reader
+// exceeds the `MaxKeySize` limit.
+var ErrKeyTooLarge = errors.New("jwt: key exceeds the maximum size")
+
+// MaxKeySize is the maximum number of bytes the ParseXXXFromReader
+// key helpers read from an io.Reader, protects against never-ending or malicious sources.
+// Defaults to 1 MiB.
+var MaxKeySize int64 = 1 << 20
+
+// ErrEnvKeyNotSet indicates that the environment variable
+// of a LoadXXXFromEnv key helper is not set or it's empty.
+var ErrEnvKeyNotSet = errors.New("jwt: key environment variable is not set or empty")
+
+// readEnvKey returns the key of the "varName" environment variable.
+// The value can be a raw PEM or a base64-encoded PEM (to survive the multi-line issues).
+// It tries to base64-decode the value first and falls back to the raw value.
+func readEnvKey(varName string) ([]byte, error) {
+	value := strings.TrimSpace(os.Getenv(varName))
+	if value == "" {
+		return nil, fmt.Errorf("%w: %s", ErrEnvKeyNotSet, varName)
+	}
+
+	for _, enc := range []*base64.Encoding{base64.StdEncoding, base64.RawStdEncoding} {
+		if b, err := enc.DecodeString(value); err == nil {
+			return b, nil
+		}
+	}
+
+	return []byte(value), nil
+}
+
+// readKey reads all the contents of "r" up to `MaxKeySize`.
+func readKey(r io.Reader) ([]byte, error) {
+	b, err := ioutil.ReadAll(io.LimitReader(r, MaxKeySize+1))
+	if err != nil {
+		return nil, err
+	}
+
+	if int64(len(b)) > MaxKeySize {
+		return nil, ErrKeyTooLarge
+	}
+
+	return b, nil
+}
+
 // Marshal same as json.Marshal.
 // This variable can be modified to enable custom encoder behavior
 // for a signed payload.
@@ -70,6 +123,31 @@ func UnmarshalWithRequired(payload []byte, dest interface{}) error {
 	return meetRequirements(reflect.ValueOf(dest))
 }
 
+// unmarshalStandardClaims decodes the payload to the standard claims on `Verify`.
+// See `SetJSONCodec`.
+var unmarshalStandardClaims = json.Unmarshal
+
+// SetJSONCodec replaces the JSON encoder and decoder of the package,
+// e.g. with a faster, third-party, implementation.
+// It modifies the `Marshal` and `Unmarshal` package-level variables
+// and the standard claims decoder the `Verify` function uses.
+// Raw []byte claims are still signed as they are.
+// Should be called once, before any token is signed or verified.
+//
+// Usage:
+//  jwt.SetJSONCodec(sonic.Marshal, sonic.Unmarshal)
+func SetJSONCodec(marshal func(v interface{}) ([]byte, error), unmarshal func(data []byte, v interface{}) error) {
+	Marshal = func(v interface{}) ([]byte, error) {
+		if b, ok := v.([]byte); ok {
+			return b, nil
+		}
+
+		return marshal(v)
+	}
+	Unmarshal = unmarshal
+	unmarshalStandardClaims = unmarshal
+}
+
 func defaultUnmarshal(payload []byte, dest interface{}) error {
 	dec := json.NewDecoder(bytes.NewReader(payload))
 	dec.UseNumber() // fixes the issue of setting float64 instead of int64 on maps.
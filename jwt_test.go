@@ -0,0 +1,66 @@
+package jwt
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+// resetJSONCodec restores the default JSON codec after a SetJSONCodec call.
+func resetJSONCodec(tb testing.TB) {
+	prevMarshal, prevUnmarshal, prevUnmarshalStandardClaims := Marshal, Unmarshal, unmarshalStandardClaims
+	tb.Cleanup(func() {
+		Marshal, Unmarshal, unmarshalStandardClaims = prevMarshal, prevUnmarshal, prevUnmarshalStandardClaims
+	})
+}
+
+func TestSetJSONCodec(t *testing.T) {
+	resetJSONCodec(t)
+
+	var marshalCalls, unmarshalCalls int
+	SetJSONCodec(func(v interface{}) ([]byte, error) {
+		marshalCalls++
+		return json.Marshal(v)
+	}, func(data []byte, v interface{}) error {
+		unmarshalCalls++
+		return json.Unmarshal(data, v)
+	})
+
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims Map
+	if err = VerifyToken(testAlg, testSecret, token, &claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if claims["username"] != "kataras" {
+		t.Fatalf("expected claims to be decoded but got: %#+v", claims)
+	}
+
+	if marshalCalls == 0 {
+		t.Fatalf("expected the custom marshal to be called")
+	}
+
+	// The standard claims and the custom ones.
+	if expected := 2; unmarshalCalls != expected {
+		t.Fatalf("expected the custom unmarshal to be called %d times but got: %d", expected, unmarshalCalls)
+	}
+
+	// Raw payloads are still signed as they are.
+	token, err = Sign(testAlg, testSecret, []byte("raw"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token, Plain)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if string(verifiedToken.Payload) != "raw" {
+		t.Fatalf("expected raw payload but got: %q", verifiedToken.Payload)
+	}
+}
@@ -9,7 +9,8 @@ import (
 )
 
 var (
-	// ErrEmptyKid fires when the header is missing a "kid" field.
+	// ErrEmptyKid fires when the header is missing a "kid" field
+	// or when a key of an empty "kid" is added to a `Signer`.
 	ErrEmptyKid = errors.New("jwt: kid is empty")
 	// ErrUnknownKid fires when the header has a "kid" field
 	// but does not match with any of the registered ones.
@@ -42,15 +43,14 @@ type (
 	// User should initialize the keys once, not safe for concurrent writes.
 	// See its `SignToken`, `VerifyToken` and `ValidateHeader` methods.
 	// Usage:
-	//  var keys jwt.Keys
-	//  keys.Register("api", jwt.RS256, apiPubKey, apiPrivKey)
-	//  keys.Register("cognito", jwt.RS256, cognitoPubKey, nil)
+	//  keys := make(jwt.Keys)
+	//  keys.Register(jwt.RS256, "api", apiPubKey, apiPrivKey)
+	//  keys.Register(jwt.RS256, "cognito", cognitoPubKey, nil)
 	//  ...
 	//  token, err := keys.SignToken("api", myClaims{...}, jwt.MaxAge(15*time.Minute))
 	//  ...
 	//  var c myClaims
-	//  err := keys.VerifyToken("api", token, &myClaims)
-	//  }
+	//  err := keys.VerifyToken(token, &c)
 	Keys map[string]*Key
 
 	// KeysConfiguration for multiple keys sign and validate.
@@ -158,6 +158,29 @@ func (c KeysConfiguration) Load() (Keys, error) {
 	return parsedKeys, nil
 }
 
+// NewKeys returns a new Keys of verification-only keys which share the same "alg".
+// The "publicKeys" map's key is the key id ("kid" header field)
+// and its value the public key which verifies the tokens with that "kid".
+//
+// Useful for key rotation, where tokens signed with the
+// current and the previous keys are both valid for a period of time.
+// Tokens with a "kid" that is not part of the "publicKeys" fail with ErrUnknownKid.
+//
+// Usage:
+//  keys := jwt.NewKeys(jwt.EdDSA, map[string]jwt.PublicKey{
+//    "2021-01": previousPublicKey,
+//    "2021-02": currentPublicKey,
+//  })
+//  err := keys.VerifyToken(token, &claims)
+func NewKeys(alg Alg, publicKeys map[string]PublicKey) Keys {
+	keys := make(Keys, len(publicKeys))
+	for kid, pubKey := range publicKeys {
+		keys.Register(alg, kid, pubKey, nil)
+	}
+
+	return keys
+}
+
 // Get returns the key based on its id.
 func (keys Keys) Get(kid string) (*Key, bool) {
 	k, ok := keys[kid]
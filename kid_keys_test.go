@@ -0,0 +1,51 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"testing"
+)
+
+func TestNewKeys(t *testing.T) {
+	previousPrivateKey, previousPublicKey, err := GenerateEdDSA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	currentPrivateKey, currentPublicKey, err := GenerateEdDSA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	keys := NewKeys(EdDSA, map[string]PublicKey{
+		"previous": previousPublicKey,
+		"current":  currentPublicKey,
+	})
+
+	var tests = []struct {
+		kid        string
+		privateKey ed25519.PrivateKey
+		wantErr    error
+	}{
+		{"previous", previousPrivateKey, nil},
+		{"current", currentPrivateKey, nil},
+		{"current", previousPrivateKey, ErrTokenSignature},
+		{"unknown", currentPrivateKey, ErrUnknownKid},
+		{"", currentPrivateKey, ErrEmptyKid},
+	}
+
+	for i, tt := range tests {
+		token, err := SignWithHeader(EdDSA, tt.privateKey, Map{"username": "kataras"}, HeaderWithKid{Kid: tt.kid, Alg: EdDSA.Name()})
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var claims Map
+		if err = keys.VerifyToken(token, &claims); err != tt.wantErr {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.wantErr, err)
+		}
+
+		if tt.wantErr == nil && claims["username"] != "kataras" {
+			t.Fatalf("[%d] expected claims to be decoded but got: %#+v", i, claims)
+		}
+	}
+}
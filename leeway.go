@@ -1,16 +1,21 @@
 package jwt
 
-import "time"
+import (
+	"errors"
+	"fmt"
+	"time"
+)
 
 // Leeway adds validation for a leeway expiration time.
 // If the token was not expired then a comparison between
 // this "leeway" and the token's "exp" one is expected to pass instead (now+leeway > exp).
 // Example of use case: disallow tokens that are going to be expired in 3 seconds from now,
 // this is useful to make sure that the token is valid when the when the user fires a database call for example.
+// The current time is the one of the verification, see `WithClock`.
 func Leeway(leeway time.Duration) TokenValidatorFunc {
 	return func(_ []byte, standardClaims Claims, err error) error {
 		if err == nil {
-			if Clock().Add(leeway).Round(time.Second).Unix() > standardClaims.Expiry {
+			if standardClaims.currentTime().Add(leeway).Round(time.Second).Unix() > standardClaims.Expiry {
 				return ErrExpired
 			}
 		}
@@ -18,3 +23,93 @@ func Leeway(leeway time.Duration) TokenValidatorFunc {
 		return err
 	}
 }
+
+// ClockSkew is a TokenValidator which tolerates a "skew" difference
+// between the clocks of the token's issuer and the current machine.
+// It's applied to all of the "exp", "nbf" and "iat" claims:
+// expired tokens are accepted for "skew" duration after their expiration time
+// and not valid yet (or issued in the future) tokens are accepted for "skew" duration before that time.
+//
+// Note that, unlike `Leeway`, it does not make the expiration validation stricter but looser.
+// Pass it before any other validator, so they can see its result.
+// It returns a type of ValidationError when the token is still invalid.
+//
+// Usage:
+//  verifiedToken, err := jwt.Verify(jwt.HS256, sharedKey, token, jwt.ClockSkew(30*time.Second))
+func ClockSkew(skew time.Duration) TimeValidatorFunc {
+	return func(now time.Time, _ []byte, standardClaims Claims, err error) error {
+		switch err {
+		case ErrExpired, ErrNotValidYet, ErrIssuedInTheFuture:
+			// Validate again, with the skew this time.
+			return newTimeValidationError(validateClaimsWithSkew(now, standardClaims, skew), now, standardClaims, skew)
+		default:
+			return err
+		}
+	}
+}
+
+// AllowedSkew same as `ClockSkew` but it tolerates a different skew
+// for each one of the "exp", "nbf" and "iat" claims,
+// e.g. a generous "exp" skew, so users are not cut off abruptly,
+// and a strict "nbf" one.
+//
+// Pass it before any other validator, like the `ClockSkew`: a `ClockSkew` passed after it
+// sees its result, so the per claim skews take precedence,
+// while a `Leeway` passed after it still makes the expiration validation stricter.
+// It returns a type of ValidationError when the token is still invalid.
+//
+// Usage:
+//  verifiedToken, err := jwt.Verify(jwt.HS256, sharedKey, token, jwt.AllowedSkew(2*time.Minute, 5*time.Second, 5*time.Second))
+func AllowedSkew(expSkew, nbfSkew, iatSkew time.Duration) TimeValidatorFunc {
+	return func(now time.Time, _ []byte, standardClaims Claims, err error) error {
+		if !errors.Is(err, ErrExpired) && !errors.Is(err, ErrNotValidYet) && !errors.Is(err, ErrIssuedInTheFuture) {
+			return err
+		}
+
+		// Validate again, with the per claim skews this time.
+		err = validateClaimsWithSkews(now, standardClaims, expSkew, nbfSkew, iatSkew)
+		switch err {
+		case ErrExpired:
+			return newTimeValidationError(err, now, standardClaims, expSkew)
+		case ErrNotValidYet:
+			return newTimeValidationError(err, now, standardClaims, nbfSkew)
+		case ErrIssuedInTheFuture:
+			return newTimeValidationError(err, now, standardClaims, iatSkew)
+		default:
+			return err
+		}
+	}
+}
+
+// ErrTokenMaxAgeExceeded indicates that the token was issued ("iat" claim)
+// before the maximum age given to the `MaxTokenAge` validator,
+// or that the token has no "iat" claim at all.
+var ErrTokenMaxAgeExceeded = errors.New("jwt: token max age exceeded")
+
+// MaxTokenAge is a TokenValidator which rejects tokens
+// issued ("iat" claim) more than "maxAge" ago, whatever their "exp" claim is.
+// Useful to cap the lifetime of tokens of issuers with very long expiration times.
+// Tokens without an "iat" claim are rejected, as their age is unknown.
+// It returns an ErrTokenMaxAgeExceeded error.
+//
+// Note that it's the verifier's equivalent of the `MaxAge` SignOption.
+//
+// Usage:
+//  verifiedToken, err := jwt.Verify(jwt.EdDSA, publicKey, token, jwt.MaxTokenAge(15*time.Minute))
+func MaxTokenAge(maxAge time.Duration) TimeValidatorFunc {
+	return func(now time.Time, _ []byte, standardClaims Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		if standardClaims.IssuedAt == 0 {
+			return fmt.Errorf("%w: missing iat", ErrTokenMaxAgeExceeded)
+		}
+
+		if age := now.Unix() - standardClaims.IssuedAt; age > int64(maxAge/time.Second) {
+			return ErrTokenMaxAgeExceeded
+		}
+
+		return nil
+	}
+}
@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"errors"
 	"testing"
 	"time"
 )
@@ -20,3 +21,173 @@ func TestLeeway(t *testing.T) {
 		t.Fatalf("expected to respect previous error 'ErrInvalidKey' but got: %v", err)
 	}
 }
+
+func TestClockSkew(t *testing.T) {
+	prevClock := Clock
+	defer func() {
+		Clock = prevClock
+	}()
+
+	now := time.Date(2020, 10, 26, 1, 1, 1, 0, time.UTC)
+	Clock = func() time.Time {
+		return now
+	}
+
+	var tests = []struct {
+		claims  Claims
+		skew    time.Duration
+		wantErr error
+	}{
+		// Exactly at the boundary.
+		{Claims{Expiry: now.Unix()}, 0, nil},
+		{Claims{NotBefore: now.Unix()}, 0, nil},
+		{Claims{IssuedAt: now.Unix()}, 0, nil},
+		// A second after the boundary without skew.
+		{Claims{Expiry: now.Unix() - 1}, 0, ErrExpired},
+		{Claims{NotBefore: now.Unix() + 1}, 0, ErrNotValidYet},
+		{Claims{IssuedAt: now.Unix() + 1}, 0, ErrIssuedInTheFuture},
+		// Exactly at the boundary with skew.
+		{Claims{Expiry: now.Unix() - 30}, 30 * time.Second, nil},
+		{Claims{NotBefore: now.Unix() + 30}, 30 * time.Second, nil},
+		{Claims{IssuedAt: now.Unix() + 30}, 30 * time.Second, nil},
+		// A second after the boundary with skew.
+		{Claims{Expiry: now.Unix() - 31}, 30 * time.Second, ErrExpired},
+		{Claims{NotBefore: now.Unix() + 31}, 30 * time.Second, ErrNotValidYet},
+		{Claims{IssuedAt: now.Unix() + 31}, 30 * time.Second, ErrIssuedInTheFuture},
+		// Absent claims.
+		{Claims{}, 0, nil},
+	}
+
+	for i, tt := range tests {
+		err := ClockSkew(tt.skew).ValidateToken(nil, tt.claims, validateClaims(Clock(), tt.claims))
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.wantErr, err)
+		}
+	}
+
+	// Test through Verify.
+	token, err := Sign(HS256, testSecret, Claims{Expiry: now.Unix() - 10})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(HS256, testSecret, token); err != ErrExpired {
+		t.Fatalf("expected error: %v but got: %v", ErrExpired, err)
+	}
+
+	if _, err = Verify(HS256, testSecret, token, ClockSkew(time.Minute)); err != nil {
+		t.Fatalf("expected to pass but got error: %v", err)
+	}
+
+	// Test respect previous error
+	err = ClockSkew(time.Minute).ValidateToken(nil, Claims{}, ErrInvalidKey)
+	if err != ErrInvalidKey {
+		t.Fatalf("expected to respect previous error 'ErrInvalidKey' but got: %v", err)
+	}
+}
+
+func TestAllowedSkew(t *testing.T) {
+	prevClock := Clock
+	defer func() {
+		Clock = prevClock
+	}()
+
+	now := time.Date(2020, 10, 26, 1, 1, 1, 0, time.UTC)
+	Clock = func() time.Time {
+		return now
+	}
+
+	skew := AllowedSkew(2*time.Minute, 5*time.Second, 10*time.Second)
+
+	var tests = []struct {
+		claims     Claims
+		validators []TokenValidator
+		wantErr    error
+		wantSkew   time.Duration
+	}{
+		// Exactly at the boundary of each claim.
+		{Claims{Expiry: now.Unix() - 120}, []TokenValidator{skew}, nil, 0},
+		{Claims{NotBefore: now.Unix() + 5}, []TokenValidator{skew}, nil, 0},
+		{Claims{IssuedAt: now.Unix() + 10}, []TokenValidator{skew}, nil, 0},
+		// A second after the boundary of each claim.
+		{Claims{Expiry: now.Unix() - 121}, []TokenValidator{skew}, ErrExpired, 2 * time.Minute},
+		{Claims{NotBefore: now.Unix() + 6}, []TokenValidator{skew}, ErrNotValidYet, 5 * time.Second},
+		{Claims{IssuedAt: now.Unix() + 11}, []TokenValidator{skew}, ErrIssuedInTheFuture, 10 * time.Second},
+		// The same skew passes the "exp" check but fails the "nbf" one.
+		{Claims{Expiry: now.Unix() - 60}, []TokenValidator{skew}, nil, 0},
+		{Claims{NotBefore: now.Unix() + 60}, []TokenValidator{skew}, ErrNotValidYet, 5 * time.Second},
+		// Takes precedence over a next ClockSkew.
+		{Claims{NotBefore: now.Unix() + 60}, []TokenValidator{skew, ClockSkew(time.Minute)}, ErrNotValidYet, 5 * time.Second},
+		{Claims{Expiry: now.Unix() - 90}, []TokenValidator{skew, ClockSkew(time.Minute)}, nil, 0},
+		// A next Leeway still applies.
+		{Claims{Expiry: now.Unix() + 10}, []TokenValidator{skew, Leeway(time.Minute)}, ErrExpired, 0},
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(HS256, testSecret, tt.claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = Verify(HS256, testSecret, token, tt.validators...)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.wantErr, err)
+		}
+
+		var validationErr *ValidationError
+		if errors.As(err, &validationErr) && validationErr.Skew != tt.wantSkew {
+			t.Fatalf("[%d] expected skew: %s but got: %s", i, tt.wantSkew, validationErr.Skew)
+		}
+	}
+
+	// Test respect previous error
+	err := skew.ValidateToken(nil, Claims{}, ErrInvalidKey)
+	if err != ErrInvalidKey {
+		t.Fatalf("expected to respect previous error 'ErrInvalidKey' but got: %v", err)
+	}
+}
+
+func TestMaxTokenAge(t *testing.T) {
+	prevClock := Clock
+	defer func() {
+		Clock = prevClock
+	}()
+
+	now := time.Date(2020, 10, 26, 1, 1, 1, 0, time.UTC)
+	Clock = func() time.Time {
+		return now
+	}
+
+	maxAge := 15 * time.Minute
+
+	var tests = []struct {
+		claims  Claims
+		wantErr error
+	}{
+		// Exactly at the boundary.
+		{Claims{IssuedAt: now.Add(-maxAge).Unix()}, nil},
+		// A second after the boundary.
+		{Claims{IssuedAt: now.Add(-maxAge - time.Second).Unix()}, ErrTokenMaxAgeExceeded},
+		// Regardless of a long expiration time.
+		{Claims{IssuedAt: now.Add(-time.Hour).Unix(), Expiry: now.Add(time.Hour).Unix()}, ErrTokenMaxAgeExceeded},
+		// Missing iat.
+		{Claims{Expiry: now.Add(time.Hour).Unix()}, ErrTokenMaxAgeExceeded},
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(HS256, testSecret, tt.claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = Verify(HS256, testSecret, token, MaxTokenAge(maxAge)); !errors.Is(err, tt.wantErr) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.wantErr, err)
+		}
+	}
+
+	// Test respect previous error
+	err := MaxTokenAge(maxAge).ValidateToken(nil, Claims{IssuedAt: now.Unix()}, ErrInvalidKey)
+	if err != ErrInvalidKey {
+		t.Fatalf("expected to respect previous error 'ErrInvalidKey' but got: %v", err)
+	}
+}
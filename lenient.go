@@ -0,0 +1,76 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/base64"
+)
+
+type lenientBase64Option struct{}
+
+// ValidateToken completes the TokenValidator interface.
+// It respects the previous error.
+func (lenientBase64Option) ValidateToken(_ []byte, _ Claims, err error) error {
+	return err
+}
+
+// LenientBase64 is a TokenValidator which allows the `Verify` functions to decode
+// the token's parts produced by legacy producers, base64url-encoded with padding
+// or even standard base64-encoded, when the default base64url (without padding) decoding fails.
+// It is off by default and it should be used only during a migration,
+// as it WEAKENS THE PARSER: the same token can be written in more than one form,
+// e.g. a blocklisted token passes under a different encoding.
+//
+// The signature is verified against the parts as they were signed.
+// The token validators and the `VerifiedToken.Token` field
+// get the token re-encoded to the standard form (base64url without padding),
+// so e.g. the `ExpectType` and the `Blocklist` work as expected.
+//
+// Usage:
+//  verifiedToken, err := jwt.Verify(jwt.RS256, publicKey, legacyToken, jwt.LenientBase64)
+var LenientBase64 TokenValidator = lenientBase64Option{}
+
+// hasLenientBase64 reports whether the `LenientBase64` is part of the "validators".
+func hasLenientBase64(validators []TokenValidator) bool {
+	for _, v := range validators {
+		if _, ok := v.(lenientBase64Option); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// base64DecodeLenient decodes "src" like `Base64Decode` does,
+// if that fails it tries the padded base64url and then the standard base64 encodings.
+// Line breaks are still rejected.
+func base64DecodeLenient(src []byte) ([]byte, error) {
+	b, err := Base64Decode(src)
+	if err == nil || bytes.IndexAny(src, "\r\n") >= 0 {
+		return b, err
+	}
+
+	for _, enc := range []*base64.Encoding{base64.URLEncoding, base64.StdEncoding} {
+		buf := make([]byte, enc.DecodedLen(len(src)))
+		if n, decodeErr := enc.Decode(buf, src); decodeErr == nil {
+			return buf[:n], nil
+		}
+	}
+
+	return nil, err
+}
+
+// canonicalBase64Token re-encodes each part of the (already decoded successfully) "token"
+// to base64url without padding.
+func canonicalBase64Token(token []byte) []byte {
+	parts := bytes.Split(token, sep)
+	for i, part := range parts {
+		b, err := base64DecodeLenient(part)
+		if err != nil {
+			return token
+		}
+
+		parts[i] = Base64Encode(b)
+	}
+
+	return bytes.Join(parts, sep)
+}
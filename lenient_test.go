@@ -0,0 +1,56 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"testing"
+)
+
+func TestLenientBase64(t *testing.T) {
+	header := []byte(`{"alg":"HS256","typ":"JWT"}`)
+	payload := []byte(`{"sub":"kataras","name":"legacy>>??"}`) // the standard base64 form holds '+' and '/'.
+
+	for _, enc := range []*base64.Encoding{base64.URLEncoding, base64.StdEncoding} {
+		// A legacy producer signs the padded or standard base64 form.
+		signingInput := []byte(enc.EncodeToString(header) + "." + enc.EncodeToString(payload))
+		signature, err := testAlg.Sign(testSecret, signingInput)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		token := append(append(signingInput, '.'), enc.EncodeToString(signature)...)
+		if !bytes.ContainsAny(token, "=+/") {
+			t.Fatalf("expected a non-standard token: %s", token)
+		}
+
+		if _, err = Verify(testAlg, testSecret, token); !errors.Is(err, ErrTokenForm) {
+			t.Fatalf("expected error: %v but got: %v", ErrTokenForm, err)
+		}
+
+		verifiedToken, err := Verify(testAlg, testSecret, token, LenientBase64, ExpectType("JWT"))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(verifiedToken.Payload, payload) {
+			t.Fatalf("expected payload: %s but got: %s", payload, verifiedToken.Payload)
+		}
+
+		if verifiedToken.StandardClaims.Subject != "kataras" {
+			t.Fatalf("expected subject: kataras but got: %q", verifiedToken.StandardClaims.Subject)
+		}
+
+		expectedToken := SigningInput(header, payload)
+		expectedToken = append(append(expectedToken, '.'), Base64Encode(signature)...)
+		if !bytes.Equal(verifiedToken.Token, expectedToken) {
+			t.Fatalf("expected canonical token:\n%s\nbut got:\n%s", expectedToken, verifiedToken.Token)
+		}
+
+		// The signature is still verified against the parts as they were signed.
+		tampered := append([]byte(base64.StdEncoding.EncodeToString(header)+"."+base64.StdEncoding.EncodeToString([]byte(`{"sub":"admin"}`))+"."), enc.EncodeToString(signature)...)
+		if _, err = Verify(testAlg, testSecret, tampered, LenientBase64); !errors.Is(err, ErrTokenSignature) {
+			t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+		}
+	}
+}
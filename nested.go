@@ -0,0 +1,88 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+)
+
+// ErrNestedTooDeep indicates that a nested token holds
+// more levels than the `MaxNestedDepth` allows.
+var ErrNestedTooDeep = errors.New("jwt: nested token too deep")
+
+// MaxNestedDepth is the maximum number of tokens, the outer one included,
+// that `VerifyNested` verifies, so a token can not nest itself endlessly.
+// Defaults to 2: an outer token which wraps an inner one.
+var MaxNestedDepth = 2
+
+// VerifyNested verifies a nested token (RFC 7519, section 5.2),
+// e.g. an inner assertion signed by an identity provider and wrapped by a broker.
+// The "outer" Verifier verifies the given "token", its header must carry the "cty":"JWT" field
+// and its payload is the inner token, which is verified by the "inner" Verifier.
+// The inner token can be a nested one too, up to the `MaxNestedDepth`.
+// It returns the innermost verified token, which holds the claims.
+//
+// The outer tokens hold no claims, their default validators should not expect any.
+// A token without the "cty":"JWT" header field fails with an ErrTokenForm error.
+//
+// Usage:
+//  outer := jwt.NewVerifier(jwt.EdDSA, brokerPublicKey)
+//  inner := jwt.NewVerifier(jwt.RS256, idpPublicKey, jwt.ExpectIssuer("my-idp"))
+//  verifiedToken, err := jwt.VerifyNested(outer, inner, token)
+//  [handle error...]
+//  var claims myClaims
+//  err = verifiedToken.Claims(&claims)
+func VerifyNested(outer, inner *Verifier, token []byte) (*VerifiedToken, error) {
+	verifiedToken, err := outer.Verify(token, Plain) // the payload is a token, not a JSON.
+	if err != nil {
+		return nil, err
+	}
+
+	if !isNested(verifiedToken.Header) {
+		return nil, fmt.Errorf("%w: expected a nested token (cty: JWT)", ErrTokenForm)
+	}
+
+	for depth := 2; ; depth++ {
+		if depth > MaxNestedDepth {
+			return nil, ErrNestedTooDeep
+		}
+
+		token = verifiedToken.Payload
+
+		// Decode the header before verification only to decide whether the payload
+		// is expected to be a token too, it's checked again after the verification.
+		header, err := DecodeHeader(token)
+		if err != nil {
+			return nil, err
+		}
+
+		nested := normalizeType(header.Cty) == "jwt"
+		if nested {
+			verifiedToken, err = inner.Verify(token, Plain)
+		} else {
+			verifiedToken, err = inner.Verify(token)
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		if nested != isNested(verifiedToken.Header) {
+			return nil, ErrTokenForm
+		}
+
+		if !nested {
+			return verifiedToken, nil
+		}
+	}
+}
+
+// isNested reports whether the "header" (decoded) carries the "cty":"JWT" field.
+func isNested(header []byte) bool {
+	var h struct {
+		Cty string `json:"cty"`
+	}
+	if err := Unmarshal(header, &h); err != nil {
+		return false
+	}
+
+	return normalizeType(h.Cty) == "jwt"
+}
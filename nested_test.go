@@ -0,0 +1,65 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestVerifyNested(t *testing.T) {
+	brokerPrivateKey, brokerPublicKey, err := GenerateEdDSA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	innerToken, err := Sign(testAlg, testSecret, Claims{Issuer: "my-idp", Subject: "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	token, err := Sign(EdDSA, brokerPrivateKey, innerToken, WithHeader("cty", "JWT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	outer := NewVerifier(EdDSA, brokerPublicKey)
+	inner := NewVerifier(testAlg, testSecret, ExpectIssuer("my-idp"))
+
+	verifiedToken, err := VerifyNested(outer, inner, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var claims Claims
+	if err = verifiedToken.Claims(&claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.Subject != "kataras" {
+		t.Fatalf("expected the innermost claims but got: %#+v", claims)
+	}
+
+	// test the inner validators are applied.
+	if _, err = VerifyNested(outer, NewVerifier(testAlg, testSecret, ExpectIssuer("other")), token); !errors.Is(err, ErrInvalidIssuer) {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidIssuer, err)
+	}
+
+	// test the inner signature is verified.
+	if _, err = VerifyNested(outer, NewVerifier(testAlg, []byte("other")), token); err != ErrTokenSignature {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+
+	// test a not nested token fails.
+	if _, err = VerifyNested(inner, inner, innerToken); !errors.Is(err, ErrTokenForm) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenForm, err)
+	}
+
+	// test the max depth.
+	deepToken, err := Sign(EdDSA, brokerPrivateKey, token, WithHeader("cty", "JWT"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyNested(outer, outer, deepToken); err != ErrNestedTooDeep {
+		t.Fatalf("expected error: %v but got: %v", ErrNestedTooDeep, err)
+	}
+}
@@ -0,0 +1,193 @@
+package jwt
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// ErrTokenReplayed indicates that a one-time token was already used,
+// see `OneTimeUse`.
+var ErrTokenReplayed = errors.New("jwt: token replayed")
+
+// NonceStore keeps the "jti" claims of the tokens used already,
+// so the `OneTimeUse` TokenValidator can reject a second use of the same token.
+//
+// The end-developer is free to design a custom storage (e.g. redis SET NX),
+// the `MemoryNonceStore` is the builtin in-memory one.
+type NonceStore interface {
+	// Consume marks the "jti" as used, until its "expiry" time.
+	// It reports whether it's the first use of the "jti".
+	// It MUST be atomic: between concurrent calls of the same "jti"
+	// only one of them may report a first use.
+	Consume(jti string, expiry time.Time) (firstUse bool, err error)
+}
+
+type oneTimeUseOption struct {
+	store NonceStore
+}
+
+// ValidateToken completes the TokenValidator interface.
+// It respects the previous error, the store is consumed after all validators passed.
+func (oneTimeUseOption) ValidateToken(_ []byte, _ Claims, err error) error {
+	return err
+}
+
+// OneTimeUse is a TokenValidator which allows each token to be used at most once,
+// e.g. a magic-link login token. On the first successful verification
+// the token's "jti" is consumed from the "store", a second verification
+// of the same token fails with ErrTokenReplayed.
+// The store is consumed after the signature, the claims and every other validator passed,
+// so a rejected token is never consumed.
+// Tokens without a "jti" claim fail with an ErrMissingKey error,
+// see the `WithRandomJTI` sign option.
+//
+// Usage:
+//  store := jwt.NewMemoryNonceStore(15*time.Minute, 30*time.Second)
+//  verifiedToken, err := jwt.Verify(jwt.EdDSA, publicKey, token, jwt.ClockSkew(30*time.Second), jwt.OneTimeUse(store))
+//  // errors.Is(err, jwt.ErrTokenReplayed) on the second use.
+func OneTimeUse(store NonceStore) TokenValidator {
+	return oneTimeUseOption{store: store}
+}
+
+// consumeOneTimeUse consumes the token's "jti" claim from the
+// stores of the `OneTimeUse` validators of the "validators".
+func consumeOneTimeUse(c Claims, validators []TokenValidator) error {
+	for _, v := range validators {
+		opt, ok := v.(oneTimeUseOption)
+		if !ok || opt.store == nil {
+			continue
+		}
+
+		if c.ID == "" {
+			return fmt.Errorf("%w: jti", ErrMissingKey)
+		}
+
+		var expiry time.Time
+		if c.Expiry > 0 {
+			expiry = time.Unix(c.Expiry, 0)
+		}
+
+		firstUse, err := opt.store.Consume(c.ID, expiry)
+		if err != nil {
+			return err
+		}
+
+		if !firstUse {
+			return ErrTokenReplayed
+		}
+	}
+
+	return nil
+}
+
+// MemoryNonceStore is the builtin in-memory `NonceStore`.
+// The consumed ids are removed by its GC after their expiration time plus the `Retention`,
+// the tokens are no longer valid by then anyway.
+// It's safe for concurrent use by multiple goroutines.
+type MemoryNonceStore struct {
+	Clock func() time.Time
+	// Retention keeps the consumed ids for that long after their expiration time.
+	// It should be at least the largest window the verifications accept expired tokens for,
+	// e.g. the `ClockSkew`, the "exp" skew of the `AllowedSkew`
+	// or the "maxExpiredFor" of the `VerifyAllowExpired`,
+	// otherwise an expired but still accepted token could be replayed after the GC.
+	Retention time.Duration
+
+	entries map[string]int64 // key = jti | value = expiration unix seconds (to remove expired).
+	mu      sync.Mutex
+}
+
+var _ NonceStore = (*MemoryNonceStore)(nil)
+
+// NewMemoryNonceStore returns a new up and running in-memory NonceStore.
+// It accepts the clear every "x" duration, e.g. the tokens max age,
+// and the "retention" of the consumed ids after their expiration time,
+// e.g. the `ClockSkew` of the verification, see the `MemoryNonceStore.Retention` field.
+func NewMemoryNonceStore(gcEvery, retention time.Duration) *MemoryNonceStore {
+	return NewMemoryNonceStoreContext(context.Background(), gcEvery, retention)
+}
+
+// NewMemoryNonceStoreContext same as `NewMemoryNonceStore`
+// but it also accepts a standard Go Context for GC cancelation.
+func NewMemoryNonceStoreContext(ctx context.Context, gcEvery, retention time.Duration) *MemoryNonceStore {
+	s := &MemoryNonceStore{
+		entries:   make(map[string]int64),
+		Clock:     Clock,
+		Retention: retention,
+	}
+
+	if gcEvery > 0 {
+		go s.runGC(ctx, gcEvery)
+	}
+
+	return s
+}
+
+// Consume completes the `NonceStore` interface.
+// The "jti" is kept until the "expiry" plus the `Retention`,
+// a zero "expiry" keeps it until the store is dropped.
+func (s *MemoryNonceStore) Consume(jti string, expiry time.Time) (bool, error) {
+	if jti == "" {
+		return false, ErrMissing
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if _, used := s.entries[jti]; used {
+		return false, nil
+	}
+
+	if expiry.IsZero() {
+		s.entries[jti] = 0
+		return true, nil
+	}
+
+	s.entries[jti] = NumericDate(expiry.Add(s.Retention))
+	return true, nil
+}
+
+// Count returns the total amount of consumed ids.
+func (s *MemoryNonceStore) Count() int {
+	s.mu.Lock()
+	n := len(s.entries)
+	s.mu.Unlock()
+
+	return n
+}
+
+// GC iterates over all entries and removes the ones expired more than the `Retention` ago.
+// Entries without an expiration time are kept.
+// It returns the number of the removed entries.
+func (s *MemoryNonceStore) GC() int {
+	now := s.Clock().Round(time.Second).Unix()
+	n := 0
+
+	s.mu.Lock()
+	for jti, expiry := range s.entries {
+		if expiry > 0 && now > expiry {
+			delete(s.entries, jti)
+			n++
+		}
+	}
+	s.mu.Unlock()
+
+	return n
+}
+
+func (s *MemoryNonceStore) runGC(ctx context.Context, every time.Duration) {
+	t := time.NewTicker(every)
+
+	for {
+		select {
+		case <-ctx.Done():
+			t.Stop()
+			return
+		case <-t.C:
+			s.GC()
+		}
+	}
+}
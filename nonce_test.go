@@ -0,0 +1,139 @@
+package jwt
+
+import (
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestOneTimeUse(t *testing.T) {
+	store := NewMemoryNonceStore(0, 0)
+
+	token, err := Sign(testAlg, testSecret, Map{"iss": "my-app"}, WithRandomJTI(nil), MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// a rejected token is not consumed.
+	if _, err = Verify(testAlg, testSecret, token, OneTimeUse(store), ExpectIssuer("other-app")); !errors.Is(err, ErrInvalidIssuer) {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidIssuer, err)
+	}
+
+	if n := store.Count(); n != 0 {
+		t.Fatalf("expected no consumed ids but got: %d", n)
+	}
+
+	const concurrent = 32
+	var (
+		wg               sync.WaitGroup
+		succeed, replays int32
+	)
+
+	for i := 0; i < concurrent; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			_, err := Verify(testAlg, testSecret, token, OneTimeUse(store))
+			switch err {
+			case nil:
+				atomic.AddInt32(&succeed, 1)
+			case ErrTokenReplayed:
+				atomic.AddInt32(&replays, 1)
+			default:
+				t.Errorf("unexpected error: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if succeed != 1 || replays != concurrent-1 {
+		t.Fatalf("expected a single use and %d replays but got: %d uses and %d replays", concurrent-1, succeed, replays)
+	}
+
+	// a token without a "jti".
+	token, err = Sign(testAlg, testSecret, Map{"iss": "my-app"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, OneTimeUse(store)); !errors.Is(err, ErrMissingKey) {
+		t.Fatalf("expected error: %v but got: %v", ErrMissingKey, err)
+	}
+}
+
+func TestMemoryNonceStoreGC(t *testing.T) {
+	store := NewMemoryNonceStore(0, 0)
+
+	now := time.Now()
+	store.Clock = func() time.Time { return now }
+
+	for _, entry := range []struct {
+		jti    string
+		expiry time.Time
+	}{
+		{"expired", now.Add(-time.Minute)},
+		{"valid", now.Add(time.Minute)},
+		{"forever", time.Time{}},
+	} {
+		if firstUse, err := store.Consume(entry.jti, entry.expiry); err != nil || !firstUse {
+			t.Fatalf("%s: expected a first use but got: %v (%v)", entry.jti, firstUse, err)
+		}
+	}
+
+	if firstUse, _ := store.Consume("valid", now.Add(time.Minute)); firstUse {
+		t.Fatalf("expected a second use")
+	}
+
+	if n := store.GC(); n != 1 {
+		t.Fatalf("expected GC to remove 1 entry but removed: %d", n)
+	}
+
+	if n := store.Count(); n != 2 {
+		t.Fatalf("expected 2 entries but got: %d", n)
+	}
+
+	if _, err := store.Consume("", now); err != ErrMissing {
+		t.Fatalf("expected error: %v but got: %v", ErrMissing, err)
+	}
+}
+
+func TestOneTimeUseReplayAfterGC(t *testing.T) {
+	const skew = time.Minute
+	store := NewMemoryNonceStore(0, skew)
+
+	now := time.Now()
+	store.Clock = func() time.Time { return now }
+	clock := WithClock(FixedClock(now))
+
+	// Expired, but still accepted because of the clock skew.
+	token, err := Sign(testAlg, testSecret, Claims{ID: "jti", Expiry: now.Add(-10 * time.Second).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, ClockSkew(skew), clock, OneTimeUse(store)); err != nil {
+		t.Fatal(err)
+	}
+
+	// The id is kept for the skew, so the GC can not open a replay window.
+	if n := store.GC(); n != 0 {
+		t.Fatalf("expected GC to keep the consumed id but removed: %d", n)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, ClockSkew(skew), clock, OneTimeUse(store)); err != ErrTokenReplayed {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenReplayed, err)
+	}
+
+	// Once the token is no longer accepted, its id is removed.
+	now = now.Add(skew)
+	if n := store.GC(); n != 1 {
+		t.Fatalf("expected GC to remove 1 entry but removed: %d", n)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, ClockSkew(skew), WithClock(FixedClock(now)), OneTimeUse(store)); !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected error: %v but got: %v", ErrExpired, err)
+	}
+}
@@ -1,8 +1,59 @@
 package jwt
 
-import "testing"
+import (
+	"errors"
+	"testing"
+)
 
 func TestEncodeDecodeTokenNONE(t *testing.T) {
 	expectedToken := []byte("eyJhbGciOiJOT05FIiwidHlwIjoiSldUIn0.eyJ1c2VybmFtZSI6ImthdGFyYXMifQ.")
 	testEncodeDecodeToken(t, NONE, nil, nil, expectedToken)
 }
+
+func TestVerifyNoneAlgorithm(t *testing.T) {
+	privateKey, publicKey, err := GenerateEdDSA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := Base64Encode([]byte(`{"username":"kataras"}`))
+	for _, header := range []string{
+		`{"alg":"none","typ":"JWT"}`,
+		`{"alg":"NONE","typ":"JWT"}`,
+		`{"typ":"JWT","alg":"none"}`,
+		`{"alg":"None"}`,
+	} {
+		token := joinParts(Base64Encode([]byte(header)), payload, []byte{})
+		if _, err = Verify(EdDSA, publicKey, token); err != ErrNoneAlgorithm {
+			t.Fatalf("[%s] expected error: %v but got: %v", header, ErrNoneAlgorithm, err)
+		}
+
+		if !errors.Is(err, ErrTokenAlg) {
+			t.Fatalf("[%s] expected error to be an ErrTokenAlg too", header)
+		}
+	}
+
+	// Test the header validator which resolves the algorithm from the token.
+	keys := make(Keys)
+	keys.Register(NONE, "unsecured", nil, nil)
+	keys.Register(EdDSA, "api", publicKey, privateKey)
+
+	token, err := SignWithHeader(NONE, nil, Map{"username": "kataras"}, HeaderWithKid{Kid: "unsecured", Alg: NONE.Name()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyWithHeaderValidator(EdDSA, publicKey, token, keys.ValidateHeader); err != ErrNoneAlgorithm {
+		t.Fatalf("expected error: %v but got: %v", ErrNoneAlgorithm, err)
+	}
+
+	// Test explicit usage of the NONE algorithm.
+	token, err = Sign(NONE, nil, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(NONE, nil, token); err != nil {
+		t.Fatalf("expected unsecured token to pass when NONE is given but got: %v", err)
+	}
+}
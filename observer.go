@@ -0,0 +1,94 @@
+package jwt
+
+// Observer receives the outcomes of the `Sign` and `Verify` calls,
+// e.g. to increment Prometheus counters or emit traces in a central place.
+// The "alg" is the name of the token's algorithm, e.g. "EdDSA".
+// The methods should not block, they are called synchronously.
+// See `WithObserver`.
+type Observer interface {
+	// OnSign is called after a token was signed.
+	OnSign(alg string)
+	// OnVerifySuccess is called after a token was verified and validated.
+	OnVerifySuccess(alg string)
+	// OnVerifyFailure is called with the error the verification returns,
+	// e.g. ErrExpired, ErrTokenSignature or ErrUnknownKid.
+	// The "alg" may be empty if the token is malformed.
+	OnVerifyFailure(alg string, err error)
+}
+
+type observerOption struct {
+	Observer
+}
+
+// ValidateToken completes the TokenValidator interface.
+// It respects the previous error.
+func (observerOption) ValidateToken(_ []byte, _ Claims, err error) error {
+	return err
+}
+
+// ApplyClaims completes the SignOption interface. It does nothing.
+func (observerOption) ApplyClaims(*Claims) {}
+
+// ObserverOption is both a SignOption and a TokenValidator, see `WithObserver`.
+type ObserverOption interface {
+	SignOption
+	TokenValidator
+}
+
+// WithObserver returns an option which reports the outcome of a `Sign` (as a SignOption)
+// or a `Verify` (as a TokenValidator) call to the given Observer.
+// It does not modify the returned token or error.
+// The verification failures are reported even if they happen before the validators run,
+// e.g. on a malformed token.
+//
+// Usage:
+//  observer := jwt.WithObserver(myMetrics)
+//  token, err := jwt.Sign(jwt.EdDSA, privateKey, claims, observer)
+//  verifiedToken, err := jwt.Verify(jwt.EdDSA, publicKey, token, observer)
+func WithObserver(o Observer) ObserverOption {
+	return observerOption{o}
+}
+
+// findObserver returns the Observer of the last `WithObserver` option, if any.
+func findObserver(validators []TokenValidator) Observer {
+	for i := len(validators) - 1; i >= 0; i-- {
+		if o, ok := validators[i].(observerOption); ok && o.Observer != nil {
+			return o.Observer
+		}
+	}
+
+	return nil
+}
+
+// verifyAlgName returns the name of the algorithm of a verification of the "token".
+// When it's resolved by the header validator, the (unverified) "alg" header
+// is reported only if it's a registered algorithm, see `AlgByName`,
+// so that arbitrary header values never reach the metrics labels or the logs.
+func verifyAlgName(alg Alg, token []byte) string {
+	if alg != nil {
+		return alg.Name()
+	}
+
+	h, err := DecodeHeader(trimToken(token))
+	if err != nil {
+		return ""
+	}
+
+	if _, ok := AlgByName(h.Alg); !ok {
+		return ""
+	}
+
+	return h.Alg
+}
+
+// observeVerify reports the outcome of a verification of the "token".
+func observeVerify(o Observer, alg Alg, token []byte, err error) {
+	algName := verifyAlgName(alg, token)
+
+	if err != nil {
+		o.OnVerifyFailure(algName, err)
+		return
+	}
+
+	o.OnVerifySuccess(algName)
+}
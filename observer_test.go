@@ -0,0 +1,113 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+type recordingObserver struct {
+	mu     sync.Mutex
+	events []string
+}
+
+func (o *recordingObserver) record(format string, args ...interface{}) {
+	o.mu.Lock()
+	o.events = append(o.events, fmt.Sprintf(format, args...))
+	o.mu.Unlock()
+}
+
+func (o *recordingObserver) OnSign(alg string) {
+	o.record("sign:%s", alg)
+}
+
+func (o *recordingObserver) OnVerifySuccess(alg string) {
+	o.record("success:%s", alg)
+}
+
+func (o *recordingObserver) OnVerifyFailure(alg string, err error) {
+	o.record("failure:%s:%v", alg, err)
+}
+
+func TestWithObserver(t *testing.T) {
+	o := new(recordingObserver)
+	observer := WithObserver(o)
+
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, observer, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expired, err := Sign(testAlg, testSecret, Claims{Expiry: Clock().Add(-time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, observer); err != nil {
+		t.Fatal(err)
+	}
+
+	// The observer does not change the returned error.
+	if _, err = Verify(testAlg, testSecret, expired, observer); err != ErrExpired {
+		t.Fatalf("expected error: %v but got: %v", ErrExpired, err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, ExpectIssuer("other"), observer); !errors.Is(err, ErrInvalidIssuer) {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidIssuer, err)
+	}
+
+	if _, err = Verify(testAlg, []byte("other"), token, observer); err != ErrTokenSignature {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+
+	// Before the validators run.
+	if _, err = Verify(testAlg, testSecret, []byte("malformed"), observer); err != ErrMalformedToken {
+		t.Fatalf("expected error: %v but got: %v", ErrMalformedToken, err)
+	}
+
+	// The algorithm resolved from the header.
+	if _, err = VerifyWithHeaderValidator(nil, testSecret, token, AllowedAlgorithms(testAlg), observer); err != nil {
+		t.Fatal(err)
+	}
+
+	name := testAlg.Name()
+	expected := []string{
+		"sign:" + name,
+		"success:" + name,
+		"failure:" + name + ":" + ErrExpired.Error(),
+		"failure:" + name + ":" + fmt.Sprint(ExpectIssuer("other").ValidateToken(nil, Claims{}, nil)),
+		"failure:" + name + ":" + ErrTokenSignature.Error(),
+		"failure:" + name + ":" + ErrMalformedToken.Error(),
+		"success:" + name,
+	}
+
+	if !reflect.DeepEqual(o.events, expected) {
+		t.Fatalf("expected events:\n%q\nbut got:\n%q", expected, o.events)
+	}
+
+	// Without an observer nothing is recorded.
+	o.events = nil
+	if _, err = Verify(testAlg, testSecret, token); err != nil {
+		t.Fatal(err)
+	}
+
+	if len(o.events) != 0 {
+		t.Fatalf("expected no events but got: %q", o.events)
+	}
+
+	// An unknown "alg" header is never reported, it's not trusted.
+	unknown := append([]byte(base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"user-controlled"}`))), bytes.SplitN(token, sep, 2)[1]...)
+	if _, err = VerifyWithHeaderValidator(nil, testSecret, unknown, AllowedAlgorithms(testAlg), observer); err == nil {
+		t.Fatal("expected an error")
+	}
+
+	if len(o.events) != 1 || !strings.HasPrefix(o.events[0], "failure::") {
+		t.Fatalf("expected a failure event without an algorithm but got: %q", o.events)
+	}
+}
@@ -0,0 +1,204 @@
+package jwt
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/asn1"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"hash"
+	"strings"
+
+	"golang.org/x/crypto/pbkdf2"
+)
+
+// ErrEncryptedKeyNoPassword indicates that a PEM-encoded private key
+// is protected by a password but no password was given.
+// Callers can check for that error to ask for one.
+var ErrEncryptedKeyNoPassword = errors.New("jwt: private key is encrypted but no password was given")
+
+var (
+	oidPBES2          = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 13}
+	oidPBKDF2         = asn1.ObjectIdentifier{1, 2, 840, 113549, 1, 5, 12}
+	oidHMACWithSHA1   = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 7}
+	oidHMACWithSHA256 = asn1.ObjectIdentifier{1, 2, 840, 113549, 2, 9}
+	oidAES128CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 2}
+	oidAES192CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 22}
+	oidAES256CBC      = asn1.ObjectIdentifier{2, 16, 840, 1, 101, 3, 4, 1, 42}
+)
+
+type (
+	// RFC 5958, section 3.
+	encryptedPrivateKeyInfo struct {
+		Algorithm     pkixAlgorithmIdentifier
+		EncryptedData []byte
+	}
+
+	pkixAlgorithmIdentifier struct {
+		Algorithm  asn1.ObjectIdentifier
+		Parameters asn1.RawValue `asn1:"optional"`
+	}
+
+	// RFC 8018, appendix A.4.
+	pbes2Params struct {
+		KeyDerivationFunc pkixAlgorithmIdentifier
+		EncryptionScheme  pkixAlgorithmIdentifier
+	}
+
+	// RFC 8018, appendix A.2.
+	pbkdf2Params struct {
+		Salt           []byte
+		IterationCount int
+		KeyLength      int                     `asn1:"optional"`
+		PRF            pkixAlgorithmIdentifier `asn1:"optional"`
+	}
+)
+
+// decodePEMBlock returns the first PEM block of "data" which its type ends with the given "typ",
+// e.g. "PRIVATE KEY" matches the "PRIVATE KEY", "ENCRYPTED PRIVATE KEY" and "EC PRIVATE KEY" blocks.
+// The rest of the blocks, e.g. certificates of a chain, are skipped.
+//
+// It returns an errPEMMalformed error if "data" contains no PEM block at all
+// and an ErrPEMBlockNotFound error if no block of the expected type is present.
+func decodePEMBlock(data []byte, typ string) (*pem.Block, error) {
+	var found []string
+
+	for {
+		block, rest := pem.Decode(data)
+		if block == nil {
+			break
+		}
+
+		if strings.HasSuffix(block.Type, typ) {
+			return block, nil
+		}
+
+		found = append(found, block.Type)
+		data = rest
+	}
+
+	if len(found) == 0 {
+		return nil, errPEMMalformed
+	}
+
+	return nil, fmt.Errorf("%w: expected a %q block but got: %q", ErrPEMBlockNotFound, typ, found)
+}
+
+// ErrPEMBlockNotFound indicates that the PEM data contains blocks
+// (e.g. certificates) but not the key block the parser expects.
+var ErrPEMBlockNotFound = errors.New("jwt: pem: key block not found")
+
+// decryptPEMBlock returns the DER bytes of the PEM "block".
+// Supports the legacy (RFC 1423, "DEK-Info" header) and the
+// PKCS #8 ("ENCRYPTED PRIVATE KEY", PBES2 with PBKDF2 and AES-CBC) encrypted blocks.
+// If the block is not encrypted then its bytes are returned as they are.
+func decryptPEMBlock(block *pem.Block, password []byte) ([]byte, error) {
+	encrypted := block.Type == "ENCRYPTED PRIVATE KEY"
+	legacy := !encrypted && x509.IsEncryptedPEMBlock(block) // RFC 1423 is insecure by design but such keys are still out there.
+	if !encrypted && !legacy {
+		return block.Bytes, nil
+	}
+
+	if len(password) == 0 {
+		return nil, ErrEncryptedKeyNoPassword
+	}
+
+	if legacy {
+		return x509.DecryptPEMBlock(block, password)
+	}
+
+	return decryptPKCS8(block.Bytes, password)
+}
+
+// maxPBKDF2Iterations is the maximum iteration count of the key derivation
+// of an encrypted private key, far above the ones the tools generate (e.g. 2048 of OpenSSL).
+const maxPBKDF2Iterations = 10000000
+
+func decryptPKCS8(der, password []byte) ([]byte, error) {
+	var info encryptedPrivateKeyInfo
+	if _, err := asn1.Unmarshal(der, &info); err != nil {
+		return nil, fmt.Errorf("private key: encrypted: %w", err)
+	}
+
+	if !info.Algorithm.Algorithm.Equal(oidPBES2) {
+		return nil, fmt.Errorf("private key: encrypted: unsupported algorithm: %s", info.Algorithm.Algorithm)
+	}
+
+	var params pbes2Params
+	if _, err := asn1.Unmarshal(info.Algorithm.Parameters.FullBytes, &params); err != nil {
+		return nil, fmt.Errorf("private key: encrypted: pbes2: %w", err)
+	}
+
+	if !params.KeyDerivationFunc.Algorithm.Equal(oidPBKDF2) {
+		return nil, fmt.Errorf("private key: encrypted: unsupported key derivation function: %s", params.KeyDerivationFunc.Algorithm)
+	}
+
+	var kdfParams pbkdf2Params
+	if _, err := asn1.Unmarshal(params.KeyDerivationFunc.Parameters.FullBytes, &kdfParams); err != nil {
+		return nil, fmt.Errorf("private key: encrypted: pbkdf2: %w", err)
+	}
+
+	var prf func() hash.Hash
+	switch prfOID := kdfParams.PRF.Algorithm; {
+	case len(prfOID) == 0, prfOID.Equal(oidHMACWithSHA1):
+		prf = sha1.New
+	case prfOID.Equal(oidHMACWithSHA256):
+		prf = sha256.New
+	default:
+		return nil, fmt.Errorf("private key: encrypted: unsupported pseudorandom function: %s", prfOID)
+	}
+
+	var keyLen int
+	switch encOID := params.EncryptionScheme.Algorithm; {
+	case encOID.Equal(oidAES128CBC):
+		keyLen = 16
+	case encOID.Equal(oidAES192CBC):
+		keyLen = 24
+	case encOID.Equal(oidAES256CBC):
+		keyLen = 32
+	default:
+		return nil, fmt.Errorf("private key: encrypted: unsupported encryption scheme: %s", encOID)
+	}
+
+	var iv []byte
+	if _, err := asn1.Unmarshal(params.EncryptionScheme.Parameters.FullBytes, &iv); err != nil {
+		return nil, fmt.Errorf("private key: encrypted: iv: %w", err)
+	}
+
+	// The iteration count comes from the key file, bound the work a crafted one can cause.
+	if kdfParams.IterationCount <= 0 || kdfParams.IterationCount > maxPBKDF2Iterations {
+		return nil, fmt.Errorf("private key: encrypted: pbkdf2: iteration count out of range (1-%d): %d", maxPBKDF2Iterations, kdfParams.IterationCount)
+	}
+
+	key := pbkdf2.Key(password, kdfParams.Salt, kdfParams.IterationCount, keyLen, prf)
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data := info.EncryptedData
+	if len(iv) != block.BlockSize() || len(data) == 0 || len(data)%block.BlockSize() != 0 {
+		return nil, fmt.Errorf("private key: encrypted: malformed data")
+	}
+
+	plain := make([]byte, len(data))
+	cipher.NewCBCDecrypter(block, iv).CryptBlocks(plain, data)
+
+	// Remove the PKCS #7 padding,
+	// a wrong password results to an invalid one (most of the times).
+	n := int(plain[len(plain)-1])
+	if n == 0 || n > block.BlockSize() {
+		return nil, x509.IncorrectPasswordError
+	}
+	for _, b := range plain[len(plain)-n:] {
+		if int(b) != n {
+			return nil, x509.IncorrectPasswordError
+		}
+	}
+
+	return plain[:len(plain)-n], nil
+}
@@ -0,0 +1,55 @@
+package jwt
+
+import "fmt"
+
+type claimPredicate func(claims map[string]interface{}) error
+
+// ValidateToken completes the TokenValidator interface.
+// It respects the previous error, the predicate itself
+// is called after all the validators, see `WithClaimPredicate`.
+func (claimPredicate) ValidateToken(_ []byte, _ Claims, err error) error {
+	return err
+}
+
+// WithClaimPredicate is a TokenValidator which calls the given "predicate" with all the claims
+// of the token, after the signature, the standard claims and the rest of the validators
+// passed, so one-off rules can be checked, e.g. "tenant_id must equal X".
+// A non-nil error of the "predicate" fails the verification, it is wrapped,
+// so errors.Is and errors.As can still reach it (and its sentinels, e.g. ErrExpected).
+// Tokens with a non-JSON payload fail.
+//
+// Usage:
+//  verifiedToken, err := jwt.Verify(jwt.EdDSA, publicKey, token, jwt.WithClaimPredicate(func(claims map[string]interface{}) error {
+//    if claims["tenant_id"] != tenantID {
+//      return jwt.ErrExpected
+//    }
+//    return nil
+//  }))
+func WithClaimPredicate(predicate func(claims map[string]interface{}) error) TokenValidator {
+	return claimPredicate(predicate)
+}
+
+// validateClaimPredicates calls the `WithClaimPredicate` predicates
+// of the "validators" with the decoded "payload".
+func validateClaimPredicates(payload []byte, validators []TokenValidator) error {
+	var claims map[string]interface{}
+
+	for _, v := range validators {
+		predicate, ok := v.(claimPredicate)
+		if !ok || predicate == nil {
+			continue
+		}
+
+		if claims == nil {
+			if err := Unmarshal(payload, &claims); err != nil {
+				return err
+			}
+		}
+
+		if err := predicate(claims); err != nil {
+			return fmt.Errorf("jwt: claim predicate: %w", err)
+		}
+	}
+
+	return nil
+}
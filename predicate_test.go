@@ -0,0 +1,66 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestWithClaimPredicate(t *testing.T) {
+	errTenant := errors.New("tenant mismatch")
+	tenant := func(tenantID string) TokenValidator {
+		return WithClaimPredicate(func(claims map[string]interface{}) error {
+			if claims["tenant_id"] != tenantID {
+				return errTenant
+			}
+
+			return nil
+		})
+	}
+
+	token, err := Sign(testAlg, testSecret, Map{"tenant_id": "tenant-1", "plan": "pro"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, tenant("tenant-1")); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, tenant("tenant-2")); !errors.Is(err, errTenant) {
+		t.Fatalf("expected error: %v but got: %v", errTenant, err)
+	}
+
+	// test the error of the predicate reaches the sentinels.
+	plan := WithClaimPredicate(func(claims map[string]interface{}) error {
+		if plan := claims["plan"]; plan != "enterprise" {
+			return &ValidationError{Err: ErrExpected, Claim: "plan", Expected: "enterprise", Actual: plan.(string)}
+		}
+
+		return nil
+	})
+
+	_, err = Verify(testAlg, testSecret, token, tenant("tenant-1"), plan)
+	var vErr *ValidationError
+	if !errors.Is(err, ErrExpected) || !errors.As(err, &vErr) || vErr.Claim != "plan" {
+		t.Fatalf("expected a ValidationError which wraps ErrExpected but got: %v", err)
+	}
+
+	// test the predicate is not called when the token is already invalid.
+	called := false
+	expired, err := Sign(testAlg, testSecret, Map{"tenant_id": "tenant-1"}, Claims{Expiry: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, expired, WithClaimPredicate(func(map[string]interface{}) error {
+		called = true
+		return nil
+	})); err != ErrExpired {
+		t.Fatalf("expected error: %v but got: %v", ErrExpired, err)
+	}
+
+	if called {
+		t.Fatalf("expected the predicate not to be called on an expired token")
+	}
+}
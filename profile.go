@@ -0,0 +1,29 @@
+package jwt
+
+// Profile is a reusable set of token validators,
+// e.g. the clock skew, issuer and audience checks a service applies everywhere,
+// built once and spread into every verification, so a call site can not forget one of them.
+// A Profile is a TokenValidator slice, it can be passed as the
+// default validators of a `Verifier` too.
+//
+// Usage:
+//  profile := jwt.NewProfile(jwt.ClockSkew(30*time.Second), jwt.ExpectIssuer("my-idp"), jwt.ExpectAudience("my-service"))
+//  err := jwt.VerifyToken(jwt.EdDSA, publicKey, token, &claims, profile...)
+//  [...]
+//  verifier := jwt.NewVerifier(jwt.EdDSA, publicKey, profile.With(jwt.ExpectType("at+jwt"))...)
+type Profile []TokenValidator
+
+// NewProfile returns a new Profile of the given validators,
+// in the same order as they would be passed to `Verify`.
+func NewProfile(validators ...TokenValidator) Profile {
+	return Profile(nil).With(validators...)
+}
+
+// With returns a new Profile of the Profile's validators followed by the given ones.
+// The Profile itself is not modified, so it can be shared between goroutines
+// and composed differently by each call site.
+func (p Profile) With(validators ...TokenValidator) Profile {
+	joined := make(Profile, 0, len(p)+len(validators))
+	joined = append(joined, p...)
+	return append(joined, validators...)
+}
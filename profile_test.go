@@ -0,0 +1,66 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+	"time"
+)
+
+func TestProfile(t *testing.T) {
+	profile := NewProfile(ClockSkew(time.Minute), ExpectIssuer("my-idp"), ExpectAudience("my-service"))
+	verifier := NewVerifier(testAlg, testSecret, profile...)
+
+	var tests = []struct {
+		claims  Claims
+		wantErr error
+	}{
+		{Claims{Issuer: "my-idp", Audience: Audience{"my-service"}}, nil},
+		{Claims{Issuer: "my-idp", Audience: Audience{"my-service"}, Expiry: Clock().Add(-30 * time.Second).Unix()}, nil},
+		{Claims{Issuer: "my-idp", Audience: Audience{"my-service"}, Expiry: Clock().Add(-2 * time.Minute).Unix()}, ErrExpired},
+		{Claims{Issuer: "other", Audience: Audience{"my-service"}}, ErrExpected},
+		{Claims{Issuer: "my-idp"}, ErrExpected},
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, tt.claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// Two different call sites of the same profile.
+		_, errVerify := Verify(testAlg, testSecret, token, profile...)
+		_, errVerifier := verifier.Verify(token)
+		if !errors.Is(errVerify, tt.wantErr) || !errors.Is(errVerifier, tt.wantErr) {
+			t.Fatalf("[%d] expected error: %v but got: %v and %v", i, tt.wantErr, errVerify, errVerifier)
+		}
+
+		if (errVerify == nil) != (errVerifier == nil) || (errVerify != nil && errVerify.Error() != errVerifier.Error()) {
+			t.Fatalf("[%d] expected the same error but got: %v and %v", i, errVerify, errVerifier)
+		}
+	}
+
+	// Test composition.
+	token, err := Sign(testAlg, testSecret, Claims{Issuer: "my-idp", Audience: Audience{"my-service"}, Subject: "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	extended := profile.With(ExpectSubject("admin"))
+	if _, err = Verify(testAlg, testSecret, token, extended...); !errors.Is(err, ErrExpected) {
+		t.Fatalf("expected error: %v but got: %v", ErrExpected, err)
+	}
+
+	if len(profile) != 3 || len(extended) != 4 {
+		t.Fatalf("expected the profile to be left untouched but got: %d and %d validators", len(profile), len(extended))
+	}
+
+	// Appending to one composed profile does not affect another one.
+	a, b := profile.With(ExpectSubject("kataras")), profile.With(ExpectSubject("admin"))
+	if _, err = Verify(testAlg, testSecret, token, a...); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, b...); !errors.Is(err, ErrExpected) {
+		t.Fatalf("expected error: %v but got: %v", ErrExpected, err)
+	}
+}
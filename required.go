@@ -1,6 +1,7 @@
 package jwt
 
 import (
+	"encoding/json"
 	"errors"
 	"fmt"
 	"reflect"
@@ -66,3 +67,80 @@ func indirectType(typ reflect.Type) reflect.Type {
 	}
 	return typ
 }
+
+// ErrMissingRequiredClaim indicates that the token does not contain a claim of the `RequireClaims`
+// or its value is null or empty. It is an ErrMissingKey too.
+var ErrMissingRequiredClaim = fmt.Errorf("%w: claim", ErrMissingKey)
+
+type requiredClaims []string
+
+// ValidateToken completes the TokenValidator interface.
+// It respects the previous error, the claims themselves
+// are checked after all the validators, see `RequireClaims`.
+func (requiredClaims) ValidateToken(_ []byte, _ Claims, err error) error {
+	return err
+}
+
+// RequireClaims is a TokenValidator which makes sure that
+// the token's payload contains all of the given claims "names",
+// including custom ones, e.g. RequireClaims("sub", "iss", "tenant_id").
+// A claim with a null, empty or zero value (e.g. "", 0, false, [] and {})
+// is considered missing.
+//
+// It returns a type of ErrMissingRequiredClaim error which contains the missing claim's name.
+//
+// The claims are checked after the signature, the standard claims and the rest of the validators passed,
+// against the verified payload, so compressed (see `WithCompression`)
+// and encrypted (see `VerifyEncrypted`) payloads are supported too.
+//
+// Usage:
+//
+//	verifiedToken, err := jwt.Verify(jwt.EdDSA, publicKey, token, jwt.RequireClaims("sub", "tenant_id"))
+func RequireClaims(names ...string) TokenValidator {
+	return requiredClaims(names)
+}
+
+// validateRequiredClaims checks the `RequireClaims` of the "validators"
+// against the decoded "payload".
+func validateRequiredClaims(payload []byte, validators []TokenValidator) error {
+	var claims map[string]interface{}
+
+	for _, v := range validators {
+		names, ok := v.(requiredClaims)
+		if !ok || len(names) == 0 {
+			continue
+		}
+
+		if claims == nil {
+			if err := Unmarshal(payload, &claims); err != nil {
+				return fmt.Errorf("%w: %v", errPayloadNotJSON, err)
+			}
+		}
+
+		for _, name := range names {
+			if isEmptyClaim(claims[name]) {
+				return fmt.Errorf("%w: %q", ErrMissingRequiredClaim, name)
+			}
+		}
+	}
+
+	return nil
+}
+
+func isEmptyClaim(v interface{}) bool {
+	switch n := v.(type) {
+	case nil:
+		return true
+	case json.Number: // see `Unmarshal`.
+		f, err := n.Float64()
+		return err == nil && f == 0
+	}
+
+	val := reflect.ValueOf(v)
+	switch val.Kind() {
+	case reflect.String, reflect.Slice, reflect.Map:
+		return val.Len() == 0
+	default:
+		return val.IsZero()
+	}
+}
@@ -2,6 +2,7 @@ package jwt
 
 import (
 	"errors"
+	"strings"
 	"testing"
 )
 
@@ -50,3 +51,40 @@ func TestUnmarshalWithRequired(t *testing.T) {
 		t.Fatalf("expected error: ErrMissingKey but got: %v", err)
 	}
 }
+
+func TestRequireClaims(t *testing.T) {
+	var tests = []struct {
+		claims  Map
+		missing string
+	}{
+		{Map{"sub": "kataras", "iss": "my-app", "tenant_id": 1}, ""},
+		{Map{"sub": "kataras", "iss": "my-app"}, "tenant_id"},
+		{Map{"sub": "", "iss": "my-app", "tenant_id": 1}, "sub"},              // present but empty.
+		{Map{"sub": "kataras", "iss": nil, "tenant_id": 1}, "iss"},            // present but null.
+		{Map{"sub": "kataras", "iss": "my-app", "tenant_id": 0}, "tenant_id"}, // present but zero.
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, tt.claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		_, err = Verify(testAlg, testSecret, token, RequireClaims("sub", "iss", "tenant_id"))
+		if tt.missing == "" {
+			if err != nil {
+				t.Fatalf("[%d] expected no error but got: %v", i, err)
+			}
+
+			continue
+		}
+
+		if !errors.Is(err, ErrMissingRequiredClaim) || !errors.Is(err, ErrMissingKey) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, ErrMissingRequiredClaim, err)
+		}
+
+		if expected := `"` + tt.missing + `"`; !strings.HasSuffix(err.Error(), expected) {
+			t.Fatalf("[%d] expected error to contain the claim name: %s but got: %v", i, expected, err)
+		}
+	}
+}
@@ -36,6 +36,29 @@ func (a *algRSA) Name() string {
 	return a.name
 }
 
+func (a *algRSA) ExpectedSignatureSize(key PublicKey) int {
+	return rsaSignatureSize(key)
+}
+
+// rsaSignatureSize returns the modulus size in bytes of the RSA "key",
+// which is the length of a PKCS #1 v1.5 and a PSS signature,
+// or zero if the "key" is not an RSA one.
+func rsaSignatureSize(key PublicKey) int {
+	var publicKey *rsa.PublicKey
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		publicKey = k
+	case *rsa.PrivateKey:
+		publicKey = &k.PublicKey
+	}
+
+	if publicKey == nil || publicKey.N == nil {
+		return 0
+	}
+
+	return publicKey.Size()
+}
+
 func (a *algRSA) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error) {
 	privateKey, ok := key.(*rsa.PrivateKey)
 	if !ok {
@@ -136,6 +159,7 @@ func LoadPublicKeyRSA(filename string) (*rsa.PublicKey, error) {
 
 // ParsePrivateKeyRSA decodes and parses the
 // PEM-encoded RSA private key's raw contents.
+// The key can be PKCS #1 or PKCS #8 encoded.
 // Pass the result to the `Token` (signing) function.
 func ParsePrivateKeyRSA(key []byte) (*rsa.PrivateKey, error) {
 	block, _ := pem.Decode(key)
@@ -162,6 +186,7 @@ func ParsePrivateKeyRSA(key []byte) (*rsa.PrivateKey, error) {
 
 // ParsePublicKeyRSA decodes and parses the
 // PEM-encoded RSA public key's raw contents.
+// The key can be PKIX, PKCS #1 ("RSA PUBLIC KEY") encoded or a certificate.
 // Pass the result to the `Verify` function.
 func ParsePublicKeyRSA(key []byte) (*rsa.PublicKey, error) {
 	block, _ := pem.Decode(key)
@@ -171,6 +196,10 @@ func ParsePublicKeyRSA(key []byte) (*rsa.PublicKey, error) {
 
 	parsedKey, err := x509.ParsePKIXPublicKey(block.Bytes)
 	if err != nil {
+		if publicKey, err := x509.ParsePKCS1PublicKey(block.Bytes); err == nil {
+			return publicKey, nil
+		}
+
 		if cert, err := x509.ParseCertificate(block.Bytes); err == nil {
 			parsedKey = cert.PublicKey
 		} else {
@@ -0,0 +1,79 @@
+package jwt
+
+import (
+	"crypto/rsa"
+	"fmt"
+)
+
+// ErrWeakKey indicates that the RSA key of the verification is smaller
+// than the minimum size, see `MinRSAKeyBits`. It is an ErrInvalidKey too.
+var ErrWeakKey = fmt.Errorf("%w: weak key", ErrInvalidKey)
+
+// DefaultMinRSAKeyBits is the minimum RSA key size, in bits, which the verification accepts
+// when no `MinRSAKeyBits` validator is given. Defaults to 2048, as NIST SP 800-131A requires.
+// Set it once, at initialization, to modify it package-wide.
+var DefaultMinRSAKeyBits = 2048
+
+type minRSAKeyBitsOption int
+
+// ValidateToken completes the TokenValidator interface.
+// It respects the previous error.
+func (minRSAKeyBitsOption) ValidateToken(_ []byte, _ Claims, err error) error {
+	return err
+}
+
+// MinRSAKeyBits is a TokenValidator which rejects RSA keys (RS* and PS* algorithms)
+// whose modulus is smaller than "n" bits with an ErrWeakKey error,
+// before the signature is verified.
+// It overrides the `DefaultMinRSAKeyBits`, e.g. to still accept a legacy 1024-bit key
+// during its migration. A zero or negative "n" disables the check.
+// When more than one is given, the last one is used.
+//
+// Usage:
+//  verifiedToken, err := jwt.Verify(jwt.RS256, publicKey, token, jwt.MinRSAKeyBits(3072))
+func MinRSAKeyBits(n int) TokenValidator {
+	return minRSAKeyBitsOption(n)
+}
+
+// minRSAKeyBits returns the minimum of the last `MinRSAKeyBits` of the "validators",
+// the `DefaultMinRSAKeyBits` when none is given.
+func minRSAKeyBits(validators []TokenValidator) int {
+	n := DefaultMinRSAKeyBits
+	for _, v := range validators {
+		if bits, ok := v.(minRSAKeyBitsOption); ok {
+			n = int(bits)
+		}
+	}
+
+	return n
+}
+
+// checkRSAKeySize reports ErrWeakKey if the "key" is an RSA one
+// of less than "minBits" bits. Any other key is left to the algorithm.
+func checkRSAKeySize(key PublicKey, minBits int) error {
+	if minBits <= 0 {
+		return nil
+	}
+
+	var publicKey *rsa.PublicKey
+	switch k := key.(type) {
+	case *rsa.PublicKey:
+		publicKey = k
+	case *rsa.PrivateKey:
+		if k != nil {
+			publicKey = &k.PublicKey
+		}
+	default:
+		return nil
+	}
+
+	if publicKey == nil || publicKey.N == nil {
+		return nil // let the algorithm report the invalid key.
+	}
+
+	if bits := publicKey.N.BitLen(); bits < minBits {
+		return fmt.Errorf("%w: RSA key of %d bits, expected at least %d", ErrWeakKey, bits, minBits)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+)
+
+func TestMinRSAKeyBits(t *testing.T) {
+	weakKey, err := rsa.GenerateKey(rand.Reader, 1024)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	strongKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := Map{"sub": "kataras"}
+
+	for _, alg := range []Alg{RS256, PS256} {
+		weakToken, err := Sign(alg, weakKey, claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		// The default minimum is 2048 bits.
+		if _, err = Verify(alg, &weakKey.PublicKey, weakToken); !errors.Is(err, ErrWeakKey) {
+			t.Fatalf("[%s] expected error: %v but got: %v", alg.Name(), ErrWeakKey, err)
+		}
+
+		if !errors.Is(err, ErrInvalidKey) {
+			t.Fatalf("[%s] expected ErrWeakKey to be an ErrInvalidKey", alg.Name())
+		}
+
+		if _, err = Verify(alg, &weakKey.PublicKey, weakToken, MinRSAKeyBits(2048)); !errors.Is(err, ErrWeakKey) {
+			t.Fatalf("[%s] expected error: %v but got: %v", alg.Name(), ErrWeakKey, err)
+		}
+
+		// The check runs before the signature one.
+		if _, err = Verify(alg, &weakKey.PublicKey, append(weakToken[:len(weakToken)-4:len(weakToken)-4], "AAAA"...)); !errors.Is(err, ErrWeakKey) {
+			t.Fatalf("[%s] expected error: %v but got: %v", alg.Name(), ErrWeakKey, err)
+		}
+
+		// The last one is used.
+		if _, err = Verify(alg, &weakKey.PublicKey, weakToken, MinRSAKeyBits(2048), MinRSAKeyBits(1024)); err != nil {
+			t.Fatalf("[%s] expected a 1024-bit key to pass a 1024 minimum but got: %v", alg.Name(), err)
+		}
+
+		if _, err = Verify(alg, &weakKey.PublicKey, weakToken, MinRSAKeyBits(0)); err != nil {
+			t.Fatalf("[%s] expected a disabled check but got: %v", alg.Name(), err)
+		}
+
+		strongToken, err := Sign(alg, strongKey, claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = Verify(alg, &strongKey.PublicKey, strongToken, MinRSAKeyBits(2048)); err != nil {
+			t.Fatalf("[%s] expected a 2048-bit key to pass a 2048 minimum but got: %v", alg.Name(), err)
+		}
+
+		if _, err = Verify(alg, strongKey, strongToken); err != nil {
+			t.Fatalf("[%s] expected a 2048-bit private key to pass the default minimum but got: %v", alg.Name(), err)
+		}
+
+		if _, err = Verify(alg, &strongKey.PublicKey, strongToken, MinRSAKeyBits(3072)); !errors.Is(err, ErrWeakKey) {
+			t.Fatalf("[%s] expected error: %v but got: %v", alg.Name(), ErrWeakKey, err)
+		}
+	}
+
+	// Non RSA keys are left to the algorithm.
+	token, err := Sign(testAlg, testSecret, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, MinRSAKeyBits(4096)); err != nil {
+		t.Fatal(err)
+	}
+}
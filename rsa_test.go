@@ -92,3 +92,24 @@ func generateTestFilesRSA() error {
 	}
 	return ioutil.WriteFile("./_testfiles/rsa_public_key.pem", pubKeyPem, 0666)
 }
+
+func TestParsePublicKeyRSAPKCS1(t *testing.T) {
+	expected, err := LoadPublicKeyRSA("./_testfiles/rsa_public_key.pem")
+	if err != nil {
+		t.Fatalf("rsa: public key: %v", err)
+	}
+
+	publicKeyPem := pem.EncodeToMemory(&pem.Block{
+		Type:  "RSA PUBLIC KEY",
+		Bytes: x509.MarshalPKCS1PublicKey(expected),
+	})
+
+	got, err := ParsePublicKeyRSA(publicKeyPem)
+	if err != nil {
+		t.Fatalf("rsa: pkcs1 public key: %v", err)
+	}
+
+	if !expected.Equal(got) {
+		t.Fatalf("expected parsed pkcs1 public key to match")
+	}
+}
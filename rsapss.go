@@ -33,6 +33,10 @@ func (a *algRSAPSS) Name() string {
 	return a.name
 }
 
+func (a *algRSAPSS) ExpectedSignatureSize(key PublicKey) int {
+	return rsaSignatureSize(key)
+}
+
 func (a *algRSAPSS) Sign(key PrivateKey, headerAndPayload []byte) ([]byte, error) {
 	privateKey, ok := key.(*rsa.PrivateKey)
 	if !ok {
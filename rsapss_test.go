@@ -1,6 +1,12 @@
 package jwt
 
-import "testing"
+import (
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"errors"
+	"testing"
+)
 
 func TestEncodeDecodeTokenRSAPSS(t *testing.T) {
 	privateKey, err := LoadPrivateKeyRSA("./_testfiles/rsapss_private_key.pem")
@@ -14,6 +20,31 @@ func TestEncodeDecodeTokenRSAPSS(t *testing.T) {
 	}
 
 	testEncodeDecodeToken(t, PS256, privateKey, publicKey, nil)
+	testEncodeDecodeToken(t, PS384, privateKey, publicKey, nil)
+	testEncodeDecodeToken(t, PS512, privateKey, publicKey, nil)
 	// test the automatic extract of public key from private key.
 	testEncodeDecodeToken(t, PS256, privateKey, privateKey, nil)
 }
+
+func TestRSAPSSSaltLength(t *testing.T) {
+	privateKey, err := LoadPrivateKeyRSA("./_testfiles/rsapss_private_key.pem")
+	if err != nil {
+		t.Fatalf("rsa-pss: private key: %v", err)
+	}
+
+	headerAndPayload := []byte("header.payload")
+	h := crypto.SHA256.New()
+	h.Write(headerAndPayload)
+
+	// Sign with a salt length which is different than the hash's output.
+	signature, err := rsa.SignPSS(rand.Reader, privateKey, crypto.SHA256, h.Sum(nil), &rsa.PSSOptions{
+		SaltLength: rsa.PSSSaltLengthAuto,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if err = PS256.Verify(&privateKey.PublicKey, headerAndPayload, signature); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+}
@@ -0,0 +1,142 @@
+package jwt
+
+import (
+	"encoding/json"
+	"strings"
+)
+
+// Scope represents the "scope" (and "scp") claim of OAuth 2.0 tokens,
+// a space-delimited list of scopes (RFC 8693, section 4.2), e.g. "read:users write:users".
+// It accepts an array of strings too, as some issuers produce,
+// but it's always encoded to the space-delimited form.
+// See the `Claims.Scopes` and `Claims.HasScope` methods.
+type Scope []string
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// The scope is expected to be a space-delimited string or an array of strings.
+// Any other value (e.g. a number or an array of objects) is ignored, as it's not a scope,
+// and so are the non-string elements of an array.
+func (scope *Scope) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' { // it's a space-delimited string.
+		var scopeString string
+		if json.Unmarshal(data, &scopeString) == nil {
+			*scope = strings.Fields(scopeString)
+		}
+		return nil
+	}
+
+	*scope = stringElements(data)
+	return nil
+}
+
+// MarshalJSON implements the json.Marshaler interface.
+// It encodes the scope to a space-delimited string.
+func (scope Scope) MarshalJSON() ([]byte, error) {
+	return json.Marshal(strings.Join(scope, " "))
+}
+
+// Roles represents the "roles" claim, an array of strings.
+// A single string is accepted as a single role.
+// See the `Claims.HasRole` method.
+type Roles []string
+
+// UnmarshalJSON implements the json.Unmarshaler interface.
+// The roles are expected to be a single string or an array of strings.
+// Any other value (e.g. an array of objects like {"name":"admin"}) is ignored,
+// and so are the non-string elements of an array.
+func (roles *Roles) UnmarshalJSON(data []byte) error {
+	if len(data) > 0 && data[0] == '"' { // it's a single role.
+		var role string
+		if json.Unmarshal(data, &role) == nil {
+			*roles = Roles{role}
+		}
+		return nil
+	}
+
+	*roles = stringElements(data)
+	return nil
+}
+
+// stringElements returns the string elements of the "data" JSON array,
+// nil if it's not an array.
+func stringElements(data []byte) []string {
+	if len(data) == 0 || data[0] != '[' {
+		return nil
+	}
+
+	var elements []json.RawMessage
+	if json.Unmarshal(data, &elements) != nil {
+		return nil
+	}
+
+	values := make([]string, 0, len(elements))
+	for _, element := range elements {
+		var value string
+		if len(element) > 0 && element[0] == '"' && json.Unmarshal(element, &value) == nil {
+			values = append(values, value)
+		}
+	}
+
+	return values
+}
+
+// Scopes returns the scopes of the "scope" and the "scp" claims, without duplicates.
+func (c Claims) Scopes() []string {
+	if len(c.Scp) == 0 {
+		return c.Scope
+	}
+
+	scopes := make([]string, 0, len(c.Scope)+len(c.Scp))
+	for _, list := range [][]string{c.Scope, c.Scp} {
+		for _, s := range list {
+			if !containsString(scopes, s) {
+				scopes = append(scopes, s)
+			}
+		}
+	}
+
+	return scopes
+}
+
+// HasScope reports whether the "scope" or the "scp" claim contains the "scope" value.
+func (c Claims) HasScope(scope string) bool {
+	return scope != "" && (containsString(c.Scope, scope) || containsString(c.Scp, scope))
+}
+
+// HasAllScopes reports whether the claims hold all the given scopes.
+// It returns false if no scope was given.
+func (c Claims) HasAllScopes(scopes ...string) bool {
+	for _, scope := range scopes {
+		if !c.HasScope(scope) {
+			return false
+		}
+	}
+
+	return len(scopes) > 0
+}
+
+// HasAnyScope reports whether the claims hold at least one of the given scopes.
+func (c Claims) HasAnyScope(scopes ...string) bool {
+	for _, scope := range scopes {
+		if c.HasScope(scope) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// HasRole reports whether the "roles" claim contains the "role" value.
+func (c Claims) HasRole(role string) bool {
+	return role != "" && containsString(c.Roles, role)
+}
+
+func containsString(list []string, v string) bool {
+	for _, s := range list {
+		if s == v {
+			return true
+		}
+	}
+
+	return false
+}
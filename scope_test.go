@@ -0,0 +1,114 @@
+package jwt
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestClaimsScopes(t *testing.T) {
+	var tests = []struct {
+		payload string
+		scopes  []string
+	}{
+		{`{"scope":"read:users write:users"}`, []string{"read:users", "write:users"}},
+		{`{"scope":"  read:users   write:users "}`, []string{"read:users", "write:users"}},
+		{`{"scope":"read:users"}`, []string{"read:users"}},
+		{`{"scp":["read:users","write:users"]}`, []string{"read:users", "write:users"}},
+		{`{"scope":"read:users","scp":"read:users write:users"}`, []string{"read:users", "write:users"}},
+		{`{"scope":""}`, []string{}},
+		{`{}`, nil},
+		// Unknown shapes are ignored, they are not scopes.
+		{`{"scope":[1,2]}`, nil},
+		{`{"scope":["read:users",1,{"name":"write:users"}]}`, []string{"read:users"}},
+		{`{"scope":{"read:users":true},"scp":"write:users"}`, []string{"write:users"}},
+		{`{"scope":true}`, nil},
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, []byte(tt.payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		verifiedToken, err := Verify(testAlg, testSecret, token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		claims := verifiedToken.StandardClaims
+		if got := claims.Scopes(); len(got) != len(tt.scopes) || (len(got) > 0 && !reflect.DeepEqual(got, tt.scopes)) {
+			t.Fatalf("[%d] expected scopes: %q but got: %q", i, tt.scopes, got)
+		}
+
+		for _, scope := range tt.scopes {
+			if !claims.HasScope(scope) {
+				t.Fatalf("[%d] expected to have scope: %q", i, scope)
+			}
+		}
+
+		if claims.HasScope("") || claims.HasScope("admin") {
+			t.Fatalf("[%d] expected not to have an empty or unknown scope", i)
+		}
+
+		if expected := len(tt.scopes) > 0; claims.HasAllScopes(tt.scopes...) != expected {
+			t.Fatalf("[%d] expected HasAllScopes to report: %v", i, expected)
+		}
+
+		if claims.HasAllScopes(append(tt.scopes, "admin")...) {
+			t.Fatalf("[%d] expected HasAllScopes to fail on an unknown scope", i)
+		}
+
+		if expected := len(tt.scopes) > 0; claims.HasAnyScope(append([]string{"admin"}, tt.scopes...)...) != expected {
+			t.Fatalf("[%d] expected HasAnyScope to report: %v", i, expected)
+		}
+	}
+
+	// test the space-delimited form on sign.
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, Claims{Scope: Scope{"read:users", "write:users"}, Roles: Roles{"admin"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got map[string]interface{}
+	if err = VerifyToken(testAlg, testSecret, token, &got); err != nil {
+		t.Fatal(err)
+	}
+
+	if got["scope"] != "read:users write:users" {
+		t.Fatalf("expected a space-delimited scope but got: %#+v", got["scope"])
+	}
+}
+
+func TestClaimsHasRole(t *testing.T) {
+	var tests = []struct {
+		payload string
+		role    string
+		ok      bool
+	}{
+		{`{"roles":["admin","editor"]}`, "editor", true},
+		{`{"roles":"admin"}`, "admin", true},
+		{`{"roles":["admin editor"]}`, "editor", false},
+		{`{"roles":[]}`, "admin", false},
+		{`{}`, "", false},
+		// Unknown shapes are ignored, they are not roles.
+		{`{"roles":[{"name":"admin"}]}`, "admin", false},
+		{`{"roles":[{"name":"admin"},"editor"]}`, "editor", true},
+		{`{"roles":7}`, "7", false},
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, []byte(tt.payload))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		verifiedToken, err := Verify(testAlg, testSecret, token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if got := verifiedToken.StandardClaims.HasRole(tt.role); got != tt.ok {
+			t.Fatalf("[%d] expected HasRole(%q) to report: %v", i, tt.role, tt.ok)
+		}
+	}
+}
@@ -1,5 +1,12 @@
 package jwt
 
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
 // Sign signs and generates a new token based on the algorithm and a secret key.
 // The claims is the payload, the actual body of the token, should
 // contain information about a specific authorized client.
@@ -52,17 +59,192 @@ func SignEncryptedWithHeader(alg Alg, key PrivateKey, encrypt InjectFunc, claims
 	return signToken(alg, key, encrypt, claims, customHeader, opts...)
 }
 
+// ErrInvalidPayload indicates that the payload given to `SignRaw`
+// is not a JSON object, e.g. an array or a scalar value.
+var ErrInvalidPayload = errors.New("jwt: invalid payload: expected a JSON object")
+
+// SignRaw same as `Sign` but it accepts an already marshaled (e.g. canonical) JSON payload,
+// which is signed as it is, without re-marshaling.
+// The payload must be a JSON object, otherwise it fails with ErrInvalidPayload.
+// The claims of the "opts" (e.g. `MaxAge`) are merged into the payload,
+// they override the payload's fields of the same name, the rest fields keep their order.
+//
+// Usage:
+//  token, err := jwt.SignRaw(jwt.EdDSA, privateKey, payload, jwt.MaxAge(15*time.Minute))
+func SignRaw(alg Alg, key PrivateKey, payload json.RawMessage, opts ...SignOption) ([]byte, error) {
+	if !isJSONObject(payload) {
+		return nil, ErrInvalidPayload
+	}
+
+	var (
+		standardClaims Claims
+		claimsOptions  int
+		signOptions    = make([]SignOption, 0, len(opts))
+	)
+
+	for _, opt := range opts {
+		switch opt.(type) {
+		case nil:
+		case canonicalJSONOption, observerOption, compressionOption, HeaderSignOption:
+			signOptions = append(signOptions, opt)
+		default:
+			opt.ApplyClaims(&standardClaims)
+			claimsOptions++
+		}
+	}
+
+	claims := []byte(payload)
+	if claimsOptions > 0 {
+		merged, err := mergeRawClaims(claims, standardClaims)
+		if err != nil {
+			return nil, err
+		}
+
+		claims = merged
+	}
+
+	return signToken(alg, key, nil, claims, nil, signOptions...)
+}
+
+// mergeRawClaims writes the (object) "payload" fields, except the ones of the "standardClaims",
+// and then the non-zero "standardClaims" fields.
+func mergeRawClaims(payload []byte, standardClaims Claims) ([]byte, error) {
+	claimsB, err := json.Marshal(standardClaims)
+	if err != nil {
+		return nil, err
+	}
+
+	var claimsFields map[string]json.RawMessage
+	if err = json.Unmarshal(claimsB, &claimsFields); err != nil {
+		return nil, err
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	if _, err = dec.Token(); err != nil { // the '{'.
+		return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+		}
+
+		var value json.RawMessage
+		if err = dec.Decode(&value); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+		}
+
+		name, _ := t.(string)
+		if _, ok := claimsFields[name]; ok {
+			continue
+		}
+
+		nameB, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(nameB)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+
+	// The fields of the standard claims, in their declaration order.
+	if claimsB = bytes.TrimSpace(claimsB); len(claimsB) > 2 {
+		if buf.Len() > 1 {
+			buf.WriteByte(',')
+		}
+		buf.Write(claimsB[1 : len(claimsB)-1])
+	}
+
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// mergeClaims merges the claims of the sign options into the "claims",
+// the "standardClaims" replace the fields of the same name,
+// so the payload never holds a duplicated key, see `mergeRawClaims`.
+// Claims which are not encoded to a JSON object are merged by `Merge`.
+func mergeClaims(claims interface{}, standardClaims Claims) ([]byte, error) {
+	claimsB, err := Marshal(claims)
+	if err != nil {
+		return nil, err
+	}
+
+	if !isJSONObject(claimsB) {
+		return Merge(claimsB, standardClaims), nil
+	}
+
+	return mergeRawClaims(claimsB, standardClaims)
+}
+
 func signToken(alg Alg, key PrivateKey, encrypt InjectFunc, claims interface{}, customHeader interface{}, opts ...SignOption) ([]byte, error) {
+	var (
+		canonical, compress bool
+		observer            Observer
+	)
+
 	if len(opts) > 0 {
-		var standardClaims Claims
+		var (
+			standardClaims Claims
+			claimsOptions  int
+			headerOptions  []HeaderSignOption
+		)
+
 		for _, opt := range opts {
 			if opt == nil {
 				continue
 			}
+
+			if _, ok := opt.(canonicalJSONOption); ok {
+				canonical = true
+				continue
+			}
+
+			if o, ok := opt.(observerOption); ok {
+				observer = o.Observer
+				continue
+			}
+
+			if _, ok := opt.(compressionOption); ok {
+				compress = true
+				headerOptions = append(headerOptions, zipHeader)
+				continue
+			}
+
+			if headerOpt, ok := opt.(HeaderSignOption); ok {
+				headerOptions = append(headerOptions, headerOpt)
+				continue
+			}
+
 			opt.ApplyClaims(&standardClaims)
+			claimsOptions++
 		}
 
-		claims = Merge(claims, standardClaims)
+		if claimsOptions > 0 {
+			merged, err := mergeClaims(claims, standardClaims)
+			if err != nil {
+				return nil, err
+			}
+
+			claims = merged
+		}
+
+		if len(headerOptions) > 0 {
+			header, err := applyHeaderOptions(alg, customHeader, headerOptions)
+			if err != nil {
+				return nil, err
+			}
+
+			customHeader = header
+		}
 	}
 
 	payload, err := Marshal(claims)
@@ -70,6 +252,20 @@ func signToken(alg Alg, key PrivateKey, encrypt InjectFunc, claims interface{},
 		return nil, err
 	}
 
+	if canonical {
+		payload, err = canonicalJSON(payload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if compress {
+		payload, err = compressPayload(payload)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	if encrypt != nil {
 		payload, err = encrypt(payload)
 		if err != nil {
@@ -77,14 +273,28 @@ func signToken(alg Alg, key PrivateKey, encrypt InjectFunc, claims interface{},
 		}
 	}
 
-	return encodeToken(alg, key, payload, customHeader)
+	token, err := encodeToken(alg, key, payload, customHeader)
+	if err == nil && observer != nil {
+		observer.OnSign(alg.Name())
+	}
+
+	return token, err
 }
 
 // SignOption is just a helper which sets the standard claims at the `Sign` function.
 //
 // Available SignOptions:
 // - MaxAge(time.Duration)
+// - SetIssuedAt(), NotBeforeFromNow(time.Duration), ExpiresIn(time.Duration)
+// - WithRandomJTI(*string)
 // - Claims{}
+// - WithKid(string)
+// - WithType(string)
+// - WithHeader(string, interface{})
+// - WithHeaders(map[string]interface{})
+// - CanonicalJSON
+// - WithCompression()
+// - WithObserver(Observer)
 type SignOption interface {
 	// ApplyClaims should apply standard claims.
 	// Accepts the destination claims.
@@ -98,3 +308,143 @@ type SignOptionFunc func(*Claims)
 func (f SignOptionFunc) ApplyClaims(c *Claims) {
 	f(c)
 }
+
+// HeaderSignOption is a SignOption which sets header fields instead of claims.
+// Its ApplyClaims method does nothing.
+// See `WithKid`.
+type HeaderSignOption func(*Header) error
+
+// ApplyClaims completes the `SignOption` interface. It does nothing.
+func (f HeaderSignOption) ApplyClaims(*Claims) {}
+
+// WithKid is a SignOption which sets the "kid" header field,
+// the key id which hints the verifier which key to use.
+//
+// Usage:
+//  token, err := jwt.Sign(jwt.EdDSA, privateKey, claims, jwt.WithKid("2021-02"), jwt.MaxAge(15*time.Minute))
+func WithKid(kid string) HeaderSignOption {
+	return func(h *Header) error {
+		h.Kid = kid
+		return nil
+	}
+}
+
+// WithType is a SignOption which sets the "typ" header field,
+// instead of the default "JWT" one, e.g. "at+jwt" for OAuth 2.0 access tokens (RFC 9068).
+// See the `ExpectType` TokenValidator too.
+//
+// Usage:
+//  token, err := jwt.Sign(jwt.EdDSA, privateKey, claims, jwt.WithType("at+jwt"))
+func WithType(typ string) HeaderSignOption {
+	return func(h *Header) error {
+		h.Typ = typ
+		return nil
+	}
+}
+
+// ErrProtectedHeader indicates that a `WithHeader` or `WithHeaders` sign option
+// tried to override the "alg" or the "typ" header field
+// or to set a field which changes how the token is processed: "b64", "crit" or "zip".
+// Use the `WithType` sign option to set a "typ" explicitly,
+// the `WithCompression` for a "zip" one and the `SignUnencoded` for "b64" and "crit".
+var ErrProtectedHeader = errors.New("jwt: protected header field")
+
+// WithHeader is a SignOption which sets a header field,
+// e.g. the "cty" (content type) or the "x5t" (certificate thumbprint).
+// The "kid" and "cty" values must be strings.
+// It returns an ErrProtectedHeader error when "key" is "alg" or "typ",
+// so the signing algorithm can not be altered by accident,
+// or "b64", "crit" or "zip", which the verifier would misread.
+//
+// Usage:
+//  token, err := jwt.Sign(jwt.EdDSA, privateKey, claims, jwt.WithHeader("x5t", thumbprint))
+func WithHeader(key string, value interface{}) HeaderSignOption {
+	return func(h *Header) error {
+		return h.set(key, value)
+	}
+}
+
+// WithHeaders same as `WithHeader` but it sets multiple header fields at once.
+func WithHeaders(fields map[string]interface{}) HeaderSignOption {
+	return func(h *Header) error {
+		for key, value := range fields {
+			if err := h.set(key, value); err != nil {
+				return err
+			}
+		}
+
+		return nil
+	}
+}
+
+// applyHeaderOptions builds the header of the token.
+// The "customHeader" can be nil, a Header or a HeaderWithKid value.
+func applyHeaderOptions(alg Alg, customHeader interface{}, opts []HeaderSignOption) (Header, error) {
+	var h Header
+
+	switch v := customHeader.(type) {
+	case nil:
+		h = Header{Alg: alg.Name(), Typ: "JWT"}
+	case Header:
+		h = v
+	case *Header:
+		h = *v
+	case HeaderWithKid:
+		h = Header{Alg: v.Alg, Kid: v.Kid}
+	default:
+		return h, fmt.Errorf("jwt: header sign options: unsupported custom header type: %T", customHeader)
+	}
+
+	if len(h.Extra) > 0 { // do not modify the caller's map.
+		extra := make(map[string]interface{}, len(h.Extra))
+		for k, v := range h.Extra {
+			extra[k] = v
+		}
+		h.Extra = extra
+	}
+
+	for _, opt := range opts {
+		if err := opt(&h); err != nil {
+			return h, err
+		}
+	}
+
+	return h, nil
+}
+
+type canonicalJSONOption struct{}
+
+func (canonicalJSONOption) ApplyClaims(*Claims) {}
+
+// CanonicalJSON is a SignOption which re-encodes the JSON payload
+// with sorted object keys (at any depth) and without insignificant whitespace,
+// so the same logical claims always produce the same token,
+// even if they are passed as raw bytes or hold json.RawMessage values.
+// Useful for reproducible tokens, e.g. in tests or for idempotency keys derived from tokens.
+//
+// It uses the standard encoding/json package, even if `SetJSONCodec` was called.
+// Note that it may change the exact payload bytes, and therefore the signature,
+// compared to a token signed without it.
+//
+// Usage:
+//  token, err := jwt.Sign(jwt.EdDSA, privateKey, claims, jwt.CanonicalJSON)
+var CanonicalJSON SignOption = canonicalJSONOption{}
+
+// canonicalJSON decodes and encodes the "payload" again,
+// encoding/json writes the map keys sorted.
+// The numbers are kept as they are through json.Number.
+func canonicalJSON(payload []byte) ([]byte, error) {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+
+	var v interface{}
+	if err := dec.Decode(&v); err != nil {
+		return nil, fmt.Errorf("jwt: canonical json: %w", err)
+	}
+
+	if dec.More() {
+		return nil, fmt.Errorf("jwt: canonical json: %w", errPayloadNotJSON)
+	}
+
+	return json.Marshal(v)
+}
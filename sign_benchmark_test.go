@@ -0,0 +1,19 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"testing"
+)
+
+func BenchmarkSignEdDSA(b *testing.B) {
+	privateKey := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		if _, err := Sign(EdDSA, privateKey, testStructValue); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
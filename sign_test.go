@@ -1,6 +1,9 @@
 package jwt
 
 import (
+	"bytes"
+	"encoding/json"
+	"errors"
 	"reflect"
 	"testing"
 	"time"
@@ -59,3 +62,179 @@ func TestSignOption(t *testing.T) {
 		t.Fatalf("expected custom claims:\n%#+v\n\nbut got:\n%#+v", expectedCustomClaims, got)
 	}
 }
+
+func TestSignWithKid(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"foo": "bar"}, WithKid("api"), MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := verifiedToken.ParseHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := (Header{Alg: testAlg.Name(), Typ: "JWT", Kid: "api"}); !reflect.DeepEqual(expected, h) {
+		t.Fatalf("expected header:\n%#+v\n\nbut got:\n%#+v", expected, h)
+	}
+
+	if verifiedToken.StandardClaims.Expiry == 0 {
+		t.Fatalf("expected the claims sign options to be applied too")
+	}
+
+	// Test the keys selection by the kid.
+	keys := make(Keys)
+	keys.Register(testAlg, "api", testSecret, testSecret)
+	var claims Map
+	if err = keys.VerifyToken(token, &claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = SignWithHeader(testAlg, testSecret, Map{"foo": "bar"}, Map{"alg": testAlg.Name()}, WithKid("api")); err == nil {
+		t.Fatalf("expected an error on unsupported custom header type")
+	}
+}
+
+func TestSignWithHeaderOption(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"foo": "bar"},
+		WithHeader("cty", "JWT"),
+		WithHeaders(map[string]interface{}{"x5t": "thumbprint", "kid": "api"}))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	h, err := verifiedToken.ParseHeader()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for key, expected := range map[string]interface{}{
+		"alg":     testAlg.Name(),
+		"typ":     "JWT",
+		"cty":     "JWT",
+		"kid":     "api",
+		"x5t":     "thumbprint",
+		"missing": nil,
+	} {
+		if got := h.Get(key); got != expected {
+			t.Fatalf("[%s] expected header value: %v but got: %v", key, expected, got)
+		}
+	}
+
+	for _, key := range []string{"alg", "typ", "b64", "crit", "zip"} {
+		if _, err = Sign(testAlg, testSecret, Map{"foo": "bar"}, WithHeader(key, "none")); !errors.Is(err, ErrProtectedHeader) {
+			t.Fatalf("[%s] expected error: %v but got: %v", key, ErrProtectedHeader, err)
+		}
+	}
+
+	if _, err = Sign(testAlg, testSecret, Map{"foo": "bar"}, WithHeaders(map[string]interface{}{"x5t": "thumbprint", "b64": false})); !errors.Is(err, ErrProtectedHeader) {
+		t.Fatalf("expected error: %v but got: %v", ErrProtectedHeader, err)
+	}
+
+	if _, err = Sign(testAlg, testSecret, Map{"foo": "bar"}, WithHeader("kid", 42)); err == nil {
+		t.Fatalf("expected an error on non-string kid value")
+	}
+}
+
+func TestSignCanonicalJSON(t *testing.T) {
+	now := time.Unix(1600000000, 0)
+	Clock = func() time.Time { return now }
+	defer func() { Clock = time.Now }()
+
+	var tests = []interface{}{
+		Map{"username": "kataras", "roles": []string{"admin"}, "meta": json.RawMessage(`{"z": 1, "a": {"y": 2, "b": 3}}`)},
+		[]byte(`{ "meta": {"a": {"b": 3, "y": 2}, "z": 1}, "roles": ["admin"],   "username": "kataras" }`),
+		json.RawMessage(`{"username":"kataras","meta":{"z":1,"a":{"y":2,"b":3}},"roles":["admin"]}`),
+	}
+
+	var expected []byte
+	for i, claims := range tests {
+		token, err := Sign(testAlg, testSecret, claims, CanonicalJSON, MaxAge(time.Minute))
+		if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if i == 0 {
+			expected = token
+			continue
+		}
+
+		if !bytes.Equal(token, expected) {
+			t.Fatalf("[%d] expected the same token for equal claims:\n%s\nbut got:\n%s", i, expected, token)
+		}
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, expected)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expectedPayload := `{"exp":1600000060,"iat":1600000000,"meta":{"a":{"b":3,"y":2},"z":1},"roles":["admin"],"username":"kataras"}`; string(verifiedToken.Payload) != expectedPayload {
+		t.Fatalf("expected payload: %s but got: %s", expectedPayload, verifiedToken.Payload)
+	}
+
+	if _, err = Sign(testAlg, testSecret, []byte("raw"), CanonicalJSON); err == nil {
+		t.Fatalf("expected an error on a non JSON payload")
+	}
+}
+
+func TestSignRaw(t *testing.T) {
+	prevClock := Clock
+	defer func() {
+		Clock = prevClock
+	}()
+
+	now := time.Date(2020, 10, 26, 1, 1, 1, 0, time.UTC)
+	Clock = func() time.Time {
+		return now
+	}
+
+	var tests = []struct {
+		payload string
+		opts    []SignOption
+		want    string
+		wantErr error
+	}{
+		{`{"z":"last","a":1}`, nil, `{"z":"last","a":1}`, nil},
+		{`{"z":"last","a":{"b":[1,2]}}`, []SignOption{MaxAge(time.Minute)}, `{"z":"last","a":{"b":[1,2]},"iat":1603674061,"exp":1603674121}`, nil},
+		{` { "exp" : 1, "sub":"kataras" } `, []SignOption{MaxAge(time.Minute)}, `{"sub":"kataras","iat":1603674061,"exp":1603674121}`, nil},
+		{`{}`, []SignOption{Claims{Issuer: "my-app"}}, `{"iss":"my-app"}`, nil},
+		{`{"sub":"kataras"}`, []SignOption{WithKid("api")}, `{"sub":"kataras"}`, nil},
+		// Non-object payloads.
+		{`[{"sub":"kataras"}]`, nil, "", ErrInvalidPayload},
+		{`"kataras"`, nil, "", ErrInvalidPayload},
+		{`42`, nil, "", ErrInvalidPayload},
+		{`{"sub":"kataras"`, nil, "", ErrInvalidPayload},
+		{``, nil, "", ErrInvalidPayload},
+	}
+
+	for i, tt := range tests {
+		token, err := SignRaw(testAlg, testSecret, json.RawMessage(tt.payload), tt.opts...)
+		if !errors.Is(err, tt.wantErr) {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.wantErr, err)
+		}
+
+		if err != nil {
+			continue
+		}
+
+		verifiedToken, err := Verify(testAlg, testSecret, token, StrictJSON)
+		if err != nil {
+			t.Fatalf("[%d] %v", i, err)
+		}
+
+		if got := string(verifiedToken.Payload); got != tt.want {
+			t.Fatalf("[%d] expected payload:\n%s\nbut got:\n%s", i, tt.want, got)
+		}
+	}
+}
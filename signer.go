@@ -0,0 +1,208 @@
+package jwt
+
+import "sync"
+
+// Signer holds an algorithm, a private key and any default sign options,
+// so the call sites can sign tokens without passing them again,
+// e.g. it can be stored in a service structure.
+// It's safe for concurrent use by multiple goroutines,
+// its key can be rotated through `SetKey` while tokens are signed.
+// During a rotation it can hold more than one key, by their key ids,
+// see `AddKey`, `SetActiveKID` and `SignWithKID`.
+// See `NewSigner` and `Verifier` too.
+type Signer struct {
+	alg  Alg
+	opts []SignOption
+
+	mu   sync.RWMutex // protects the active key, its kid and the keys.
+	key  PrivateKey
+	kid  string
+	keys map[string]PrivateKey
+}
+
+// NewSigner returns a new Signer of the given algorithm and private key.
+// The optional "opts" are applied on every `Signer.Sign` call,
+// before the per call ones.
+//
+// Usage:
+//  signer := jwt.NewSigner(jwt.EdDSA, privateKey, jwt.MaxAge(15*time.Minute))
+//  token, err := signer.Sign(claims)
+func NewSigner(alg Alg, key PrivateKey, opts ...SignOption) *Signer {
+	return &Signer{
+		alg:  alg,
+		key:  key,
+		opts: opts,
+	}
+}
+
+// Sign same as the package-level `Sign` function
+// but it uses the Signer's algorithm, key and default sign options.
+// When a key id was set through `SetKey`, it is written to the "kid" header field,
+// a `WithKid` option overrides it.
+func (s *Signer) Sign(claims interface{}, opts ...SignOption) ([]byte, error) {
+	s.mu.RLock()
+	key, kid := s.key, s.kid
+	s.mu.RUnlock()
+
+	opts = joinSignOptions(s.opts, opts)
+	if kid != "" {
+		opts = joinSignOptions([]SignOption{WithKid(kid)}, opts)
+	}
+
+	return Sign(s.alg, key, claims, opts...)
+}
+
+// SetKey replaces the private key and the key id ("kid" header field) of the Signer.
+// The next `Sign` calls use the new key immediately,
+// the ones already in progress finish with the previous key.
+// A non-empty "kid" is added to the Signer's keys too, see `AddKey`.
+//
+// Usage:
+//  signer := jwt.NewSigner(jwt.EdDSA, privateKey)
+//  [...]
+//  signer.SetKey("2021-03", newPrivateKey)
+func (s *Signer) SetKey(kid string, key PrivateKey) {
+	s.mu.Lock()
+	s.key, s.kid = key, kid
+	if kid != "" {
+		s.addKey(kid, key)
+	}
+	s.mu.Unlock()
+}
+
+// AddKey adds a private key of the given key id to the Signer, without activating it,
+// e.g. a new key which is published to the verifiers before it's used by `Sign`.
+// It replaces any key of the same "kid", the active one included.
+// It returns an ErrEmptyKid error if the "kid" is empty, as it could not be selected.
+// See `SetActiveKID` and `SignWithKID`.
+func (s *Signer) AddKey(kid string, key PrivateKey) error {
+	if kid == "" {
+		return ErrEmptyKid
+	}
+
+	s.mu.Lock()
+	s.addKey(kid, key)
+	if kid == s.kid {
+		s.key = key
+	}
+	s.mu.Unlock()
+
+	return nil
+}
+
+func (s *Signer) addKey(kid string, key PrivateKey) {
+	if s.keys == nil {
+		s.keys = make(map[string]PrivateKey)
+	}
+
+	s.keys[kid] = key
+}
+
+// SetActiveKID sets the key of the given key id, previously added through `AddKey` or `SetKey`,
+// as the one the next `Sign` calls use.
+// It returns an ErrUnknownKid error if the Signer has no such key.
+//
+// Usage:
+//  err := signer.AddKey("2021-04", nextPrivateKey)
+//  [publish the public key and wait for the verifiers to fetch it...]
+//  err = signer.SetActiveKID("2021-04")
+func (s *Signer) SetActiveKID(kid string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	key, ok := s.keys[kid]
+	if !ok {
+		return ErrUnknownKid
+	}
+
+	s.key, s.kid = key, kid
+	return nil
+}
+
+// SignWithKID same as `Sign` but it uses the key of the given key id,
+// previously added through `AddKey` or `SetKey`, whether it's the active one or not.
+// The "kid" is written to the header, it can not be overridden by a `WithKid` option.
+// It returns an ErrUnknownKid error if the Signer has no such key.
+func (s *Signer) SignWithKID(kid string, claims interface{}, opts ...SignOption) ([]byte, error) {
+	s.mu.RLock()
+	key, ok := s.keys[kid]
+	s.mu.RUnlock()
+
+	if !ok {
+		return nil, ErrUnknownKid
+	}
+
+	opts = joinSignOptions(joinSignOptions(s.opts, opts), []SignOption{WithKid(kid)})
+	return Sign(s.alg, key, claims, opts...)
+}
+
+// Verifier holds an algorithm, a public key and any default token validators,
+// so the call sites can verify tokens without passing them again,
+// e.g. it can be stored in a service structure.
+// It's safe for concurrent use by multiple goroutines,
+// as long as its validators are safe too (the builtin ones are).
+// See `NewVerifier` and `Signer` too.
+type Verifier struct {
+	alg        Alg
+	key        PublicKey
+	validators []TokenValidator
+}
+
+// NewVerifier returns a new Verifier of the given algorithm and public key.
+// The optional "validators" are applied on every verification,
+// before the per call ones.
+//
+// Usage:
+//  verifier := jwt.NewVerifier(jwt.EdDSA, publicKey, jwt.ExpectIssuer("my-app"))
+//  var claims myClaims
+//  err := verifier.VerifyToken(token, &claims)
+func NewVerifier(alg Alg, key PublicKey, validators ...TokenValidator) *Verifier {
+	return &Verifier{
+		alg:        alg,
+		key:        key,
+		validators: validators,
+	}
+}
+
+// Verify same as the package-level `Verify` function
+// but it uses the Verifier's algorithm, key and default validators.
+func (v *Verifier) Verify(token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	return Verify(v.alg, v.key, token, joinValidators(v.validators, validators)...)
+}
+
+// VerifyToken same as the package-level `VerifyToken` function
+// but it uses the Verifier's algorithm, key and default validators.
+func (v *Verifier) VerifyToken(token []byte, dest interface{}, validators ...TokenValidator) error {
+	return VerifyToken(v.alg, v.key, token, dest, joinValidators(v.validators, validators)...)
+}
+
+// joinSignOptions returns a new slice of the "defaults" and the "opts",
+// the "defaults" one is shared between goroutines, it must not be appended in place.
+func joinSignOptions(defaults, opts []SignOption) []SignOption {
+	if len(opts) == 0 {
+		return defaults
+	}
+
+	if len(defaults) == 0 {
+		return opts
+	}
+
+	joined := make([]SignOption, 0, len(defaults)+len(opts))
+	joined = append(joined, defaults...)
+	return append(joined, opts...)
+}
+
+// joinValidators same as `joinSignOptions` but for token validators.
+func joinValidators(defaults, validators []TokenValidator) []TokenValidator {
+	if len(validators) == 0 {
+		return defaults
+	}
+
+	if len(defaults) == 0 {
+		return validators
+	}
+
+	joined := make([]TokenValidator, 0, len(defaults)+len(validators))
+	joined = append(joined, defaults...)
+	return append(joined, validators...)
+}
@@ -0,0 +1,251 @@
+package jwt
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestSignerVerifierConcurrent(t *testing.T) {
+	privateKey, publicKey, err := GenerateEdDSA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signer := NewSigner(EdDSA, privateKey, MaxAge(time.Minute), Claims{Issuer: "my-app"})
+	verifier := NewVerifier(EdDSA, publicKey, ExpectIssuer("my-app"))
+
+	const n = 50
+	var (
+		wg   sync.WaitGroup
+		errs = make(chan error, n)
+	)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			subject := fmt.Sprintf("user-%d", i)
+			token, err := signer.Sign(Map{"index": i}, Claims{Subject: subject})
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			var claims struct {
+				Index int `json:"index"`
+				Claims
+			}
+			if err = verifier.VerifyToken(token, &claims, ExpectSubject(subject)); err != nil {
+				errs <- fmt.Errorf("[%d] %w", i, err)
+				return
+			}
+
+			if claims.Index != i || claims.Subject != subject || claims.Issuer != "my-app" || claims.Expiry == 0 {
+				errs <- fmt.Errorf("[%d] unexpected claims: %#+v", i, claims)
+			}
+		}(i)
+	}
+
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	// test the default validators are applied.
+	token, err := NewSigner(EdDSA, privateKey).Sign(Claims{Issuer: "other"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = verifier.Verify(token); !errors.Is(err, ErrInvalidIssuer) {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidIssuer, err)
+	}
+}
+
+func TestSignerSetKey(t *testing.T) {
+	const rotations = 5
+
+	keys := make(Keys)
+	privateKeys := make([]PrivateKey, rotations)
+	for i := range privateKeys {
+		privateKey, publicKey, err := GenerateEdDSA()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		privateKeys[i] = privateKey
+		keys.Register(EdDSA, fmt.Sprintf("key-%d", i), publicKey, nil)
+	}
+
+	signer := NewSigner(EdDSA, nil)
+	signer.SetKey("key-0", privateKeys[0])
+
+	const n = 200
+	var (
+		wg     sync.WaitGroup
+		tokens = make(chan []byte, n)
+		errs   = make(chan error, n)
+	)
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			token, err := signer.Sign(Map{"index": i})
+			if err != nil {
+				errs <- err
+				return
+			}
+
+			tokens <- token
+		}(i)
+	}
+
+	for i := 1; i < rotations; i++ {
+		signer.SetKey(fmt.Sprintf("key-%d", i), privateKeys[i])
+	}
+
+	wg.Wait()
+	close(tokens)
+	close(errs)
+
+	for err := range errs {
+		t.Fatal(err)
+	}
+
+	for token := range tokens {
+		var claims Map
+		if err := keys.VerifyToken(token, &claims); err != nil {
+			t.Fatalf("expected token to be verified by its kid's key but got: %v: %s", err, token)
+		}
+	}
+
+	// test the next sign picks up the new key and kid.
+	token, err := signer.Sign(Map{})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, err := DecodeHeader(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := fmt.Sprintf("key-%d", rotations-1); header.Kid != expected {
+		t.Fatalf("expected kid: %q but got: %q", expected, header.Kid)
+	}
+
+	// test WithKid overrides it.
+	token, err = signer.Sign(Map{}, WithKid("other"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if header, err = DecodeHeader(token); err != nil {
+		t.Fatal(err)
+	}
+
+	if header.Kid != "other" {
+		t.Fatalf("expected kid: %q but got: %q", "other", header.Kid)
+	}
+}
+
+func TestSignerKeys(t *testing.T) {
+	keys := make(Keys)
+	privateKeys := make(map[string]PrivateKey)
+	for _, kid := range []string{"2021-03", "2021-04"} {
+		privateKey, publicKey, err := GenerateEdDSA()
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		privateKeys[kid] = privateKey
+		keys.Register(EdDSA, kid, publicKey, nil)
+	}
+
+	signer := NewSigner(EdDSA, nil, Claims{Issuer: "my-app"})
+	signer.SetKey("2021-03", privateKeys["2021-03"])
+	if err := signer.AddKey("2021-04", privateKeys["2021-04"]); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := signer.AddKey("", privateKeys["2021-04"]); err != ErrEmptyKid {
+		t.Fatalf("expected error: %v but got: %v", ErrEmptyKid, err)
+	}
+
+	verify := func(token []byte, expectedKid string) {
+		t.Helper()
+
+		var claims Claims
+		if err := keys.VerifyToken(token, &claims, ExpectIssuer("my-app")); err != nil {
+			t.Fatal(err)
+		}
+
+		header, err := DecodeHeader(token)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if header.Kid != expectedKid {
+			t.Fatalf("expected kid: %q but got: %q", expectedKid, header.Kid)
+		}
+
+		// The matching public key only.
+		for kid, key := range keys {
+			_, err = Verify(EdDSA, key.Public, token)
+			if kid == expectedKid && err != nil {
+				t.Fatal(err)
+			} else if kid != expectedKid && !errors.Is(err, ErrTokenSignature) {
+				t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+			}
+		}
+	}
+
+	// The added key is not active yet.
+	token, err := signer.Sign(Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify(token, "2021-03")
+
+	for kid := range privateKeys {
+		token, err = signer.SignWithKID(kid, Map{"username": "kataras"}, WithKid("other"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		verify(token, kid)
+	}
+
+	if err = signer.SetActiveKID("2021-04"); err != nil {
+		t.Fatal(err)
+	}
+
+	token, err = signer.Sign(Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify(token, "2021-04")
+
+	// Unknown key ids.
+	if err = signer.SetActiveKID("2021-05"); !errors.Is(err, ErrUnknownKid) {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownKid, err)
+	}
+
+	if _, err = signer.SignWithKID("2021-05", Map{"username": "kataras"}); !errors.Is(err, ErrUnknownKid) {
+		t.Fatalf("expected error: %v but got: %v", ErrUnknownKid, err)
+	}
+
+	// The active key is still the previous one.
+	token, err = signer.Sign(Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verify(token, "2021-04")
+}
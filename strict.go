@@ -0,0 +1,131 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+)
+
+// ErrDuplicateJSONKey indicates that the header or the payload of a token
+// holds an object with the same key twice, e.g. two "exp" claims.
+// Returned by the `Verify` functions when the `StrictJSON` validator is given.
+var ErrDuplicateJSONKey = errors.New("jwt: duplicate JSON key")
+
+// maxStrictJSONDepth limits the nesting of the objects and arrays the duplicate keys scan walks,
+// like encoding/json does.
+const maxStrictJSONDepth = 10000
+
+type strictJSONOption struct{}
+
+// ValidateToken completes the TokenValidator interface.
+// It respects the previous error.
+func (strictJSONOption) ValidateToken(_ []byte, _ Claims, err error) error {
+	return err
+}
+
+// StrictJSON is a TokenValidator which rejects tokens whose header or payload
+// hold duplicate object keys with an ErrDuplicateJSONKey error.
+// The standard encoding/json silently keeps the last duplicate, while other parsers
+// may keep the first one, a well-known JSON smuggling vector, e.g. a second "exp" claim.
+//
+// It also disallows unknown fields when the claims are decoded to a struct value
+// (through `VerifiedToken.Claims`, `VerifyToken` and e.g. `Keys.VerifyToken`),
+// so the struct must declare all the expected claims, including the standard ones
+// (e.g. by embedding the `Claims` type).
+// The strict decoding uses the standard encoding/json package,
+// even if the `Unmarshal` package-level variable was modified.
+//
+// Usage:
+//  var claims struct {
+//    jwt.Claims
+//    Username string `json:"username"`
+//  }
+//  err := jwt.VerifyToken(jwt.EdDSA, publicKey, token, &claims, jwt.StrictJSON)
+var StrictJSON TokenValidator = strictJSONOption{}
+
+// hasStrictJSON reports whether the `StrictJSON` is part of the "validators".
+func hasStrictJSON(validators []TokenValidator) bool {
+	for _, v := range validators {
+		if _, ok := v.(strictJSONOption); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// unmarshalStrict decodes the "payload" to the "dest" like the default `Unmarshal` does
+// but it fails on fields that the "dest" struct does not declare.
+func unmarshalStrict(payload []byte, dest interface{}) error {
+	dec := json.NewDecoder(bytes.NewReader(payload))
+	dec.UseNumber()
+	dec.DisallowUnknownFields()
+	return dec.Decode(dest)
+}
+
+// checkDuplicateKeys returns an ErrDuplicateJSONKey error
+// if any object of the JSON "data" holds the same key twice.
+// A non-JSON "data" is not reported, it's up to the caller to decode it.
+func checkDuplicateKeys(data []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+
+	err := scanDuplicateKeys(dec, 0)
+	if errors.Is(err, ErrDuplicateJSONKey) {
+		return err
+	}
+
+	return nil
+}
+
+func scanDuplicateKeys(dec *json.Decoder, depth int) error {
+	if depth > maxStrictJSONDepth {
+		return io.ErrUnexpectedEOF // treated as invalid JSON.
+	}
+
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+
+	delim, ok := tok.(json.Delim)
+	if !ok {
+		return nil // a scalar value.
+	}
+
+	switch delim {
+	case '{':
+		keys := make(map[string]struct{})
+		for dec.More() {
+			tok, err = dec.Token()
+			if err != nil {
+				return err
+			}
+
+			key, ok := tok.(string)
+			if !ok {
+				return io.ErrUnexpectedEOF
+			}
+
+			if _, exists := keys[key]; exists {
+				return fmt.Errorf("%w: %q", ErrDuplicateJSONKey, key)
+			}
+			keys[key] = struct{}{}
+
+			if err = scanDuplicateKeys(dec, depth+1); err != nil {
+				return err
+			}
+		}
+	case '[':
+		for dec.More() {
+			if err = scanDuplicateKeys(dec, depth+1); err != nil {
+				return err
+			}
+		}
+	}
+
+	_, err = dec.Token() // the closing delimiter.
+	return err
+}
@@ -0,0 +1,83 @@
+package jwt
+
+import (
+	"encoding/json"
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestStrictJSON(t *testing.T) {
+	exp := Clock().Add(time.Minute).Unix()
+
+	duplicateExp := []byte(`{"sub":"kataras","exp":1,"exp":` + strconv.FormatInt(exp, 10) + `}`)
+	token, err := Sign(testAlg, testSecret, duplicateExp)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// encoding/json keeps the last one.
+	if _, err = Verify(testAlg, testSecret, token); err != nil {
+		t.Fatalf("expected no error without StrictJSON but got: %v", err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, StrictJSON); !errors.Is(err, ErrDuplicateJSONKey) {
+		t.Fatalf("expected error: %v but got: %v", ErrDuplicateJSONKey, err)
+	}
+
+	nested := []byte(`{"sub":"kataras","roles":[{"name":"admin","name":"user"}]}`)
+	if token, err = Sign(testAlg, testSecret, nested); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, StrictJSON); !errors.Is(err, ErrDuplicateJSONKey) {
+		t.Fatalf("expected error on nested duplicate key: %v but got: %v", ErrDuplicateJSONKey, err)
+	}
+
+	duplicateAlg := json.RawMessage(`{"alg":"` + testAlg.Name() + `","typ":"JWT","alg":"` + testAlg.Name() + `"}`)
+	if token, err = SignWithHeader(testAlg, testSecret, Map{"sub": "kataras"}, duplicateAlg); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, StrictJSON); !errors.Is(err, ErrDuplicateJSONKey) {
+		t.Fatalf("expected error on duplicate header key: %v but got: %v", ErrDuplicateJSONKey, err)
+	}
+
+	// Unknown fields.
+	type userClaims struct {
+		Claims
+		Username string `json:"username"`
+	}
+
+	if token, err = Sign(testAlg, testSecret, Map{"username": "kataras"}, MaxAge(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	var claims userClaims
+	if err = VerifyToken(testAlg, testSecret, token, &claims, StrictJSON); err != nil {
+		t.Fatal(err)
+	}
+
+	if claims.Username != "kataras" || claims.Expiry == 0 {
+		t.Fatalf("unexpected claims: %#+v", claims)
+	}
+
+	if token, err = Sign(testAlg, testSecret, Map{"username": "kataras", "admin": true}, MaxAge(time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+
+	if err = VerifyToken(testAlg, testSecret, token, &userClaims{}); err != nil {
+		t.Fatalf("expected no error without StrictJSON but got: %v", err)
+	}
+
+	if err = VerifyToken(testAlg, testSecret, token, &userClaims{}, StrictJSON); err == nil {
+		t.Fatalf("expected an error on the unexpected extra claim")
+	}
+
+	// Maps accept any claim.
+	var m Map
+	if err = VerifyToken(testAlg, testSecret, token, &m, StrictJSON); err != nil {
+		t.Fatal(err)
+	}
+}
@@ -3,17 +3,32 @@ package jwt
 import (
 	"bytes"
 	"encoding/base64"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"strings"
+	"sync"
 )
 
 var (
-	// ErrMissing indicates that a given token to `Verify` is empty.
+	// ErrMissing indicates that a token is missing,
+	// e.g. the request does not hold one. See `ErrEmptyToken` too.
 	ErrMissing = errors.New("jwt: token is empty")
+	// ErrEmptyToken indicates that a given token to `Verify` or `Decode`
+	// is empty or it contains whitespace only. It is an ErrMissing too.
+	ErrEmptyToken = fmt.Errorf("%w: empty or whitespace only", ErrMissing)
 	// ErrTokenForm indicates that the extracted token has not the expected form .
 	ErrTokenForm = errors.New("jwt: invalid token form")
+	// ErrMalformedToken indicates that the token is not of the compact form:
+	// it has not exactly three parts, its header or payload is empty
+	// or a part is not base64url-encoded. It is an ErrTokenForm too.
+	ErrMalformedToken = fmt.Errorf("%w: malformed token", ErrTokenForm)
 	// ErrTokenAlg indicates that the given algorithm does not match the extracted one.
 	ErrTokenAlg = errors.New("jwt: unexpected token algorithm")
+	// ErrNoneAlgorithm indicates that the token is unsecured (its header's "alg" is "none")
+	// but the caller did not verify it using the `NONE` algorithm.
+	// It is an ErrTokenAlg too.
+	ErrNoneAlgorithm = fmt.Errorf("%w: none", ErrTokenAlg)
 )
 
 type (
@@ -24,6 +39,10 @@ type (
 )
 
 func encodeToken(alg Alg, key PrivateKey, payload []byte, customHeader interface{}) ([]byte, error) {
+	if err := checkAlgorithmPermitted(alg); err != nil {
+		return nil, err
+	}
+
 	var header []byte
 	if customHeader != nil {
 		h, err := createCustomHeader(customHeader)
@@ -35,17 +54,25 @@ func encodeToken(alg Alg, key PrivateKey, payload []byte, customHeader interface
 		header = createHeader(alg.Name())
 	}
 
-	payload = Base64Encode(payload)
+	// The signature is unknown at this point, so the signing input
+	// is encoded to a pooled buffer first and then copied to the token.
+	headerPayloadLen := len(header) + 1 + base64.RawURLEncoding.EncodedLen(len(payload))
+	buf := acquireSigningInput(headerPayloadLen)
+	defer releaseSigningInput(buf)
 
-	headerPayload := joinParts(header, payload)
+	headerPayload := *buf
+	encodeSigningInput(headerPayload, header, payload)
 
-	signature, err := createSignature(alg, key, headerPayload)
+	signature, err := alg.Sign(key, headerPayload)
 	if err != nil {
 		return nil, fmt.Errorf("encodeToken: signature: %w", err)
 	}
 
 	// header.payload.signature
-	token := joinParts(headerPayload, signature)
+	token := make([]byte, headerPayloadLen+1+base64.RawURLEncoding.EncodedLen(len(signature)))
+	copy(token, headerPayload)
+	token[headerPayloadLen] = '.'
+	base64.RawURLEncoding.Encode(token[headerPayloadLen+1:], signature)
 
 	return token, nil
 }
@@ -59,20 +86,41 @@ func encodeToken(alg Alg, key PrivateKey, payload []byte, customHeader interface
 // Decodes and verifies the given compact "token".
 // It returns the header, payoad and signature parts (decoded).
 func decodeToken(alg Alg, key PublicKey, token []byte, compareHeaderFunc HeaderValidator) ([]byte, []byte, []byte, error) {
+	return decodeTokenWith(Base64Decode, alg, key, token, compareHeaderFunc, nil, DefaultMinRSAKeyBits, nil)
+}
+
+// decodeTokenWith same as `decodeToken` but it decodes the parts with the given "decode" function,
+// see `LenientBase64`. The signature is always verified against the parts as they are.
+// RSA keys smaller than "minRSAKeyBits" are rejected before the signature is decoded.
+func decodeTokenWith(decode func([]byte) ([]byte, error), alg Alg, key PublicKey, token []byte, compareHeaderFunc HeaderValidator, understoodCrit []string, minRSAKeyBits int, trace *verifyTrace) ([]byte, []byte, []byte, error) {
 	parts := bytes.Split(token, sep)
 	if len(parts) != 3 {
-		return nil, nil, nil, ErrTokenForm
+		return nil, nil, nil, ErrMalformedToken
 	}
 
 	header := parts[0]
 	payload := parts[1]
 	signature := parts[2]
 
-	headerDecoded, err := Base64Decode(header)
+	if len(header) == 0 || len(payload) == 0 {
+		return nil, nil, nil, ErrMalformedToken
+	}
+
+	headerDecoded, err := decode(header)
 	if err != nil {
+		return nil, nil, nil, segmentError("header", 0, err)
+	}
+
+	if err = checkHeaderLimits(headerDecoded); err != nil {
 		return nil, nil, nil, err
 	}
 
+	if err = checkEncodedPayload(headerDecoded, understoodCrit); err != nil {
+		return nil, nil, nil, err
+	}
+
+	trace.reach(stepAlg)
+
 	// validate header equality.
 	if compareHeaderFunc == nil {
 		compareHeaderFunc = CompareHeader
@@ -87,11 +135,23 @@ func decodeToken(alg Alg, key PublicKey, token []byte, compareHeaderFunc HeaderV
 
 	dynamicAlg, pubKey, decrypt, err := compareHeaderFunc(algName, headerDecoded)
 	if err != nil {
+		if alg != NONE && errors.Is(err, ErrTokenAlg) && isNoneHeader(headerDecoded) {
+			// A well-known attack, the signature is stripped and the "alg" is set to "none".
+			return nil, nil, nil, ErrNoneAlgorithm
+		}
+
 		return nil, nil, nil, err
 	}
 
 	if alg == nil {
 		alg = dynamicAlg
+	} else if alg != NONE && dynamicAlg == NONE {
+		// The unsecured JWTs are allowed only when the caller explicitly asks for them.
+		return nil, nil, nil, ErrNoneAlgorithm
+	}
+
+	if err = checkAlgorithmPermitted(alg); err != nil {
+		return nil, nil, nil, err
 	}
 
 	// Override the key given, which could be a nil if this "pubKey" always expected on success.
@@ -99,21 +159,35 @@ func decodeToken(alg Alg, key PublicKey, token []byte, compareHeaderFunc HeaderV
 		key = pubKey
 	}
 
-	signatureDecoded, err := Base64Decode(signature)
-	if err != nil {
+	trace.reach(stepSignature)
+
+	if err = checkRSAKeySize(key, minRSAKeyBits); err != nil {
 		return nil, nil, nil, err
 	}
+
+	signatureDecoded, err := decode(signature)
+	if err != nil {
+		return nil, nil, nil, segmentError("signature", len(header)+len(payload)+2, err)
+	}
+
+	payloadDecoded, err := decode(payload)
+	if err != nil {
+		return nil, nil, nil, segmentError("payload", len(header)+1, err)
+	}
+
 	// validate signature.
-	headerPayload := joinParts(header, payload)
-	if err := alg.Verify(key, headerPayload, signatureDecoded); err != nil {
+	if err = checkSignatureSize(alg, key, signatureDecoded); err != nil {
 		return nil, nil, nil, err
 	}
 
-	payload, err = Base64Decode(payload)
-	if err != nil {
+	headerPayload := joinParts(header, payload)
+	if err := alg.Verify(key, headerPayload, signatureDecoded); err != nil {
 		return nil, nil, nil, err
 	}
 
+	payload = payloadDecoded
+	trace.reach(stepPayload)
+
 	if decrypt != nil {
 		payload, err = decrypt(payload)
 		if err != nil {
@@ -124,6 +198,124 @@ func decodeToken(alg Alg, key PublicKey, token []byte, compareHeaderFunc HeaderV
 	return headerDecoded, payload, signatureDecoded, nil
 }
 
+// SigningInput returns the exact input of the signing algorithm
+// for the given header and payload (both not encoded):
+// base64url(header) + "." + base64url(payload).
+// The `Sign` and `Verify` functions use the same input.
+//
+// Useful for detached signatures (RFC 7515, Appendix F),
+// where the payload is transmitted separately. See `VerifyDetached`.
+func SigningInput(header, payload []byte) []byte {
+	encodedHeader := Base64Encode(header)
+	signingInput := make([]byte, len(encodedHeader)+1+base64.RawURLEncoding.EncodedLen(len(payload)))
+	encodeSigningInput(signingInput, encodedHeader, payload)
+	return signingInput
+}
+
+// encodeSigningInput writes the already encoded header,
+// the separator and the base64url-encoded payload to "dst".
+// The "dst" must be large enough to hold them.
+func encodeSigningInput(dst, encodedHeader, payload []byte) {
+	n := copy(dst, encodedHeader)
+	dst[n] = '.'
+	base64.RawURLEncoding.Encode(dst[n+1:], payload)
+}
+
+// VerifyDetached verifies the (decoded) "signature" of the given "signingInput",
+// e.g. the result of `SigningInput` or the first two parts of a compact token.
+// The header's "alg" must match the given algorithm, like `Verify` does,
+// and RSA keys smaller than the `DefaultMinRSAKeyBits` are rejected.
+// Note that it does NOT validate any claims,
+// the payload may not be a JSON at all.
+//
+// Example Code:
+//
+//  signingInput := jwt.SigningInput(header, payload)
+//  err := jwt.VerifyDetached(jwt.EdDSA, publicKey, signingInput, signature)
+func VerifyDetached(alg Alg, key PublicKey, signingInput, signature []byte) error {
+	if len(signingInput) == 0 {
+		return ErrMissing
+	}
+
+	if alg == nil {
+		return ErrTokenAlg
+	}
+
+	if err := checkAlgorithmPermitted(alg); err != nil {
+		return err
+	}
+
+	idx := bytes.IndexByte(signingInput, '.')
+	if idx <= 0 || bytes.Count(signingInput, sep) != 1 {
+		return ErrMalformedToken
+	}
+
+	headerDecoded, err := Base64Decode(signingInput[:idx])
+	if err != nil {
+		return segmentError("header", 0, err)
+	}
+
+	if err = checkEncodedPayload(headerDecoded, nil); err != nil {
+		return err
+	}
+
+	if _, err = Base64Decode(signingInput[idx+1:]); err != nil {
+		return segmentError("payload", idx+1, err)
+	}
+
+	if _, _, _, err = CompareHeader(alg.Name(), headerDecoded); err != nil {
+		if alg != NONE && errors.Is(err, ErrTokenAlg) && isNoneHeader(headerDecoded) {
+			return ErrNoneAlgorithm
+		}
+
+		return err
+	}
+
+	if err = checkRSAKeySize(key, DefaultMinRSAKeyBits); err != nil {
+		return err
+	}
+
+	if err = checkSignatureSize(alg, key, signature); err != nil {
+		return err
+	}
+
+	return alg.Verify(key, signingInput, signature)
+}
+
+var signingInputPool = sync.Pool{
+	New: func() interface{} {
+		b := make([]byte, 0, 512)
+		return &b
+	},
+}
+
+// acquireSigningInput returns a pooled buffer of "n" length.
+func acquireSigningInput(n int) *[]byte {
+	buf := signingInputPool.Get().(*[]byte)
+	if cap(*buf) < n {
+		*buf = make([]byte, n)
+	}
+
+	*buf = (*buf)[:n]
+	return buf
+}
+
+// releaseSigningInput zeroes the buffer, so the next token
+// can never contain any bytes of the previous one, and puts it back to the pool.
+func releaseSigningInput(buf *[]byte) {
+	b := *buf
+	for i := range b {
+		b[i] = 0
+	}
+
+	if cap(b) > 64<<10 { // don't hold large buffers.
+		return
+	}
+
+	*buf = b[:0]
+	signingInputPool.Put(buf)
+}
+
 var (
 	sep    = []byte(".")
 	pad    = []byte("=")
@@ -221,7 +413,7 @@ func compareHeader(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc
 			}
 		}
 
-		return nil, nil, nil, ErrTokenAlg
+		return compareHeaderFields(alg, headerDecoded)
 	}
 
 	// Fast check if the order is reversed.
@@ -231,7 +423,7 @@ func compareHeader(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc
 	if headerDecoded[2] == 't' {
 		expectedHeader := createHeaderReversed(alg)
 		if !bytes.Equal(expectedHeader, headerDecoded) {
-			return nil, nil, nil, ErrTokenAlg
+			return compareHeaderFields(alg, headerDecoded)
 		}
 
 		return nil, nil, nil, nil
@@ -239,18 +431,86 @@ func compareHeader(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc
 
 	expectedHeader := createHeaderRaw(alg)
 	if !bytes.Equal(expectedHeader, headerDecoded) {
+		return compareHeaderFields(alg, headerDecoded)
+	}
+
+	return nil, nil, nil, nil
+}
+
+// compareHeaderFields is the slow path of the compareHeader,
+// it's called when the header contains more fields (e.g. "kid")
+// or it's not in the form this package generates.
+// The "alg" field (case-sensitive) should match the expected "alg".
+// The "crit" field is validated by the verification itself, see `UnderstoodCrit`.
+func compareHeaderFields(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(headerDecoded, &fields); err != nil {
+		return nil, nil, nil, ErrTokenAlg
+	}
+
+	var headerAlg string
+	if err := json.Unmarshal(fields["alg"], &headerAlg); err != nil || alg == "" || headerAlg != alg {
 		return nil, nil, nil, ErrTokenAlg
 	}
 
 	return nil, nil, nil, nil
 }
 
-func createSignature(alg Alg, key PrivateKey, headerAndPayload []byte) ([]byte, error) {
-	signature, err := alg.Sign(key, headerAndPayload)
-	if err != nil {
-		return nil, err
+// AllowedAlgorithms returns a HeaderValidator which accepts
+// tokens signed with one of the given "algs" only.
+// The algorithm is resolved by the token's header "alg" field,
+// a token with any other algorithm fails with ErrUnexpectedAlgorithm (an ErrTokenAlg too)
+// before the key is used at all and a header which is not a JSON object
+// fails with ErrMalformedHeader (an ErrTokenForm too).
+// That way the well-known algorithm confusion attack
+// (e.g. an HS256 token signed using the EdDSA public key as the HMAC secret) is prevented.
+//
+// Useful when the same key verifies tokens of more than one algorithm,
+// e.g. on migration from RS256 to PS256.
+//
+// Usage:
+//  verifiedToken, err := jwt.VerifyWithHeaderValidator(nil, publicKey, token, jwt.AllowedAlgorithms(jwt.RS256, jwt.PS256))
+func AllowedAlgorithms(algs ...Alg) HeaderValidator {
+	allowed := make(map[string]Alg, len(algs))
+	for _, alg := range algs {
+		allowed[alg.Name()] = alg
+	}
+
+	return func(alg string, headerDecoded []byte) (Alg, PublicKey, InjectFunc, error) {
+		var h struct {
+			Alg string `json:"alg"`
+		}
+
+		if err := json.Unmarshal(headerDecoded, &h); err != nil {
+			return nil, nil, nil, fmt.Errorf("%w: %v", ErrMalformedHeader, err)
+		}
+
+		tokenAlg, ok := allowed[h.Alg]
+		if !ok {
+			return nil, nil, nil, ErrUnexpectedAlgorithm
+		}
+
+		// If a specific alg was given by the caller then check that as well.
+		if alg != "" && alg != h.Alg {
+			return nil, nil, nil, ErrUnexpectedAlgorithm
+		}
+
+		return tokenAlg, nil, nil, nil
 	}
-	return Base64Encode(signature), nil
+}
+
+// isNoneHeader reports whether the decoded header's "alg" field is "none" (case-insensitive).
+// It's called on failures only, so the fast header comparison is not affected.
+func isNoneHeader(headerDecoded []byte) bool {
+	var h struct {
+		Alg string `json:"alg"`
+	}
+
+	if err := json.Unmarshal(headerDecoded, &h); err != nil {
+		return false
+	}
+
+	return strings.EqualFold(h.Alg, NONE.Name())
 }
 
 // Base64Encode encodes "src" to jwt base64 url format.
@@ -262,20 +522,43 @@ func Base64Encode(src []byte) []byte {
 	return bytes.TrimRight(buf, padStr) // JWT: no trailing '='.
 }
 
+// segmentError wraps the base64 decoding error of a token's segment
+// ("header", "payload" or "signature") to an ErrMalformedToken error which names the segment,
+// e.g. "jwt: invalid token form: malformed token: payload segment: illegal base64 data at input byte 5 (token byte 42)".
+// The "offset" is the position of the segment in the token.
+func segmentError(segment string, offset int, err error) error {
+	var corruptErr base64.CorruptInputError
+	if errors.As(err, &corruptErr) {
+		return fmt.Errorf("%w: %s segment: %v (token byte %d)", ErrMalformedToken, segment, err, offset+int(corruptErr))
+	}
+
+	return fmt.Errorf("%w: %s segment: %v", ErrMalformedToken, segment, err)
+}
+
 // Base64Decode decodes "src" to jwt base64 url format.
-// We could use the base64.RawURLEncoding but the below is a bit faster.
+// The "src" should be base64url-encoded without padding (RFC 7515, section 2),
+// any standard base64 and padding characters, line breaks or
+// non-zero trailing bits result to a base64.CorruptInputError
+// instead of being silently accepted.
 func Base64Decode(src []byte) ([]byte, error) {
-	if n := len(src) % 4; n > 0 {
-		// JWT: Because of no trailing '=' let's suffix it
-		// with the correct number of those '=' before decoding.
-		src = append(src, bytes.Repeat(pad, 4-n)...)
+	if i := bytes.IndexAny(src, "\r\n"); i >= 0 { // the base64 decoder ignores them.
+		return nil, base64.CorruptInputError(i)
 	}
 
-	buf := make([]byte, base64.URLEncoding.DecodedLen(len(src)))
-	n, err := base64.URLEncoding.Decode(buf, src)
+	buf := make([]byte, base64Strict.DecodedLen(len(src)))
+	n, err := base64Strict.Decode(buf, src)
 	return buf[:n], err
 }
 
+var base64Strict = base64.RawURLEncoding.Strict()
+
+// trimToken removes the surrounding ASCII whitespace of the "token",
+// e.g. a trailing new line of a file or a space after the "Bearer" scheme.
+// A whitespace-only token results to an empty one, which fails with ErrEmptyToken.
+func trimToken(token []byte) []byte {
+	return bytes.Trim(token, " \t\r\n\v\f")
+}
+
 // Decode decodes the token of compact form WITHOUT verification and validation.
 //
 // This function is only useful to read a token's claims
@@ -283,39 +566,114 @@ func Base64Decode(src []byte) ([]byte, error) {
 // content validation is required.
 //
 // Use `Verify/VerifyEncrypted` functions instead.
+// See `UnsafeDecode` too.
 func Decode(token []byte) (*UnverifiedToken, error) {
+	token = trimToken(token)
+	if len(token) == 0 {
+		return nil, ErrEmptyToken
+	}
+
 	parts := bytes.Split(token, sep)
 	if len(parts) != 3 {
-		return nil, ErrTokenForm
+		return nil, ErrMalformedToken
 	}
 
 	header := parts[0]
 	payload := parts[1]
 	signature := parts[2]
 
+	if len(header) == 0 || len(payload) == 0 {
+		return nil, ErrMalformedToken
+	}
+
 	headerDecoded, err := Base64Decode(header)
 	if err != nil {
-		return nil, err
+		return nil, segmentError("header", 0, err)
 	}
 
 	signatureDecoded, err := Base64Decode(signature)
 	if err != nil {
-		return nil, err
+		return nil, segmentError("signature", len(header)+len(payload)+2, err)
 	}
 
-	payload, err = Base64Decode(payload)
+	payloadDecoded, err := Base64Decode(payload)
 	if err != nil {
-		return nil, err
+		return nil, segmentError("payload", len(header)+1, err)
 	}
 
 	tok := &UnverifiedToken{
 		Header:    headerDecoded,
-		Payload:   payload,
+		Payload:   payloadDecoded,
 		Signature: signatureDecoded,
 	}
 	return tok, nil
 }
 
+// UnsafeDecode decodes the header and the payload of the token of compact form
+// WITHOUT verifying its signature or validating its claims.
+//
+// WARNING: the returned header and claims can be forged by anyone,
+// NEVER trust them for authorization or any other security decision.
+// It's only useful for routing and diagnostics, e.g. to select
+// the verification key by the "iss" claim, then call `Verify` with that key.
+//
+// It returns ErrMalformedToken when the token is malformed
+// and an error when the header or the payload is not a JSON object.
+func UnsafeDecode(token []byte) (Header, json.RawMessage, error) {
+	tok, err := Decode(token)
+	if err != nil {
+		return Header{}, nil, err
+	}
+
+	var header Header
+	if err = Unmarshal(tok.Header, &header); err != nil {
+		return Header{}, nil, fmt.Errorf("%w: header: %v", ErrTokenForm, err)
+	}
+
+	zip, _ := header.Get("zip").(string)
+	payload, err := inflate(zip, tok.Payload)
+	if err != nil {
+		return Header{}, nil, err
+	}
+
+	if !json.Valid(payload) {
+		return Header{}, nil, errPayloadNotJSON
+	}
+
+	return header, json.RawMessage(payload), nil
+}
+
+// DecodeSegments splits and base64url-decodes the three parts of the token of compact form
+// WITHOUT verifying its signature or validating its claims, no cryptographic operation is performed.
+// It's only useful for introspection, e.g. a "decode" command line tool or a debug endpoint,
+// NEVER trust its results for authorization or any other security decision.
+//
+// It returns ErrMalformedToken when the token is malformed (e.g. not three parts)
+// and ErrTokenForm when its header is not a JSON object. The payload is returned as it is,
+// e.g. it may be compressed (see `WithCompression`) or not a JSON at all (see `Plain`).
+//
+// Usage:
+//  header, payload, signature, err := jwt.DecodeSegments(token)
+//  fmt.Printf("%s\n%s\n%x\n", header, payload, signature)
+func DecodeSegments(token []byte) (headerJSON, payloadJSON json.RawMessage, signature []byte, err error) {
+	tok, err := Decode(token)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+
+	if !isJSONObject(tok.Header) {
+		return nil, nil, nil, fmt.Errorf("%w: header: not a JSON object", ErrTokenForm)
+	}
+
+	return json.RawMessage(tok.Header), json.RawMessage(tok.Payload), tok.Signature, nil
+}
+
+// isJSONObject reports whether "b" is a valid JSON object.
+func isJSONObject(b []byte) bool {
+	b = bytes.TrimSpace(b)
+	return len(b) > 0 && b[0] == '{' && json.Valid(b)
+}
+
 // UnverifiedToken contains the compact form token parts.
 // Look its `Claims` method to decode to a custom structure.
 type UnverifiedToken struct {
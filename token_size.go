@@ -0,0 +1,46 @@
+package jwt
+
+import "fmt"
+
+// ErrTokenTooLarge indicates that the token exceeds the maximum size
+// of the `MaxTokenBytes` TokenValidator. It is an ErrTokenForm too.
+var ErrTokenTooLarge = fmt.Errorf("%w: token too large", ErrTokenForm)
+
+// DefaultMiddlewareMaxTokenBytes is the default maximum size of a token
+// the `Middleware` and `MiddlewareWithExtractor` accept,
+// pass a `MaxTokenBytes` validator to modify it.
+const DefaultMiddlewareMaxTokenBytes = 8 << 10 // 8KiB.
+
+type maxTokenBytesOption int
+
+// ValidateToken completes the TokenValidator interface.
+// It respects the previous error.
+func (maxTokenBytesOption) ValidateToken(_ []byte, _ Claims, err error) error {
+	return err
+}
+
+// MaxTokenBytes is a TokenValidator which rejects tokens larger than "n" bytes
+// (the length of the compact serialization) with an ErrTokenTooLarge error,
+// before any decoding happens, so an untrusted client can not force
+// the base64 decoding and JSON parsing of a multi-megabyte "token".
+// A zero or negative "n" disables the limit.
+// When more than one is given, the last one is used.
+//
+// Usage:
+//  verifiedToken, err := jwt.Verify(jwt.EdDSA, publicKey, token, jwt.MaxTokenBytes(4096))
+func MaxTokenBytes(n int) TokenValidator {
+	return maxTokenBytesOption(n)
+}
+
+// maxTokenBytes returns the limit of the last `MaxTokenBytes` of the "validators",
+// zero when no limit is set.
+func maxTokenBytes(validators []TokenValidator) int {
+	n := 0
+	for _, v := range validators {
+		if limit, ok := v.(maxTokenBytesOption); ok {
+			n = int(limit)
+		}
+	}
+
+	return n
+}
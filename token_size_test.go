@@ -0,0 +1,48 @@
+package jwt
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestMaxTokenBytes(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	size := len(token)
+
+	var tests = []struct {
+		validators []TokenValidator
+		wantErr    error
+	}{
+		{[]TokenValidator{MaxTokenBytes(size)}, nil},
+		{[]TokenValidator{MaxTokenBytes(size + 1)}, nil},
+		{[]TokenValidator{MaxTokenBytes(size - 1)}, ErrTokenTooLarge},
+		{[]TokenValidator{MaxTokenBytes(0)}, nil},
+		{[]TokenValidator{MaxTokenBytes(size - 1), MaxTokenBytes(size)}, nil}, // the last one is used.
+		{[]TokenValidator{MaxTokenBytes(size), MaxTokenBytes(size - 1)}, ErrTokenTooLarge},
+	}
+
+	for i, tt := range tests {
+		if _, err = Verify(testAlg, testSecret, token, tt.validators...); err != tt.wantErr {
+			t.Fatalf("[%d] expected error: %v but got: %v", i, tt.wantErr, err)
+		}
+	}
+
+	if !errors.Is(ErrTokenTooLarge, ErrTokenForm) {
+		t.Fatalf("expected ErrTokenTooLarge to be an ErrTokenForm")
+	}
+
+	// Surrounding whitespace is not part of the compact serialization.
+	padded := append(append([]byte(" "), token...), '\n')
+	if _, err = Verify(testAlg, testSecret, padded, MaxTokenBytes(size)); err != nil {
+		t.Fatal(err)
+	}
+
+	// It's checked before decoding, a malformed token over the limit fails with ErrTokenTooLarge.
+	if _, err = Verify(testAlg, testSecret, append(token[:size:size], "!!!"...), MaxTokenBytes(size)); err != ErrTokenTooLarge {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenTooLarge, err)
+	}
+}
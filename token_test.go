@@ -2,8 +2,11 @@ package jwt
 
 import (
 	"bytes"
+	"encoding/base64"
 	"encoding/json"
 	"errors"
+	"fmt"
+	"strings"
 	"testing"
 )
 
@@ -44,7 +47,7 @@ func testEncodeDecodeToken(t *testing.T, alg Alg, signKey PrivateKey, verKey Pub
 
 	// Test invalid signature.
 	lastPartIdx := bytes.LastIndexByte(token, '.') + 1
-	unexpectedSignature := []byte("DX22uANEy1qEG0m0utEW4YYfyNeuG9FzvRPMxpSaTc")
+	unexpectedSignature := []byte("DX22uANEy1qEG0m0utEW4YYfyNeuG9FzvRPMxpSaTQ") // canonical encoding, no trailing bits.
 	unexpectedSignatureToken := make([]byte, len(token[0:lastPartIdx])+len(unexpectedSignature))
 	copy(unexpectedSignatureToken, token[0:lastPartIdx])
 	copy(unexpectedSignatureToken[len(token[0:lastPartIdx]):], unexpectedSignature)
@@ -52,6 +55,22 @@ func testEncodeDecodeToken(t *testing.T, alg Alg, signKey PrivateKey, verKey Pub
 		t.Fatalf("[%s] decode token: expected error: ErrTokenSignature but got: %v", alg.Name(), err)
 	}
 
+	if alg != NONE { // test truncated signature error for all algorithms.
+		signature, err := Base64Decode(token[lastPartIdx:])
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		truncatedSignatureToken := append(append([]byte(nil), token[0:lastPartIdx]...), Base64Encode(signature[:len(signature)-1])...)
+		if _, _, _, err = decodeToken(alg, verKey, truncatedSignatureToken, nil); !errors.Is(err, ErrInvalidSignatureLength) {
+			t.Fatalf("[%s] decode token: expected error: ErrInvalidSignatureLength but got: %v", alg.Name(), err)
+		}
+
+		if !errors.Is(err, ErrTokenSignature) {
+			t.Fatalf("[%s] decode token: expected ErrInvalidSignatureLength to be an ErrTokenSignature", alg.Name())
+		}
+	}
+
 	if alg != NONE { // test invalid key error for all algorithms.
 		if _, _, _, err := decodeToken(alg, invalidKey, token, nil); !errors.Is(err, ErrInvalidKey) {
 			t.Fatalf("[%s] decode token: expected error: ErrInvalidKey but got: %v: %q", alg.Name(), err, token)
@@ -90,6 +109,11 @@ func TestCompareHeader(t *testing.T) {
 		{HS256.Name(), "", false},
 		{HS256.Name(), `{"alg":"HS256","typ":"JWT`, false},
 		{HS256.Name(), `{"typ":"JWT","ALG":"HS256"}`, false},
+		{HS256.Name(), `{"alg":"HS256","typ":"JWT","kid":"api"}`, true},
+		{HS256.Name(), `{"kid":"api","alg":"HS256"}`, true},
+		{HS256.Name(), `{"kid":"api","alg":"RS256"}`, false},
+		{HS256.Name(), `{"kid":"api","ALG":"HS256"}`, false},
+		{HS256.Name(), `{"alg":"HS256","crit":["exp"],"exp":1}`, true}, // the "crit" is validated by the verification, see TestUnderstoodCrit.
 	}
 
 	for i, tt := range tests {
@@ -104,6 +128,148 @@ func TestCompareHeader(t *testing.T) {
 	}
 }
 
+func TestAllowedAlgorithms(t *testing.T) {
+	privateKey, publicKey, err := GenerateEdDSA()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	claims := Map{"username": "kataras"}
+	allowed := AllowedAlgorithms(EdDSA)
+
+	token, err := Sign(EdDSA, privateKey, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyWithHeaderValidator(nil, publicKey, token, allowed); err != nil {
+		t.Fatalf("expected to pass but got error: %v", err)
+	}
+
+	// The attacker signs an HS256 token using the (known) EdDSA public key as the HMAC secret.
+	forged, err := Sign(HS256, []byte(publicKey), claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyWithHeaderValidator(nil, []byte(publicKey), forged, allowed); !errors.Is(err, ErrUnexpectedAlgorithm) || !errors.Is(err, ErrTokenAlg) {
+		t.Fatalf("expected error: %v but got: %v", ErrUnexpectedAlgorithm, err)
+	}
+
+	// Test more than one allowed algorithms with the same key.
+	secret := []byte("secret")
+	allowed = AllowedAlgorithms(HS256, HS512)
+	for _, alg := range []Alg{HS256, HS512} {
+		token, err = Sign(alg, secret, claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if _, err = VerifyWithHeaderValidator(nil, secret, token, allowed); err != nil {
+			t.Fatalf("[%s] expected to pass but got error: %v", alg.Name(), err)
+		}
+	}
+
+	token, err = Sign(HS384, secret, claims)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyWithHeaderValidator(nil, secret, token, allowed); !errors.Is(err, ErrUnexpectedAlgorithm) || !errors.Is(err, ErrTokenAlg) {
+		t.Fatalf("expected error: %v but got: %v", ErrUnexpectedAlgorithm, err)
+	}
+
+	// A header which is not a JSON object is an invalid token form, not an unexpected algorithm.
+	malformed := append([]byte(base64.RawURLEncoding.EncodeToString([]byte(`["HS256"]`))), token[bytes.IndexByte(token, '.'):]...)
+	if _, err = VerifyWithHeaderValidator(nil, secret, malformed, allowed); !errors.Is(err, ErrMalformedHeader) || !errors.Is(err, ErrTokenForm) {
+		t.Fatalf("expected error: %v but got: %v", ErrMalformedHeader, err)
+	}
+}
+
+func TestEncodeTokenPooledBuffer(t *testing.T) {
+	long := Map{"username": string(bytes.Repeat([]byte("kataras"), 100))}
+	short := Map{"username": "kataras"}
+
+	expected, err := Sign(testAlg, testSecret, short)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for i := 0; i < 10; i++ {
+		if _, err = Sign(testAlg, testSecret, long); err != nil {
+			t.Fatal(err)
+		}
+
+		got, err := Sign(testAlg, testSecret, short)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(expected, got) {
+			t.Fatalf("expected token:\n%s\n\nbut got:\n%s", expected, got)
+		}
+	}
+}
+
+func TestDecodeTokenMalformed(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := bytes.Split(token, sep)
+	header, payload, signature := string(parts[0]), string(parts[1]), string(parts[2])
+
+	var tests = []string{
+		"",
+		"..",
+		header,
+		header + "." + payload,
+		header + "." + payload + "." + signature + ".",
+		header + ".." + payload + "." + signature,
+		"." + payload + "." + signature,
+		header + ".." + signature,
+		header + "=." + payload + "." + signature,
+		header + "." + payload + "==." + signature,
+		header + "." + payload + "." + signature + "=",
+		header + "\n." + payload + "." + signature,
+		header + "." + payload + "." + signature[:10] + "\r\n" + signature[10:],
+	}
+
+	// Replace each character with the standard base64 ones.
+	for i := range token {
+		if token[i] == '.' {
+			continue
+		}
+
+		for _, c := range []byte("+/=") {
+			malformed := append([]byte{}, token...)
+			malformed[i] = c
+			tests = append(tests, string(malformed))
+		}
+	}
+
+	for i, tt := range tests {
+		_, _, _, err = decodeToken(testAlg, testSecret, []byte(tt), nil)
+		if !errors.Is(err, ErrMalformedToken) || !errors.Is(err, ErrTokenForm) {
+			t.Fatalf("[%d] %q: expected error: %v but got: %v", i, tt, ErrMalformedToken, err)
+		}
+	}
+
+	// Test non-zero trailing bits, the same signature can't be encoded differently.
+	last := signature[len(signature)-1]
+	for _, c := range []byte("ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789-_") {
+		if c == last {
+			continue
+		}
+
+		malformed := header + "." + payload + "." + signature[:len(signature)-1] + string(c)
+		if _, _, _, err = decodeToken(testAlg, testSecret, []byte(malformed), nil); err == nil {
+			t.Fatalf("%q: expected to fail", malformed)
+		}
+	}
+}
+
 func TestDecodeWithoutVerify(t *testing.T) {
 	input := testToken
 	tok, err := Decode(input)
@@ -155,3 +321,151 @@ func compareMap(m1, m2 map[string]interface{}) bool {
 
 	return true
 }
+
+func TestVerifyDetached(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"foo": "bar"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tok, err := Decode(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	signingInput := SigningInput(tok.Header, tok.Payload)
+	if expected := token[:bytes.LastIndexByte(token, '.')]; !bytes.Equal(expected, signingInput) {
+		t.Fatalf("expected signing input: %s but got: %s", expected, signingInput)
+	}
+
+	if err = VerifyDetached(testAlg, testSecret, signingInput, tok.Signature); err != nil {
+		t.Fatal(err)
+	}
+
+	tampered := SigningInput(tok.Header, []byte(`{"foo":"baz"}`))
+	if err = VerifyDetached(testAlg, testSecret, tampered, tok.Signature); err != ErrTokenSignature {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+
+	if err = VerifyDetached(testAlg, testSecret, signingInput, tok.Signature[:16]); !errors.Is(err, ErrInvalidSignatureLength) {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidSignatureLength, err)
+	}
+
+	if err = VerifyDetached(HS512, testSecret, signingInput, tok.Signature); !errors.Is(err, ErrTokenAlg) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenAlg, err)
+	}
+
+	if err = VerifyDetached(testAlg, testSecret, token, tok.Signature); err != ErrMalformedToken {
+		t.Fatalf("expected error: %v but got: %v", ErrMalformedToken, err)
+	}
+}
+
+func TestDecodeSegments(t *testing.T) {
+	privateKey, publicKey := MustLoadEdDSA("./_testfiles/ed25519_private_key.pem", "./_testfiles/ed25519_public_key.pem")
+	token, err := Sign(EdDSA, privateKey, Map{"username": "kataras"}, WithKid("api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, payload, signature, err := DecodeSegments(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected := `{"alg":"EdDSA","typ":"JWT","kid":"api"}`; string(header) != expected {
+		t.Fatalf("expected header: %s but got: %s", expected, header)
+	}
+
+	if expected := `{"username":"kataras"}`; string(payload) != expected {
+		t.Fatalf("expected payload: %s but got: %s", expected, payload)
+	}
+
+	// The raw signature, the one the EdDSA verifies.
+	if err = VerifyDetached(EdDSA, publicKey, token[:bytes.LastIndexByte(token, '.')], signature); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, malformed := range []string{
+		"",
+		"header.payload",
+		string(token) + ".extra",
+		"e30.e30.!", // invalid base64url signature.
+		string(Base64Encode([]byte("not json"))) + ".e30.", // the header is not a JSON object.
+	} {
+		if _, _, _, err = DecodeSegments([]byte(malformed)); err == nil {
+			t.Fatalf("%q: expected an error", malformed)
+		}
+	}
+}
+
+func TestUnsafeDecode(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"iss": "tenant-1"}, WithKid("api"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, payload, err := UnsafeDecode(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if header.Alg != testAlg.Name() || header.Kid != "api" {
+		t.Fatalf("unexpected header: %#+v", header)
+	}
+
+	if expected, got := `{"iss":"tenant-1"}`, string(payload); expected != got {
+		t.Fatalf("expected payload: %s but got: %s", expected, got)
+	}
+
+	// The signature is not verified.
+	forged := append(token[:bytes.LastIndexByte(token, '.')+1:bytes.LastIndexByte(token, '.')+1], "c2lnbmF0dXJl"...)
+	if _, _, err = UnsafeDecode(forged); err != nil {
+		t.Fatalf("expected no error on forged signature but got: %v", err)
+	}
+
+	for _, malformed := range []string{"", "a.b", "a.b.c.d", ".e30.", "e30..", "e30.e30=.", "e30.bm90IGpzb24."} {
+		if _, _, err = UnsafeDecode([]byte(malformed)); err == nil {
+			t.Fatalf("[%s] expected error on malformed token", malformed)
+		}
+	}
+}
+
+func TestSegmentErrors(t *testing.T) {
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	parts := bytes.Split(token, sep)
+	headerLen, payloadLen := len(parts[0]), len(parts[1])
+
+	corrupt := func(at int) []byte {
+		garbled := append([]byte(nil), token...)
+		garbled[at] = '!'
+		return garbled
+	}
+
+	var tests = []struct {
+		token    []byte
+		expected string
+	}{
+		{corrupt(3), "header segment: illegal base64 data at input byte 3 (token byte 3)"},
+		{corrupt(headerLen + 1 + 5), fmt.Sprintf("payload segment: illegal base64 data at input byte 5 (token byte %d)", headerLen+1+5)},
+		{corrupt(headerLen + payloadLen + 2), fmt.Sprintf("signature segment: illegal base64 data at input byte 0 (token byte %d)", headerLen+payloadLen+2)},
+	}
+
+	for i, tt := range tests {
+		_, verifyErr := Verify(testAlg, testSecret, tt.token)
+		_, decodeErr := Decode(tt.token)
+
+		for _, err := range []error{verifyErr, decodeErr} {
+			if !errors.Is(err, ErrMalformedToken) || !errors.Is(err, ErrTokenForm) {
+				t.Fatalf("[%d] expected error: %v but got: %v", i, ErrMalformedToken, err)
+			}
+
+			if !strings.Contains(err.Error(), tt.expected) {
+				t.Fatalf("[%d] expected error to contain: %q but got: %q", i, tt.expected, err.Error())
+			}
+		}
+	}
+}
@@ -1,6 +1,11 @@
 package jwt
 
-import "encoding/json"
+import (
+	"bytes"
+	"encoding/json"
+	"strconv"
+	"time"
+)
 
 // TokenPair holds the access token and refresh token response.
 type TokenPair struct {
@@ -18,6 +23,138 @@ func NewTokenPair(accessToken, refreshToken []byte) TokenPair {
 	}
 }
 
+// RefreshTokenType is the "typ" header field of the refresh tokens
+// generated by `SignTokenPair`. It makes sure that
+// a refresh token can not be used as an access token and vice versa:
+// every verification, except the `VerifyRefreshToken` and `VerifyTokenPair` ones,
+// rejects a token of that type with an ErrInvalidType error.
+const RefreshTokenType = "refresh+jwt"
+
+// SignTokenPair generates an access and a refresh token of the same "claims"
+// which expire after "accessMaxAge" and "refreshMaxAge" respectively.
+// The refresh token carries the `RefreshTokenType` as its "typ" header field.
+//
+// Verify the access token with `VerifyAccessToken`
+// and the refresh token with `VerifyRefreshToken`.
+//
+// Usage:
+//  tokenPair, err := jwt.SignTokenPair(jwt.EdDSA, privateKey, userClaims, 15*time.Minute, 24*time.Hour)
+func SignTokenPair(alg Alg, key PrivateKey, claims interface{}, accessMaxAge, refreshMaxAge time.Duration) (TokenPair, error) {
+	accessToken, err := Sign(alg, key, claims, MaxAge(accessMaxAge))
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	refreshHeader := Header{Alg: alg.Name(), Typ: RefreshTokenType}
+	refreshToken, err := SignWithHeader(alg, key, claims, refreshHeader, MaxAge(refreshMaxAge))
+	if err != nil {
+		return TokenPair{}, err
+	}
+
+	return NewTokenPair(accessToken, refreshToken), nil
+}
+
+// VerifyAccessToken same as `Verify` but it rejects refresh tokens
+// generated by `SignTokenPair` with an ErrInvalidType error.
+// Note that `Verify` rejects them too, this one states the intention.
+func VerifyAccessToken(alg Alg, key PublicKey, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	return Verify(alg, key, token, prependValidator(tokenType(false), validators)...)
+}
+
+// VerifyRefreshToken same as `Verify` but it accepts only refresh tokens
+// generated by `SignTokenPair`, otherwise it returns an ErrInvalidType error.
+func VerifyRefreshToken(alg Alg, key PublicKey, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
+	return Verify(alg, key, token, joinValidators([]TokenValidator{refreshTokenOption{}, tokenType(true)}, validators)...)
+}
+
+// VerifyTokenPair verifies both tokens of a "tokenPair" generated by `SignTokenPair`,
+// the access one through `VerifyAccessToken` and the refresh one through `VerifyRefreshToken`,
+// with the same "validators". It returns an ErrInvalidType error if the tokens are swapped.
+// Note that an expired access token fails, use the `VerifyRefreshToken` alone
+// (or the `VerifyAllowExpired` for the access token) to renew it.
+//
+// Usage:
+//  accessToken, refreshToken, err := jwt.VerifyTokenPair(jwt.EdDSA, publicKey, tokenPair)
+func VerifyTokenPair(alg Alg, key PublicKey, tokenPair TokenPair, validators ...TokenValidator) (accessToken, refreshToken *VerifiedToken, err error) {
+	accessToken, err = VerifyAccessToken(alg, key, unquoteToken(tokenPair.AccessToken), validators...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	refreshToken, err = VerifyRefreshToken(alg, key, unquoteToken(tokenPair.RefreshToken), validators...)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return accessToken, refreshToken, nil
+}
+
+// unquoteToken returns the token of a `TokenPair` field without its quotes, see `BytesQuote`.
+func unquoteToken(b json.RawMessage) []byte {
+	if token, err := strconv.Unquote(string(b)); err == nil {
+		return []byte(token)
+	}
+
+	return b
+}
+
+type refreshTokenOption struct{}
+
+// ValidateToken completes the TokenValidator interface.
+// It respects the previous error.
+func (refreshTokenOption) ValidateToken(_ []byte, _ Claims, err error) error {
+	return err
+}
+
+// hasRefreshToken reports whether the "validators" accept refresh tokens,
+// see `VerifyRefreshToken`.
+func hasRefreshToken(validators []TokenValidator) bool {
+	for _, v := range validators {
+		if _, ok := v.(refreshTokenOption); ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+// isRefreshTokenHeader reports whether the (decoded) header's "typ" field is the `RefreshTokenType`.
+func isRefreshTokenHeader(header []byte) bool {
+	if !bytes.Contains(header, []byte("refresh")) && bytes.IndexByte(header, '\\') < 0 {
+		return false // fast path, the field values may be escaped.
+	}
+
+	var h struct {
+		Typ string `json:"typ"`
+	}
+	if err := json.Unmarshal(header, &h); err != nil {
+		return false
+	}
+
+	return h.Typ == RefreshTokenType
+}
+
+// tokenType reports an ErrInvalidType error if the token
+// is (or is not, when "refresh" is true) a refresh token.
+func tokenType(refresh bool) TokenValidatorFunc {
+	return func(token []byte, _ Claims, err error) error {
+		header, headerErr := DecodeHeader(token) // the token is already verified.
+		if headerErr != nil {
+			return headerErr
+		}
+
+		if (header.Typ == RefreshTokenType) != refresh {
+			return ErrInvalidType
+		}
+
+		return err
+	}
+}
+
+func prependValidator(validator TokenValidator, validators []TokenValidator) []TokenValidator {
+	return append([]TokenValidator{validator}, validators...)
+}
+
 // BytesQuote returns a double-quoted []byte slice representing "b".
 func BytesQuote(b []byte) []byte {
 	dst := make([]byte, len(b)+2)
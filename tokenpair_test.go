@@ -42,3 +42,89 @@ func TestTokenPair(t *testing.T) {
 		t.Fatalf("expected token pairs to be matched, expected:\n%#+v\n\nbut got:\n%#+v", tokenPair, tokPair)
 	}
 }
+
+func TestSignTokenPair(t *testing.T) {
+	prevClock := Clock
+	defer func() {
+		Clock = prevClock
+	}()
+
+	now := time.Now()
+	Clock = func() time.Time {
+		return now
+	}
+
+	tokenPair, err := SignTokenPair(testAlg, testSecret, Map{"username": "kataras"}, 10*time.Minute, time.Hour)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	accessToken, err := strconv.Unquote(string(tokenPair.AccessToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	refreshToken, err := strconv.Unquote(string(tokenPair.RefreshToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := VerifyAccessToken(testAlg, testSecret, []byte(accessToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := Clock().Add(10*time.Minute).Unix(), verifiedToken.StandardClaims.Expiry; expected != got {
+		t.Fatalf("expected access token expiration: %d but got: %d", expected, got)
+	}
+
+	verifiedToken, err = VerifyRefreshToken(testAlg, testSecret, []byte(refreshToken))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := Clock().Add(time.Hour).Unix(), verifiedToken.StandardClaims.Expiry; expected != got {
+		t.Fatalf("expected refresh token expiration: %d but got: %d", expected, got)
+	}
+
+	var claims Map
+	if err = verifiedToken.Claims(&claims); err != nil {
+		t.Fatal(err)
+	}
+
+	if expected, got := "kataras", claims["username"]; expected != got {
+		t.Fatalf("expected username claim: %s but got: %v", expected, got)
+	}
+
+	if _, err = VerifyAccessToken(testAlg, testSecret, []byte(refreshToken)); err != ErrInvalidType {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidType, err)
+	}
+
+	if _, err = VerifyRefreshToken(testAlg, testSecret, []byte(accessToken)); err != ErrInvalidType {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidType, err)
+	}
+
+	// The default verification rejects a refresh token too.
+	if _, err = Verify(testAlg, testSecret, []byte(refreshToken)); err != ErrInvalidType {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidType, err)
+	}
+
+	if _, err = VerifyWithHeaderValidator(nil, testSecret, []byte(refreshToken), AllowedAlgorithms(testAlg)); err != ErrInvalidType {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidType, err)
+	}
+
+	// Both tokens of the pair.
+	verifiedAccessToken, verifiedRefreshToken, err := VerifyTokenPair(testAlg, testSecret, tokenPair)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifiedAccessToken.StandardClaims.Expiry != Clock().Add(10*time.Minute).Unix() || verifiedRefreshToken.StandardClaims.Expiry != Clock().Add(time.Hour).Unix() {
+		t.Fatalf("unexpected token pair expirations: %d and %d", verifiedAccessToken.StandardClaims.Expiry, verifiedRefreshToken.StandardClaims.Expiry)
+	}
+
+	swapped := TokenPair{AccessToken: tokenPair.RefreshToken, RefreshToken: tokenPair.AccessToken}
+	if _, _, err = VerifyTokenPair(testAlg, testSecret, swapped); err != ErrInvalidType {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidType, err)
+	}
+}
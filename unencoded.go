@@ -0,0 +1,223 @@
+package jwt
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"sort"
+)
+
+// SignUnencoded signs the raw "payload" with the unencoded payload option (RFC 7797):
+// the header carries the "b64":false and "crit":["b64"] fields
+// and the signing input is base64url(header) + "." + payload, the payload is not base64url-encoded.
+// The optional "customHeader" (a map or a struct) holds any other header fields,
+// e.g. the "kid" and the "http://openbanking.org.uk/iat" ones of the UK Open Banking message signing.
+//
+// It returns the detached compact form: base64url(header) + ".." + base64url(signature),
+// the payload is transmitted separately, e.g. as the HTTP request body
+// and the token through a "x-jws-signature" HTTP header.
+// Use the `VerifyUnencoded` function to verify it.
+//
+// Usage:
+//  token, err := jwt.SignUnencoded(jwt.PS256, privateKey, body, jwt.Map{"kid": "my-kid"})
+//  req.Header.Set("x-jws-signature", string(token))
+func SignUnencoded(alg Alg, key PrivateKey, payload []byte, customHeader interface{}) ([]byte, error) {
+	if alg == nil {
+		return nil, ErrTokenAlg
+	}
+
+	if err := checkAlgorithmPermitted(alg); err != nil {
+		return nil, err
+	}
+
+	header, err := createUnencodedHeader(alg.Name(), customHeader)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedHeader := Base64Encode(header)
+	signingInput := make([]byte, 0, len(encodedHeader)+1+len(payload))
+	signingInput = append(append(append(signingInput, encodedHeader...), '.'), payload...)
+
+	signature, err := alg.Sign(key, signingInput)
+	if err != nil {
+		return nil, err
+	}
+
+	encodedSignature := Base64Encode(signature)
+	token := make([]byte, 0, len(encodedHeader)+2+len(encodedSignature))
+	token = append(append(token, encodedHeader...), '.', '.')
+	return append(token, encodedSignature...), nil
+}
+
+// createUnencodedHeader returns the "alg", "b64" and "crit" fields
+// followed by the custom header fields, sorted by their names.
+// A "crit" field of the custom header is merged to the "b64" one.
+func createUnencodedHeader(alg string, customHeader interface{}) ([]byte, error) {
+	var fields map[string]json.RawMessage
+	if customHeader != nil {
+		b, err := Marshal(customHeader)
+		if err != nil {
+			return nil, err
+		}
+
+		if err = json.Unmarshal(b, &fields); err != nil {
+			return nil, fmt.Errorf("%w: custom header: %v", ErrMalformedHeader, err)
+		}
+	}
+
+	crit := []string{"b64"}
+	if v, ok := fields["crit"]; ok {
+		var extra []string
+		if err := json.Unmarshal(v, &extra); err != nil {
+			return nil, fmt.Errorf("%w: crit: %v", ErrMalformedHeader, err)
+		}
+
+		for _, name := range extra {
+			if !containsString(crit, name) {
+				crit = append(crit, name)
+			}
+		}
+	}
+
+	algValue, err := json.Marshal(alg)
+	if err != nil {
+		return nil, err
+	}
+
+	critValue, err := json.Marshal(crit)
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		switch name {
+		case "alg", "b64", "crit":
+		default:
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	var buf bytes.Buffer
+	buf.WriteString(`{"alg":`)
+	buf.Write(algValue)
+	buf.WriteString(`,"b64":false,"crit":`)
+	buf.Write(critValue)
+	for _, name := range names {
+		nameValue, err := json.Marshal(name)
+		if err != nil {
+			return nil, err
+		}
+
+		buf.WriteByte(',')
+		buf.Write(nameValue)
+		buf.WriteByte(':')
+		buf.Write(fields[name])
+	}
+	buf.WriteByte('}')
+
+	return buf.Bytes(), nil
+}
+
+// VerifyUnencoded verifies a token of the unencoded payload option (RFC 7797),
+// e.g. the result of `SignUnencoded`.
+// The "payload" is the raw, detached, payload and the token's payload segment must be empty,
+// a nil "payload" verifies the (unencoded) payload segment of the token instead.
+// The header must carry the "b64":false field and list it in its "crit" field,
+// otherwise it fails with an ErrTokenForm error
+// (tokens of base64url-encoded payloads are verified through `Verify`).
+// The optional "understoodCrit" are any other critical header fields the caller handles,
+// e.g. the "http://openbanking.org.uk/iat" one, see `UnderstoodCrit`.
+// The header's "alg" must match the given algorithm, like `Verify` does,
+// and RSA keys smaller than the `DefaultMinRSAKeyBits` are rejected.
+//
+// Note that it does NOT validate any claims,
+// the payload may not be a JSON at all, see `VerifyDetached` too.
+//
+// Usage:
+//  verifiedToken, err := jwt.VerifyUnencoded(jwt.PS256, publicKey, []byte(r.Header.Get("x-jws-signature")), body)
+func VerifyUnencoded(alg Alg, key PublicKey, token, payload []byte, understoodCrit ...string) (*VerifiedToken, error) {
+	token = trimToken(token)
+	if len(token) == 0 {
+		return nil, ErrEmptyToken
+	}
+
+	if alg == nil {
+		return nil, ErrTokenAlg
+	}
+
+	if err := checkAlgorithmPermitted(alg); err != nil {
+		return nil, err
+	}
+
+	// The payload may hold dots, the header and the signature may not.
+	headerEnd, signatureStart := bytes.IndexByte(token, '.'), bytes.LastIndexByte(token, '.')
+	if headerEnd <= 0 || headerEnd == signatureStart {
+		return nil, ErrMalformedToken
+	}
+
+	if embedded := token[headerEnd+1 : signatureStart]; payload == nil {
+		payload = embedded
+	} else if len(embedded) > 0 {
+		return nil, fmt.Errorf("%w: expected a detached payload", ErrTokenForm)
+	}
+
+	headerDecoded, err := Base64Decode(token[:headerEnd])
+	if err != nil {
+		return nil, segmentError("header", 0, err)
+	}
+
+	if err = checkHeaderLimits(headerDecoded); err != nil {
+		return nil, err
+	}
+
+	signature, err := Base64Decode(token[signatureStart+1:])
+	if err != nil {
+		return nil, segmentError("signature", signatureStart+1, err)
+	}
+
+	unencoded, err := checkCritical(headerDecoded, understoodCrit)
+	if err != nil {
+		return nil, err
+	}
+
+	if !unencoded {
+		return nil, fmt.Errorf("%w: expected the b64:false and crit:[b64] header fields", ErrTokenForm)
+	}
+
+	var h struct {
+		Alg string `json:"alg"`
+	}
+	if err = json.Unmarshal(headerDecoded, &h); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrMalformedHeader, err)
+	}
+
+	if h.Alg != alg.Name() {
+		return nil, ErrTokenAlg
+	}
+
+	if err = checkRSAKeySize(key, DefaultMinRSAKeyBits); err != nil {
+		return nil, err
+	}
+
+	if err = checkSignatureSize(alg, key, signature); err != nil {
+		return nil, err
+	}
+
+	signingInput := make([]byte, 0, headerEnd+1+len(payload))
+	signingInput = append(append(append(signingInput, token[:headerEnd]...), '.'), payload...)
+	if err = alg.Verify(key, signingInput, signature); err != nil {
+		return nil, err
+	}
+
+	verifiedToken := &VerifiedToken{
+		Token:       token,
+		Header:      headerDecoded,
+		Payload:     payload,
+		Signature:   signature,
+		VerifiedKID: headerKid(headerDecoded),
+	}
+	return verifiedToken, nil
+}
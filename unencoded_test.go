@@ -0,0 +1,109 @@
+package jwt
+
+import (
+	"bytes"
+	"errors"
+	"testing"
+)
+
+func TestUnencodedPayload(t *testing.T) {
+	// RFC 7797, section 4.2.
+	key, err := Base64Decode([]byte("AyM1SysPpbyDfgZld3umj1qzKObwVMkoqQ-EstJQLr_T-1qS0gZH75aKtMN3Yj0iPS4hcgUuTwjAzZr1Z9CAow"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	payload := []byte("$.02")
+	expectedToken := []byte("eyJhbGciOiJIUzI1NiIsImI2NCI6ZmFsc2UsImNyaXQiOlsiYjY0Il19..A5dxf2s96_n5FLueVuW1Z_vh161FwXZC4YLPff6dmDY")
+
+	token, err := SignUnencoded(HS256, key, payload, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(token, expectedToken) {
+		t.Fatalf("expected token:\n%s\nbut got:\n%s", expectedToken, token)
+	}
+
+	verifiedToken, err := VerifyUnencoded(HS256, key, token, payload)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(verifiedToken.Payload, payload) {
+		t.Fatalf("expected payload: %s but got: %s", payload, verifiedToken.Payload)
+	}
+
+	// The attached form, the payload holds a dot.
+	attached := append(append(append([]byte{}, expectedToken[:bytes.IndexByte(expectedToken, '.')+1]...), payload...), expectedToken[bytes.LastIndexByte(expectedToken, '.'):]...)
+	if _, err = VerifyUnencoded(HS256, key, attached, nil); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyUnencoded(HS256, key, attached, payload); !errors.Is(err, ErrTokenForm) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenForm, err)
+	}
+
+	if _, err = VerifyUnencoded(HS256, key, token, []byte("$.03")); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+
+	if _, err = VerifyUnencoded(HS384, key, token, payload); !errors.Is(err, ErrTokenAlg) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenAlg, err)
+	}
+
+	// The token is not accepted by Verify, as its header is critical.
+	if _, err = Verify(HS256, key, attached); err == nil {
+		t.Fatalf("expected Verify to fail on an unencoded payload token")
+	}
+
+	// A regular token is not accepted by VerifyUnencoded.
+	regular, err := Sign(HS256, key, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = VerifyUnencoded(HS256, key, regular, nil); !errors.Is(err, ErrTokenForm) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenForm, err)
+	}
+}
+
+func TestUnencodedPayloadOpenBanking(t *testing.T) {
+	privateKey, err := LoadPrivateKeyRSA("./_testfiles/rsapss_private_key.pem")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// The message signing of an Open Banking style API:
+	// the request body is signed and the detached token is sent through the "x-jws-signature" HTTP header.
+	body := []byte(`{"Data":{"Initiation":{"InstructionIdentification":"ACME412","InstructedAmount":{"Amount":"165.88","Currency":"GBP"}}},"Risk":{}}`)
+	header := Map{
+		"kid": "90210ABAD",
+		"typ": "JOSE",
+		"cty": "application/json",
+	}
+
+	token, err := SignUnencoded(PS256, privateKey, body, header)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if parts := bytes.Split(token, sep); len(parts) != 3 || len(parts[1]) != 0 {
+		t.Fatalf("expected a detached token but got: %s", token)
+	}
+
+	verifiedToken, err := VerifyUnencoded(PS256, &privateKey.PublicKey, token, body)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedHeader := `{"alg":"PS256","b64":false,"crit":["b64"],"cty":"application/json","kid":"90210ABAD","typ":"JOSE"}`
+	if string(verifiedToken.Header) != expectedHeader {
+		t.Fatalf("expected header:\n%s\nbut got:\n%s", expectedHeader, verifiedToken.Header)
+	}
+
+	tampered := bytes.Replace(body, []byte("165.88"), []byte("965.88"), 1)
+	if _, err = VerifyUnencoded(PS256, &privateKey.PublicKey, token, tampered); !errors.Is(err, ErrTokenSignature) {
+		t.Fatalf("expected error: %v but got: %v", ErrTokenSignature, err)
+	}
+}
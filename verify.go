@@ -1,8 +1,11 @@
 package jwt
 
 import (
+	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"time"
 )
 
 // Verify decodes, verifies and validates the standard JWT claims
@@ -25,36 +28,168 @@ import (
 //  var claims map[string]interface{}
 //  verifiedToken.Claims(&claims)
 func Verify(alg Alg, key PublicKey, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
-	return verifyToken(alg, key, nil, token, nil, validators...)
+	return verifyToken(context.Background(), alg, key, nil, token, nil, validators...)
+}
+
+// VerifyToken same as `Verify` but it decodes the token's payload (claims)
+// to the "dest" pointer of a struct or map value,
+// like the `Keys.VerifyToken` does. If "dest" is nil then
+// the token is verified and validated but its payload is not decoded.
+//
+// Unlike `Verify`, the errors of the "exp", "nbf" and "iat" claims validation
+// are returned as ValidationError values, which hold the token's times and
+// the current one. They can still be compared through errors.Is, e.g. errors.Is(err, jwt.ErrExpired).
+//
+// Example Code:
+//
+//  var claims myClaims
+//  err := jwt.VerifyToken(jwt.EdDSA, publicKey, token, &claims, jwt.ExpectIssuer("my-app"))
+func VerifyToken(alg Alg, key PublicKey, token []byte, dest interface{}, validators ...TokenValidator) error {
+	verifiedToken, err := Verify(alg, key, token, validators...)
+	if err != nil {
+		return withTimeValidationError(verificationTime(validators), token, err)
+	}
+
+	if dest == nil {
+		return nil
+	}
+
+	return verifiedToken.Claims(dest)
+}
+
+// VerifyTokenRaw same as `VerifyToken` but it returns the verified payload (claims) too,
+// e.g. to forward it, hash it or read the claims that the "dest" does not capture,
+// without a second parse. The "dest" can be nil.
+// The returned bytes are a copy, the caller is free to keep or modify them.
+//
+// Example Code:
+//
+//  var claims myClaims
+//  payload, err := jwt.VerifyTokenRaw(jwt.EdDSA, publicKey, token, &claims)
+func VerifyTokenRaw(alg Alg, key PublicKey, token []byte, dest interface{}, validators ...TokenValidator) (json.RawMessage, error) {
+	verifiedToken, err := Verify(alg, key, token, validators...)
+	if err != nil {
+		return nil, withTimeValidationError(verificationTime(validators), token, err)
+	}
+
+	if dest != nil {
+		if err = verifiedToken.Claims(dest); err != nil {
+			return nil, err
+		}
+	}
+
+	payload := make(json.RawMessage, len(verifiedToken.Payload))
+	copy(payload, verifiedToken.Payload)
+	return payload, nil
+}
+
+// withTimeValidationError converts the builtin time claims validation errors of
+// the (signature verified) "token" to ValidationError values.
+func withTimeValidationError(now time.Time, token []byte, err error) error {
+	switch err {
+	case ErrExpired, ErrNotValidYet, ErrIssuedInTheFuture:
+	default:
+		return err
+	}
+
+	tok, decodeErr := Decode(token)
+	if decodeErr != nil {
+		return err
+	}
+
+	// The "token" passed the signature verification, inflate its payload like the `Verify` does.
+	payload, decodeErr := decompressPayload(tok.Header, tok.Payload)
+	if decodeErr != nil {
+		return err
+	}
+
+	var standardClaims Claims
+	if unmarshalStandardClaims(payload, &standardClaims) != nil {
+		return err
+	}
+
+	return newTimeValidationError(err, now, standardClaims, 0)
 }
 
 // VerifyEncrypted same as `Verify` but it decrypts the payload part with the given "decrypt" function.
 // The "decrypt" function is called AFTER base64-decode and BEFORE Unmarshal.
 // Look the `GCM` function for details.
 func VerifyEncrypted(alg Alg, key PublicKey, decrypt InjectFunc, token []byte, validators ...TokenValidator) (*VerifiedToken, error) {
-	return verifyToken(alg, key, decrypt, token, nil, validators...)
+	return verifyToken(context.Background(), alg, key, decrypt, token, nil, validators...)
 }
 
 // VerifyWithHeaderValidator same as `Verify` but it accepts a custom header validator too.
 func VerifyWithHeaderValidator(alg Alg, key PublicKey, token []byte, headerValidator HeaderValidator, validators ...TokenValidator) (*VerifiedToken, error) {
-	return verifyToken(alg, key, nil, token, headerValidator, validators...)
+	return verifyToken(context.Background(), alg, key, nil, token, headerValidator, validators...)
 }
 
 // VerifyEncryptedWithHeaderValidator same as `VerifyEncrypted` but it accepts a custom header validator too.
 func VerifyEncryptedWithHeaderValidator(alg Alg, key PublicKey, decrypt InjectFunc, token []byte, headerValidator HeaderValidator, validators ...TokenValidator) (*VerifiedToken, error) {
-	return verifyToken(alg, key, decrypt, token, headerValidator, validators...)
+	return verifyToken(context.Background(), alg, key, decrypt, token, headerValidator, validators...)
 }
 
-func verifyToken(alg Alg, key PublicKey, decrypt InjectFunc, token []byte, headerValidator HeaderValidator, validators ...TokenValidator) (*VerifiedToken, error) {
+func verifyToken(ctx context.Context, alg Alg, key PublicKey, decrypt InjectFunc, token []byte, headerValidator HeaderValidator, validators ...TokenValidator) (*VerifiedToken, error) {
+	observer, logf := findObserver(validators), findDebugLogger(validators)
+	if observer == nil && logf == nil {
+		return verifyTokenUnobserved(ctx, alg, key, decrypt, token, headerValidator, nil, validators...)
+	}
+
+	var trace *verifyTrace
+	if logf != nil {
+		trace = new(verifyTrace)
+	}
+
+	verifiedToken, err := verifyTokenUnobserved(ctx, alg, key, decrypt, token, headerValidator, trace, validators...)
+	if observer != nil {
+		observeVerify(observer, alg, token, err)
+	}
+
+	if logf != nil {
+		debugVerify(logf, alg, token, trace.step, err)
+	}
+
+	return verifiedToken, err
+}
+
+// verifyTokenUnobserved verifies the token, the optional "trace" records the step it reached.
+func verifyTokenUnobserved(ctx context.Context, alg Alg, key PublicKey, decrypt InjectFunc, token []byte, headerValidator HeaderValidator, trace *verifyTrace, validators ...TokenValidator) (*VerifiedToken, error) {
+	token = trimToken(token)
 	if len(token) == 0 {
-		return nil, ErrMissing
+		return nil, ErrEmptyToken
+	}
+
+	if limit := maxTokenBytes(validators); limit > 0 && len(token) > limit {
+		return nil, ErrTokenTooLarge
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
 	}
 
-	header, payload, signature, err := decodeToken(alg, key, token, headerValidator)
+	decode := Base64Decode
+	lenient := hasLenientBase64(validators)
+	if lenient {
+		decode = base64DecodeLenient
+	}
+
+	header, payload, signature, err := decodeTokenWith(decode, alg, key, token, headerValidator, understoodCrit(validators), minRSAKeyBits(validators), trace)
 	if err != nil {
 		return nil, err
 	}
 
+	if lenient {
+		token = canonicalBase64Token(token)
+	}
+
+	// A refresh token is not an access token, see `VerifyRefreshToken`.
+	if !hasRefreshToken(validators) && isRefreshTokenHeader(header) {
+		return nil, ErrInvalidType
+	}
+
+	if err = ctx.Err(); err != nil {
+		return nil, err
+	}
+
 	if decrypt != nil {
 		payload, err = decrypt(payload)
 		if err != nil {
@@ -62,29 +197,48 @@ func verifyToken(alg Alg, key PublicKey, decrypt InjectFunc, token []byte, heade
 		}
 	}
 
+	payload, err = decompressPayload(header, payload)
+	if err != nil {
+		return nil, err
+	}
+
+	strict := hasStrictJSON(validators)
+	if strict {
+		if err = checkDuplicateKeys(header); err != nil {
+			return nil, err
+		}
+
+		if err = checkDuplicateKeys(payload); err != nil {
+			return nil, err
+		}
+	}
+
+	trace.reach(stepClaims)
+	now := verificationTime(validators)
+
 	var standardClaims Claims
-	standardClaimsErr := json.Unmarshal(payload, &standardClaims) // Use the standard one instead of the custom, no need to support "required" feature here.
+	standardClaimsErr := unmarshalStandardClaims(payload, &standardClaims) // Use the standard one instead of the custom, no need to support "required" feature here.
 	// Do not exist on this error now, the payload may not be a JSON one.
 	if standardClaimsErr != nil {
 		var secondChange claimsSecondChance // try again with a different structure, which always converted to the standard jwt claims.
-		if err = json.Unmarshal(payload, &secondChange); err != nil {
-			err = errPayloadNotJSON // allow validators to catch this error.
-		}
+		if err = unmarshalStandardClaims(payload, &secondChange); err != nil {
+			if !errors.Is(err, ErrInvalidNumericDate) {
+				err = errPayloadNotJSON // allow validators to catch this error.
+			}
 
-		standardClaims = secondChange.toClaims()
+			standardClaims, _ = secondChange.toClaims()
+		} else if standardClaims, err = secondChange.toClaims(); err == nil {
+			err = validateClaims(now, standardClaims)
+		}
 	} else {
-		err = validateClaims(Clock(), standardClaims)
+		err = validateClaims(now, standardClaims)
 	}
 
-	for _, validator := range validators {
-		// A token validator can skip the builtin validation and return a nil error,
-		// in that case the previous error is skipped.
-		if err = validator.ValidateToken(token, standardClaims, err); err != nil {
-			break
-		}
+	if err == nil {
+		trace.reach(stepValidators)
 	}
 
-	if err != nil {
+	if err = runValidators(ctx, now, token, payload, standardClaims, err, validators); err != nil {
 		// Exit on parsing standard claims error(when Plain is missing) or standard claims validation error or custom validators.
 		return nil, err
 	}
@@ -95,12 +249,30 @@ func verifyToken(alg Alg, key PublicKey, decrypt InjectFunc, token []byte, heade
 		Payload:        payload,
 		Signature:      signature,
 		StandardClaims: standardClaims,
+		VerifiedKID:    headerKid(header),
+		strict:         strict,
 		// We could store the standard claims error when Plain token validator is applied
 		// but there is no a single case of its usability, so we don't, unless is requested.
 	}
 	return verifiedTok, nil
 }
 
+// headerKid returns the "kid" field of the (decoded) header, if any.
+func headerKid(header []byte) string {
+	if !bytes.Contains(header, []byte("kid")) && bytes.IndexByte(header, '\\') < 0 {
+		return "" // fast path, the field names may be escaped.
+	}
+
+	var h struct {
+		Kid string `json:"kid"`
+	}
+	if err := json.Unmarshal(header, &h); err != nil {
+		return ""
+	}
+
+	return h.Kid
+}
+
 // VerifiedToken holds the information about a verified token.
 // Look `Verify` for more.
 type VerifiedToken struct {
@@ -109,6 +281,11 @@ type VerifiedToken struct {
 	Payload        []byte // The payload (decoded) part.
 	Signature      []byte // The signature (decoded) part.
 	StandardClaims Claims // Any standard claims extracted from the payload.
+	// The "kid" header field of the verified token, e.g. to audit which key of a `Keys` verified it.
+	// Empty if the header has no "kid".
+	VerifiedKID string
+
+	strict bool // decode the claims with DisallowUnknownFields, see `StrictJSON`.
 }
 
 // Claims decodes the token's payload to the "dest".
@@ -122,10 +299,65 @@ type VerifiedToken struct {
 // and validated at the `Verify` function itself,
 // therefore NO FURTHER STEP is required
 // to validate the "exp", "iat" and "nbf" claims.
+//
+// When the token was verified with the `StrictJSON` validator,
+// the claims the "dest" struct does not declare fail the decoding.
 func (t *VerifiedToken) Claims(dest interface{}) error {
+	if t.strict {
+		return unmarshalStrict(t.Payload, dest)
+	}
+
 	return Unmarshal(t.Payload, dest)
 }
 
+// runValidators runs the "validators" against the standard claims of the "payload",
+// the "err" is the builtin claims validation one, a token validator can skip it.
+// Then it checks the required claims, runs the claim predicates and consumes the one-time tokens.
+// It's the validation path of both the `Verify` functions and the `Claims.Valid` method.
+func runValidators(ctx context.Context, now time.Time, token, payload []byte, standardClaims Claims, err error, validators []TokenValidator) error {
+	standardClaims.validatedAt = now // see `Claims.currentTime`.
+
+	for _, validator := range validators {
+		// A token validator can skip the builtin validation and return a nil error,
+		// in that case the previous error is skipped.
+		if err = validateTokenContext(ctx, now, validator, token, standardClaims, err); err != nil {
+			return err
+		}
+	}
+
+	if err != nil {
+		return err
+	}
+
+	if err = validateRequiredClaims(payload, validators); err != nil {
+		return err
+	}
+
+	if err = validateClaimPredicates(payload, validators); err != nil {
+		return err
+	}
+
+	return consumeOneTimeUse(standardClaims, validators)
+}
+
+// TimeUntilExpiry returns how long the token remains valid after "now",
+// computed from its "exp" claim, e.g. to bound the TTL of a cache entry.
+// The duration is negative when the token is already expired
+// (possible under a `Leeway` or a `ClockSkew`).
+// It reports false when the token has no "exp" claim, it never expires.
+//
+// Usage:
+//  if ttl, ok := verifiedToken.TimeUntilExpiry(time.Now()); ok {
+//    cache.Set(key, value, ttl)
+//  }
+func (t *VerifiedToken) TimeUntilExpiry(now time.Time) (time.Duration, bool) {
+	if t.StandardClaims.Expiry <= 0 {
+		return 0, false
+	}
+
+	return t.StandardClaims.ExpiresAt().Sub(now), true
+}
+
 var errPayloadNotJSON = errors.New("jwt: payload is not a type of JSON") // malformed JSON or it's not a JSON at all.
 
 // Plain can be provided as a Token Validator at `Verify` and `VerifyEncrypted` functions
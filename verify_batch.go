@@ -0,0 +1,75 @@
+package jwt
+
+import (
+	"runtime"
+	"sync"
+)
+
+// BatchResult holds the result of a single token verification of `VerifyBatch`.
+type BatchResult struct {
+	// Token is the verified token, nil on failure.
+	Token *VerifiedToken
+	// Err is the verification error, nil on success.
+	Err error
+}
+
+// VerifyBatch verifies many tokens signed by the same key concurrently,
+// across runtime.GOMAXPROCS(0) goroutines at most.
+// It returns the results in the order of the given "tokens".
+// Look `VerifyBatchConcurrency` to set the number of goroutines.
+//
+// Example Code:
+//
+//  results := jwt.VerifyBatch(jwt.EdDSA, publicKey, tokens, jwt.ExpectIssuer("my-app"))
+//  for i, result := range results {
+//    if result.Err != nil {
+//      [handle error of tokens[i]...]
+//      continue
+//    }
+//    [result.Token.Payload...]
+//  }
+func VerifyBatch(alg Alg, key PublicKey, tokens [][]byte, validators ...TokenValidator) []BatchResult {
+	return VerifyBatchConcurrency(runtime.GOMAXPROCS(0), alg, key, tokens, validators...)
+}
+
+// VerifyBatchConcurrency same as `VerifyBatch` but it accepts
+// the maximum number of goroutines to verify the tokens with.
+// A "concurrency" less than 1 defaults to runtime.GOMAXPROCS(0).
+//
+// Note that the "validators" are called concurrently,
+// they must be safe for concurrent use (the builtin ones are).
+func VerifyBatchConcurrency(concurrency int, alg Alg, key PublicKey, tokens [][]byte, validators ...TokenValidator) []BatchResult {
+	results := make([]BatchResult, len(tokens))
+	if len(tokens) == 0 {
+		return results
+	}
+
+	if concurrency < 1 {
+		concurrency = runtime.GOMAXPROCS(0)
+	}
+	if concurrency > len(tokens) {
+		concurrency = len(tokens)
+	}
+
+	indexes := make(chan int)
+	var wg sync.WaitGroup
+	wg.Add(concurrency)
+	for i := 0; i < concurrency; i++ {
+		go func() {
+			defer wg.Done()
+
+			for idx := range indexes {
+				verifiedToken, err := Verify(alg, key, tokens[idx], validators...)
+				results[idx] = BatchResult{Token: verifiedToken, Err: err}
+			}
+		}()
+	}
+
+	for idx := range tokens {
+		indexes <- idx
+	}
+	close(indexes)
+	wg.Wait()
+
+	return results
+}
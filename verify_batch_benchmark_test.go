@@ -0,0 +1,52 @@
+package jwt
+
+import (
+	"crypto/ed25519"
+	"testing"
+)
+
+func benchmarkBatchTokens(b *testing.B) (ed25519.PublicKey, [][]byte) {
+	privateKey := ed25519.NewKeyFromSeed(make([]byte, ed25519.SeedSize))
+
+	tokens := make([][]byte, 256)
+	for i := range tokens {
+		token, err := Sign(EdDSA, privateKey, testStructValue)
+		if err != nil {
+			b.Fatal(err)
+		}
+
+		tokens[i] = token
+	}
+
+	return privateKey.Public().(ed25519.PublicKey), tokens
+}
+
+func BenchmarkVerifySequential(b *testing.B) {
+	publicKey, tokens := benchmarkBatchTokens(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, token := range tokens {
+			if _, err := Verify(EdDSA, publicKey, token); err != nil {
+				b.Fatal(err)
+			}
+		}
+	}
+}
+
+func BenchmarkVerifyBatch(b *testing.B) {
+	publicKey, tokens := benchmarkBatchTokens(b)
+
+	b.ReportAllocs()
+	b.ResetTimer()
+
+	for i := 0; i < b.N; i++ {
+		for _, result := range VerifyBatch(EdDSA, publicKey, tokens) {
+			if result.Err != nil {
+				b.Fatal(result.Err)
+			}
+		}
+	}
+}
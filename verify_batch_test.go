@@ -0,0 +1,68 @@
+package jwt
+
+import (
+	"encoding/json"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestVerifyBatch(t *testing.T) {
+	tokens := make([][]byte, 50)
+	for i := range tokens {
+		claims := Map{"index": i}
+		if i%10 == 0 {
+			claims["exp"] = Clock().Add(-time.Minute).Unix()
+		}
+
+		token, err := Sign(testAlg, testSecret, claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tokens[i] = token
+	}
+	tokens[len(tokens)-1] = []byte("malformed")
+
+	for _, concurrency := range []int{0, 1, 4, 100} {
+		results := VerifyBatchConcurrency(concurrency, testAlg, testSecret, tokens)
+		if expected, got := len(tokens), len(results); expected != got {
+			t.Fatalf("[%d] expected %d results but got: %d", concurrency, expected, got)
+		}
+
+		for i, result := range results {
+			if i == len(tokens)-1 {
+				if result.Err != ErrMalformedToken || result.Token != nil {
+					t.Fatalf("[%d] [%d] expected error: %v but got: %v", concurrency, i, ErrMalformedToken, result.Err)
+				}
+
+				continue
+			}
+
+			if i%10 == 0 {
+				if result.Err != ErrExpired || result.Token != nil {
+					t.Fatalf("[%d] [%d] expected error: %v but got: %v", concurrency, i, ErrExpired, result.Err)
+				}
+
+				continue
+			}
+
+			if result.Err != nil {
+				t.Fatalf("[%d] [%d] %v", concurrency, i, result.Err)
+			}
+
+			var claims Map
+			if err := result.Token.Claims(&claims); err != nil {
+				t.Fatal(err)
+			}
+
+			if expected, got := strconv.Itoa(i), claims["index"].(json.Number).String(); expected != got {
+				t.Fatalf("[%d] expected result of token index: %s but got: %s", concurrency, expected, got)
+			}
+		}
+	}
+
+	if results := VerifyBatch(testAlg, testSecret, nil); len(results) != 0 {
+		t.Fatalf("expected no results but got: %d", len(results))
+	}
+}
@@ -0,0 +1,104 @@
+package jwt
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+)
+
+// DefaultStreamMaxTokenSize is the default maximum size of a line (token) of `VerifyStream`.
+const DefaultStreamMaxTokenSize = 1 << 20 // 1MiB.
+
+// ErrTokenTooLong indicates that a line of `VerifyStream` exceeds the maximum token size.
+// It is an ErrTokenForm too.
+var ErrTokenTooLong = fmt.Errorf("%w: token too long", ErrTokenForm)
+
+// VerifyStream reads newline-delimited tokens from "r" and verifies each one of them
+// with the given algorithm, key and validators, like the `Verify` function does.
+// Empty lines are skipped.
+//
+// The "fn" callback is called for each token with its verification result,
+// a verification error does not stop the stream, unless "fn" returns false.
+// Lines longer than `DefaultStreamMaxTokenSize` are reported as ErrTokenTooLong errors,
+// see `VerifyStreamMaxTokenSize` too.
+//
+// It returns a non-nil error only when reading from "r" fails.
+//
+// Example Code:
+//
+//  err := jwt.VerifyStream(file, jwt.EdDSA, publicKey, func(verifiedToken *jwt.VerifiedToken, err error) bool {
+//    if err != nil {
+//      [handle error...]
+//      return true // continue.
+//    }
+//    [verifiedToken.Payload...]
+//    return true
+//  })
+func VerifyStream(r io.Reader, alg Alg, key PublicKey, fn func(verifiedToken *VerifiedToken, err error) bool, validators ...TokenValidator) error {
+	return VerifyStreamMaxTokenSize(r, DefaultStreamMaxTokenSize, alg, key, fn, validators...)
+}
+
+// VerifyStreamMaxTokenSize same as `VerifyStream` but it accepts
+// the maximum size of a line (token), in bytes.
+func VerifyStreamMaxTokenSize(r io.Reader, maxTokenSize int, alg Alg, key PublicKey, fn func(verifiedToken *VerifiedToken, err error) bool, validators ...TokenValidator) error {
+	if maxTokenSize <= 0 {
+		maxTokenSize = DefaultStreamMaxTokenSize
+	}
+
+	br := bufio.NewReader(r)
+	var line []byte
+
+	for {
+		var (
+			tooLong bool
+			readErr error
+		)
+
+		line = line[:0]
+		for {
+			var chunk []byte
+			chunk, readErr = br.ReadSlice('\n')
+			if !tooLong {
+				if len(line)+len(chunk) > maxTokenSize+2 { // +2 for the "\r\n".
+					tooLong = true
+					line = line[:0]
+				} else {
+					line = append(line, chunk...)
+				}
+			}
+
+			if readErr != bufio.ErrBufferFull {
+				break
+			}
+		}
+
+		if readErr != nil && readErr != io.EOF {
+			return readErr
+		}
+
+		var (
+			verifiedToken *VerifiedToken
+			err           error
+		)
+
+		if tooLong {
+			err = ErrTokenTooLong
+		} else if token := bytes.TrimSpace(line); len(token) > 0 {
+			if len(token) > maxTokenSize {
+				err = ErrTokenTooLong
+			} else {
+				// Copy the token, the line buffer is reused.
+				verifiedToken, err = Verify(alg, key, append([]byte(nil), token...), validators...)
+			}
+		} else if readErr == io.EOF {
+			return nil
+		} else {
+			continue // skip empty lines.
+		}
+
+		if !fn(verifiedToken, err) || readErr == io.EOF {
+			return nil
+		}
+	}
+}
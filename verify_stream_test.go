@@ -0,0 +1,74 @@
+package jwt
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestVerifyStream(t *testing.T) {
+	var tokens []string
+	for i := 0; i < 3; i++ {
+		token, err := Sign(testAlg, testSecret, Map{"index": i}, MaxAge(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		tokens = append(tokens, string(token))
+	}
+
+	expired, err := Sign(testAlg, testSecret, Claims{Expiry: Clock().Add(-time.Minute).Unix()})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	tooLong := strings.Repeat("a", 2048)
+
+	// Mixed line endings, an empty line and no trailing new line.
+	input := tokens[0] + "\n" + string(expired) + "\r\n\n" + tooLong + "\n" + tokens[1] + "\n" + tokens[2]
+
+	var (
+		verified int
+		errs     []error
+	)
+	err = VerifyStreamMaxTokenSize(strings.NewReader(input), 1024, testAlg, testSecret, func(verifiedToken *VerifiedToken, err error) bool {
+		if err != nil {
+			errs = append(errs, err)
+			return true
+		}
+
+		if expected := tokens[verified]; string(verifiedToken.Token) != expected {
+			t.Fatalf("expected token: %s but got: %s", expected, verifiedToken.Token)
+		}
+
+		verified++
+		return true
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verified != len(tokens) {
+		t.Fatalf("expected %d verified tokens but got: %d", len(tokens), verified)
+	}
+
+	if len(errs) != 2 || errs[0] != ErrExpired || !errors.Is(errs[1], ErrTokenTooLong) || !errors.Is(errs[1], ErrTokenForm) {
+		t.Fatalf("expected errors: [%v %v] but got: %v", ErrExpired, ErrTokenTooLong, errs)
+	}
+
+	// Test stop.
+	calls := 0
+	err = VerifyStream(bytes.NewReader([]byte(input)), testAlg, testSecret, func(*VerifiedToken, error) bool {
+		calls++
+		return false
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("expected the stream to stop after the first token but got %d calls", calls)
+	}
+}
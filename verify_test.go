@@ -2,9 +2,11 @@ package jwt
 
 import (
 	"bytes"
+	"encoding/json"
 	"errors"
 	"reflect"
 	"testing"
+	"time"
 )
 
 // The actual implementation tests live inside token_test.go and each algorithm's test file.
@@ -37,6 +39,37 @@ func TestVerify(t *testing.T) {
 	}
 }
 
+func TestVerifyEmptyToken(t *testing.T) {
+	for _, token := range []string{"", " ", "\n", " \t\r\n"} {
+		if _, err := Verify(testAlg, testSecret, []byte(token)); err != ErrEmptyToken {
+			t.Fatalf("%q: expected error: %v but got: %v", token, ErrEmptyToken, err)
+		}
+
+		if _, err := Decode([]byte(token)); err != ErrEmptyToken {
+			t.Fatalf("%q: expected decode error: %v but got: %v", token, ErrEmptyToken, err)
+		}
+
+		if _, err := VerifyUnencoded(testAlg, testSecret, []byte(token), nil); !errors.Is(err, ErrMissing) {
+			t.Fatalf("%q: expected unencoded error to be an: %v but got: %v", token, ErrMissing, err)
+		}
+	}
+
+	// The surrounding whitespace is trimmed.
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, append(append([]byte(" "), token...), '\n'))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(verifiedToken.Token, token) {
+		t.Fatalf("expected the trimmed token:\n%s\nbut got:\n%q", token, verifiedToken.Token)
+	}
+}
+
 func TestPlainTokenValidator(t *testing.T) {
 	payload := []byte("test raw\ncontents")
 	token, err := Sign(testAlg, testSecret, payload)
@@ -125,3 +158,217 @@ func TestVerifyWithSingleAudienceString_CustomClaimsAndStandard(t *testing.T) {
 		t.Fatalf("expected:\n%#+v\n\nbut got:\n%#+v", standardClaims, gotStandard)
 	}
 }
+
+func TestVerifyToken(t *testing.T) {
+	type user struct {
+		Username string   `json:"username"`
+		Email    string   `json:"email,omitempty"`
+		Roles    []string `json:"roles,omitempty"`
+		Age      int      `json:"age,omitempty"`
+	}
+
+	for _, expected := range []user{
+		{Username: "kataras", Email: "kataras2006@hotmail.com", Roles: []string{"admin"}, Age: 27},
+		{Username: "kataras"}, // omitempty fields.
+	} {
+		token, err := Sign(testAlg, testSecret, expected, MaxAge(time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		var got user
+		if err = VerifyToken(testAlg, testSecret, token, &got); err != nil {
+			t.Fatal(err)
+		}
+
+		if !reflect.DeepEqual(expected, got) {
+			t.Fatalf("expected claims:\n%#+v\n\nbut got:\n%#+v", expected, got)
+		}
+
+		// Test nil destination, validation only.
+		if err = VerifyToken(testAlg, testSecret, token, nil, ExpectIssuer("other")); !errors.Is(err, ErrInvalidIssuer) {
+			t.Fatalf("expected error: %v but got: %v", ErrInvalidIssuer, err)
+		}
+	}
+}
+
+func TestVerifyTokenRaw(t *testing.T) {
+	type user struct {
+		Username string `json:"username"`
+	}
+
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras", "tenant": "acme"}, MaxAge(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var got user
+	payload, err := VerifyTokenRaw(testAlg, testSecret, token, &got)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got.Username != "kataras" {
+		t.Fatalf("expected username: kataras but got: %q", got.Username)
+	}
+
+	// The claims the struct does not capture are kept.
+	var extra struct {
+		Tenant string `json:"tenant"`
+	}
+	if err = json.Unmarshal(payload, &extra); err != nil {
+		t.Fatal(err)
+	}
+
+	if extra.Tenant != "acme" {
+		t.Fatalf("expected tenant: acme but got: %q", extra.Tenant)
+	}
+
+	verifiedToken, err := Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if !bytes.Equal(payload, verifiedToken.Payload) {
+		t.Fatalf("expected payload:\n%s\nbut got:\n%s", verifiedToken.Payload, payload)
+	}
+
+	if _, err = VerifyTokenRaw(testAlg, testSecret, token, nil, ExpectIssuer("other")); !errors.Is(err, ErrInvalidIssuer) {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidIssuer, err)
+	}
+}
+
+func TestVerifyTokenValidationError(t *testing.T) {
+	prevClock := Clock
+	defer func() {
+		Clock = prevClock
+	}()
+
+	now := time.Date(2020, 10, 26, 1, 1, 1, 0, time.UTC)
+	Clock = func() time.Time {
+		return now
+	}
+
+	expiry := now.Add(-time.Minute)
+	token, err := Sign(testAlg, testSecret, Claims{Expiry: expiry.Unix(), Audience: []string{"other"}})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// Verify keeps the sentinel error as it's.
+	if _, err = Verify(testAlg, testSecret, token); err != ErrExpired {
+		t.Fatalf("expected error: %v but got: %v", ErrExpired, err)
+	}
+
+	err = VerifyToken(testAlg, testSecret, token, nil)
+	if !errors.Is(err, ErrExpired) {
+		t.Fatalf("expected error: %v but got: %v", ErrExpired, err)
+	}
+
+	var vErr *ValidationError
+	if !errors.As(err, &vErr) {
+		t.Fatalf("expected a ValidationError but got: %#+v", err)
+	}
+
+	if vErr.Claim != "exp" || !vErr.Expiry.Equal(expiry) || !vErr.Now.Equal(now) {
+		t.Fatalf("unexpected validation error details: %#+v", vErr)
+	}
+
+	err = VerifyToken(testAlg, testSecret, token, nil, ClockSkew(30*time.Second))
+	if !errors.As(err, &vErr) || vErr.Skew != 30*time.Second {
+		t.Fatalf("expected a ValidationError with the skew but got: %#+v", err)
+	}
+
+	err = VerifyToken(testAlg, testSecret, token, nil, ClockSkew(2*time.Minute), ExpectAudience("my-service"))
+	if !errors.Is(err, ErrInvalidAudience) || !errors.Is(err, ErrExpected) {
+		t.Fatalf("expected error: %v but got: %v", ErrInvalidAudience, err)
+	}
+
+	if !errors.As(err, &vErr) || vErr.ExpectedAudience != "my-service" || !reflect.DeepEqual(vErr.ActualAudience, []string{"other"}) {
+		t.Fatalf("expected a ValidationError with the audiences but got: %#+v", err)
+	}
+
+	if expected, got := `jwt: field not match: aud: expected: "my-service", got: ["other"]`, err.Error(); expected != got {
+		t.Fatalf("expected error message: %s but got: %s", expected, got)
+	}
+}
+
+func TestVerifiedTokenTimeUntilExpiry(t *testing.T) {
+	now := time.Unix(Clock().Unix(), 0) // round to seconds, as the claims are.
+
+	var tests = []struct {
+		claims    Claims
+		expected  time.Duration
+		hasExpiry bool
+	}{
+		{Claims{Expiry: now.Add(90 * time.Second).Unix()}, 90 * time.Second, true},
+		{Claims{Expiry: now.Add(-time.Minute).Unix()}, -time.Minute, true}, // passes under the clock skew.
+		{Claims{Subject: "kataras"}, 0, false},
+	}
+
+	for i, tt := range tests {
+		token, err := Sign(testAlg, testSecret, tt.claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		verifiedToken, err := Verify(testAlg, testSecret, token, ClockSkew(2*time.Minute))
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		got, hasExpiry := verifiedToken.TimeUntilExpiry(now)
+		if got != tt.expected || hasExpiry != tt.hasExpiry {
+			t.Fatalf("[%d] expected: %s (%v) but got: %s (%v)", i, tt.expected, tt.hasExpiry, got, hasExpiry)
+		}
+	}
+}
+
+func TestVerifiedKID(t *testing.T) {
+	keys := make(Keys)
+	keys.Register(testAlg, "api", testSecret, testSecret)
+
+	token, err := keys.SignToken("api", Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err := VerifyWithHeaderValidator(nil, nil, token, keys.ValidateHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifiedToken.VerifiedKID != "api" {
+		t.Fatalf("expected verified kid: %q but got: %q", "api", verifiedToken.VerifiedKID)
+	}
+
+	// A token without a kid.
+	token, err = Sign(testAlg, testSecret, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err = Verify(testAlg, testSecret, token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifiedToken.VerifiedKID != "" {
+		t.Fatalf("expected an empty verified kid but got: %q", verifiedToken.VerifiedKID)
+	}
+
+	// An escaped field name.
+	token, err = SignWithHeader(testAlg, testSecret, Map{"username": "kataras"}, []byte(`{"alg":"HS256","\u006bid":"api"}`))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	verifiedToken, err = VerifyWithHeaderValidator(nil, nil, token, keys.ValidateHeader)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if verifiedToken.VerifiedKID != "api" {
+		t.Fatalf("expected verified kid: %q but got: %q", "api", verifiedToken.VerifiedKID)
+	}
+}
@@ -0,0 +1,72 @@
+package jwt
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"fmt"
+)
+
+// ErrCertThumbprintMismatch indicates that the token's "x5t#S256" header field
+// does not match the thumbprint of the expected certificate, see `ExpectCertThumbprint`.
+// It is an ErrExpected too.
+var ErrCertThumbprintMismatch = fmt.Errorf("%w: x5t#S256", ErrExpected)
+
+// CertThumbprint returns the X.509 certificate SHA-256 thumbprint
+// of the "x5t#S256" header field (RFC 7515, section 4.1.8):
+// the base64url-encoded SHA-256 digest of the DER encoding of the certificate.
+func CertThumbprint(cert *x509.Certificate) string {
+	digest := sha256.Sum256(cert.Raw)
+	return string(Base64Encode(digest[:]))
+}
+
+// WithCertThumbprint is a SignOption which sets the "x5t#S256" header field
+// to the thumbprint of the signing certificate, so the verifier can bind the token
+// to the certificate presented, e.g. on mutual TLS.
+// See the `ExpectCertThumbprint` TokenValidator too.
+//
+// Usage:
+//  token, err := jwt.Sign(jwt.EdDSA, privateKey, claims, jwt.WithCertThumbprint(cert))
+func WithCertThumbprint(cert *x509.Certificate) HeaderSignOption {
+	return func(h *Header) error {
+		if cert == nil {
+			return fmt.Errorf("jwt: header: %q: missing certificate", "x5t#S256")
+		}
+
+		return h.set("x5t#S256", CertThumbprint(cert))
+	}
+}
+
+// ExpectCertThumbprint is a TokenValidator which makes sure that
+// the token's "x5t#S256" header field matches the thumbprint of the given certificate,
+// e.g. the client certificate of a mutual TLS connection.
+// A token without a "x5t#S256" header field fails.
+//
+// It returns ErrCertThumbprintMismatch on validation failure.
+// See the `WithCertThumbprint` SignOption too.
+//
+// Usage:
+//  cert := r.TLS.PeerCertificates[0]
+//  verifiedToken, err := Verify(..., ExpectCertThumbprint(cert))
+func ExpectCertThumbprint(cert *x509.Certificate) TokenValidatorFunc {
+	var expected string
+	if cert != nil {
+		expected = CertThumbprint(cert)
+	}
+
+	return func(token []byte, _ Claims, err error) error {
+		if err != nil {
+			return err
+		}
+
+		header, headerErr := DecodeHeader(token) // the token is already verified.
+		if headerErr != nil {
+			return headerErr
+		}
+
+		if thumbprint, _ := header.Get("x5t#S256").(string); expected == "" || thumbprint != expected {
+			return ErrCertThumbprintMismatch
+		}
+
+		return nil
+	}
+}
@@ -0,0 +1,81 @@
+package jwt
+
+import (
+	"crypto/x509"
+	"encoding/pem"
+	"errors"
+	"os"
+	"testing"
+)
+
+func loadTestCert(t *testing.T, filename string) *x509.Certificate {
+	t.Helper()
+
+	b, err := os.ReadFile(filename)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	block, _ := pem.Decode(b)
+	if block == nil {
+		t.Fatalf("%s: %v", filename, ErrPEMBlockNotFound)
+	}
+
+	cert, err := x509.ParseCertificate(block.Bytes)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert
+}
+
+func TestCertThumbprint(t *testing.T) {
+	cert := loadTestCert(t, "./_testfiles/ed25519_cert.pem")
+	otherCert := loadTestCert(t, "./_testfiles/ecdsa_cert.pem")
+
+	// openssl x509 -in ed25519_cert.pem -outform DER | openssl dgst -sha256 -binary | basenc --base64url
+	expectedThumbprint := "JbQqpYcBg-v9oVOX5_s-EdRApZP0sdU1dqhSS58PBg0"
+	if got := CertThumbprint(cert); got != expectedThumbprint {
+		t.Fatalf("expected thumbprint: %s but got: %s", expectedThumbprint, got)
+	}
+
+	token, err := Sign(testAlg, testSecret, Map{"username": "kataras"}, WithCertThumbprint(cert))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	header, err := DecodeHeader(token)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if got := header.Get("x5t#S256"); got != expectedThumbprint {
+		t.Fatalf("expected x5t#S256 header: %s but got: %v", expectedThumbprint, got)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, ExpectCertThumbprint(cert)); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, ExpectCertThumbprint(otherCert)); !errors.Is(err, ErrCertThumbprintMismatch) {
+		t.Fatalf("expected error: %v but got: %v", ErrCertThumbprintMismatch, err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, ExpectCertThumbprint(nil)); !errors.Is(err, ErrCertThumbprintMismatch) {
+		t.Fatalf("expected error: %v but got: %v", ErrCertThumbprintMismatch, err)
+	}
+
+	// a token without the header field.
+	token, err = Sign(testAlg, testSecret, Map{"username": "kataras"})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err = Verify(testAlg, testSecret, token, ExpectCertThumbprint(cert)); !errors.Is(err, ErrCertThumbprintMismatch) {
+		t.Fatalf("expected error: %v but got: %v", ErrCertThumbprintMismatch, err)
+	}
+
+	if _, err = Sign(testAlg, testSecret, Map{"username": "kataras"}, WithCertThumbprint(nil)); err == nil {
+		t.Fatalf("expected an error on a nil certificate")
+	}
+}